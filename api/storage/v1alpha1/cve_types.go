@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CVEList contains a list of CVE
+type CVEList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CVE `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=get,list
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster
+
+// CVE lists the images affected by a vulnerability.
+// It is a read-only resource computed from the VulnerabilityReports, named after the CVE identifier.
+type CVE struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Title is the title of the vulnerability
+	Title string `json:"title,omitempty"`
+
+	// Description of the vulnerability
+	Description string `json:"description,omitempty"`
+
+	// References contains URLs for more information
+	References []string `json:"references,omitempty"`
+
+	// CVSS scoring details
+	CVSS map[string]CVSS `json:"cvss,omitempty"`
+
+	// AffectedImages lists the vulnerable packages found in the images
+	AffectedImages []AffectedImage `json:"affectedImages"`
+}
+
+// AffectedImage is a vulnerable package found in an image
+type AffectedImage struct {
+	// Namespace of the VulnerabilityReport
+	Namespace string `json:"namespace"`
+
+	// VulnerabilityReport is the name of the VulnerabilityReport of the image
+	VulnerabilityReport string `json:"vulnerabilityReport"`
+
+	// ImageMetadata contains info about the image
+	ImageMetadata ImageMetadata `json:"imageMetadata"`
+
+	// PackageName is the name of the vulnerable package
+	// (empty when Class is "binary")
+	PackageName string `json:"packageName,omitempty"`
+
+	// PackagePath is the path where the package was found
+	PackagePath string `json:"packagePath,omitempty"`
+
+	// PURL (Package URL) identify the package uniquely
+	PURL string `json:"purl"`
+
+	// InstalledVersion of the package that was found
+	InstalledVersion string `json:"installedVersion"`
+
+	// FixedVersions is the list of versions where the vulnerability is fixed
+	FixedVersions []string `json:"fixedVersions,omitempty"`
+
+	// Severity rating (e.g., "HIGH", "MEDIUM")
+	Severity string `json:"severity"`
+
+	// Suppressed identify when vulnerability has
+	// been suppressed by VEX documents
+	Suppressed bool `json:"suppressed"`
+}
@@ -3,6 +3,9 @@ package v1alpha1
 // IndexImageMetadataRegistry is the field index for the registry of an image.
 const IndexImageMetadataRegistry = "imageMetadata.registry"
 
+// IndexImageMetadataRepository is the field index for the repository of an image.
+const IndexImageMetadataRepository = "imageMetadata.repository"
+
 // ImageMetadata contains the metadata details of an image.
 type ImageMetadata struct {
 	// Registry specifies the name of the Registry object in the same namespace where the image is stored.
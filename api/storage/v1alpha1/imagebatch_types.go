@@ -0,0 +1,62 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxImageBatchSize is the maximum number of images of an ImageBatch.
+const MaxImageBatchSize = 1000
+
+// +genclient
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageBatch creates or updates a batch of Images of its namespace in a single transaction.
+// It is a create-only resource: the created object is not persisted
+// and the number of written images is returned in its status.
+type ImageBatch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the images to write
+	Spec ImageBatchSpec `json:"spec"`
+
+	// Status holds the result of the write
+	Status ImageBatchStatus `json:"status,omitempty"`
+}
+
+// ImageBatchSpec defines the images to write.
+type ImageBatchSpec struct {
+	// Images are the images to create, or to update if they exist.
+	// They must be in the namespace of the batch and have a name.
+	// At most MaxImageBatchSize images can be written by a batch.
+	Images []Image `json:"images"`
+}
+
+// ImageBatchStatus holds the result of the write.
+type ImageBatchStatus struct {
+	// Created is the number of images created
+	Created int `json:"created"`
+
+	// Updated is the number of existing images updated
+	Updated int `json:"updated"`
+
+	// Unchanged is the number of existing images left as they were
+	Unchanged int `json:"unchanged"`
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster
+
+// PackageSearch searches the packages listed in the SBOMs.
+// It is a create-only resource: the created object is not persisted
+// and the matching packages are returned in its status.
+type PackageSearch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the search criteria
+	Spec PackageSearchSpec `json:"spec"`
+
+	// Status holds the result of the search
+	Status PackageSearchStatus `json:"status,omitempty"`
+}
+
+// PackageSearchSpec defines the search criteria.
+// At least one of Name and PURLPrefix must be set.
+type PackageSearchSpec struct {
+	// Namespace restricts the search to the images of the namespace.
+	// All the namespaces are searched if empty.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the exact name of the package
+	Name string `json:"name,omitempty"`
+
+	// PURLPrefix is a prefix of the PURL (Package URL) of the package.
+	// Example: "pkg:maven/org.apache.logging.log4j/log4j-core"
+	PURLPrefix string `json:"purlPrefix,omitempty"`
+
+	// VersionConstraint is a constraint on the version of the package.
+	// Example: "< 2.17", ">= 5.6.0, < 5.6.2".
+	// Packages whose version is not a semantic version do not match any constraint.
+	VersionConstraint string `json:"versionConstraint,omitempty"`
+
+	// Limit is the maximum number of matches to return.
+	// All the matches are returned if zero.
+	Limit int64 `json:"limit,omitempty"`
+
+	// Continue is the continue token of the status of a previous search with the same criteria,
+	// to return the matches following the ones it returned.
+	Continue string `json:"continue,omitempty"`
+}
+
+// PackageSearchStatus holds the result of the search.
+type PackageSearchStatus struct {
+	// Matches lists the packages matching the criteria
+	Matches []PackageMatch `json:"matches"`
+
+	// Continue is set if there are more matches than the limit.
+	// The search is continued by setting it in the spec of a new search.
+	Continue string `json:"continue,omitempty"`
+}
+
+// PackageMatch is a package found in an image
+type PackageMatch struct {
+	// Namespace of the image
+	Namespace string `json:"namespace"`
+
+	// Image is the name of the SBOM listing the package,
+	// which is the name of the Image and of its VulnerabilityReport too
+	Image string `json:"image"`
+
+	// ImageMetadata contains info about the image
+	ImageMetadata ImageMetadata `json:"imageMetadata"`
+
+	// PackageName is the name of the package
+	PackageName string `json:"packageName"`
+
+	// Version of the package
+	Version string `json:"version"`
+
+	// PURL (Package URL) identify the package uniquely
+	PURL string `json:"purl,omitempty"`
+}
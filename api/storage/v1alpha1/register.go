@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strconv"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -59,6 +60,27 @@ func AddKnownTypes(scheme *runtime.Scheme) error {
 		&VulnerabilityReport{},
 		&VulnerabilityReportList{},
 
+		&VulnerabilitySummary{},
+		&VulnerabilitySummaryList{},
+
+		&VulnerabilityHistory{},
+		&VulnerabilityHistoryList{},
+
+		&VulnerabilityRollup{},
+		&VulnerabilityRollupList{},
+
+		&ClusterVulnerabilityRollup{},
+		&ClusterVulnerabilityRollupList{},
+
+		&CVE{},
+		&CVEList{},
+
+		&PackageSearch{},
+
+		&SBOMComparison{},
+
+		&ImageBatch{},
+
 		&metav1.GetOptions{},
 		&metav1.CreateOptions{},
 		&metav1.UpdateOptions{},
@@ -81,11 +103,32 @@ func AddKnownTypes(scheme *runtime.Scheme) error {
 
 	err = scheme.AddFieldLabelConversionFunc(
 		SchemeGroupVersion.WithKind("VulnerabilityReport"),
-		imageMetadataFieldSelectorConversion,
+		vulnerabilityReportFieldSelectorConversion,
 	)
 	if err != nil {
 		return fmt.Errorf("unable to add field selector conversion function to VulnerabilityReport: %w", err)
 	}
+
+	err = scheme.AddFieldLabelConversionFunc(
+		SchemeGroupVersion.WithKind("VulnerabilitySummary"),
+		imageMetadataFieldSelectorConversion,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to add field selector conversion function to VulnerabilitySummary: %w", err)
+	}
+
+	err = scheme.AddFieldLabelConversionFunc(
+		SchemeGroupVersion.WithKind("VulnerabilityHistory"),
+		imageMetadataFieldSelectorConversion,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to add field selector conversion function to VulnerabilityHistory: %w", err)
+	}
+
+	err = scheme.AddFieldLabelConversionFunc(SchemeGroupVersion.WithKind("CVE"), cveFieldSelectorConversion)
+	if err != nil {
+		return fmt.Errorf("unable to add field selector conversion function to CVE: %w", err)
+	}
 	return nil
 }
 
@@ -117,3 +160,54 @@ func imageMetadataFieldSelectorConversion(label, value string) (string, string,
 		)
 	}
 }
+
+// vulnerabilityReportFieldSelectorConversion accepts the imageMetadata fields
+// and the vulnerability counts of the summary, whose values must be integers.
+func vulnerabilityReportFieldSelectorConversion(label, value string) (string, string, error) {
+	switch label {
+	case "report.summary.critical",
+		"report.summary.high",
+		"report.summary.medium",
+		"report.summary.low",
+		"report.summary.unknown",
+		"report.summary.suppressed":
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return "", "", fmt.Errorf("the value of the %q field selector must be an integer: %q", label, value)
+		}
+		// The value is normalized, as the watch filters compare the fields as strings.
+		return label, strconv.Itoa(count), nil
+	default:
+		_, _, err := imageMetadataFieldSelectorConversion(label, value)
+		if err != nil {
+			return "", "", fmt.Errorf(
+				"%q is not a known field selector: only %q, %q, %q, %q",
+				label,
+				"metadata.name",
+				"metadata.namespace",
+				"imageMetadata.*",
+				"report.summary.*",
+			)
+		}
+		return label, value, nil
+	}
+}
+
+func cveFieldSelectorConversion(label, value string) (string, string, error) {
+	switch label {
+	case "metadata.name":
+		return label, value, nil
+	case "affectedImages.namespace":
+		return label, value, nil
+	case "affectedImages.severity":
+		return label, value, nil
+	default:
+		return "", "", fmt.Errorf(
+			"%q is not a known field selector: only %q, %q, %q",
+			label,
+			"metadata.name",
+			"affectedImages.namespace",
+			"affectedImages.severity",
+		)
+	}
+}
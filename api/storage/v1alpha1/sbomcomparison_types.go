@@ -0,0 +1,88 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SBOMComparison compares the packages of two SBOMs of its namespace,
+// such as the SBOMs of two digests of a tag or of two tags of a repository.
+// It is a create-only resource: the created object is not persisted
+// and the package changes are returned in its status.
+type SBOMComparison struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the SBOMs to compare
+	Spec SBOMComparisonSpec `json:"spec"`
+
+	// Status holds the result of the comparison
+	Status SBOMComparisonStatus `json:"status,omitempty"`
+}
+
+// SBOMComparisonSpec defines the SBOMs to compare.
+type SBOMComparisonSpec struct {
+	// Base is the name of the SBOM the packages are compared from, usually the one of the older image
+	Base string `json:"base"`
+
+	// Target is the name of the SBOM the packages are compared to
+	Target string `json:"target"`
+}
+
+// SBOMComparisonStatus holds the packages that changed from the base SBOM to the target one.
+// A package is identified by its name and its PURL without the version and the qualifiers.
+// The packages are sorted by name and PURL.
+type SBOMComparisonStatus struct {
+	// Base contains info about the image of the base SBOM
+	Base ImageMetadata `json:"base"`
+
+	// Target contains info about the image of the target SBOM
+	Target ImageMetadata `json:"target"`
+
+	// Added lists the packages of the target SBOM missing from the base one
+	Added []PackageChange `json:"added,omitempty"`
+
+	// Removed lists the packages of the base SBOM missing from the target one
+	Removed []PackageChange `json:"removed,omitempty"`
+
+	// Upgraded lists the packages whose version is higher in the target SBOM
+	Upgraded []PackageChange `json:"upgraded,omitempty"`
+
+	// Downgraded lists the packages whose version is lower in the target SBOM
+	Downgraded []PackageChange `json:"downgraded,omitempty"`
+}
+
+// PackageChange is a package that changed from the base SBOM to the target one.
+type PackageChange struct {
+	// Name is the name of the package
+	Name string `json:"name"`
+
+	// PURL (Package URL) identify the package uniquely,
+	// as listed by the target SBOM, or by the base one for the removed packages
+	PURL string `json:"purl,omitempty"`
+
+	// Version of the package in the target SBOM, unset for the removed packages
+	Version string `json:"version,omitempty"`
+
+	// PreviousVersion is the version of the package in the base SBOM, unset for the added packages
+	PreviousVersion string `json:"previousVersion,omitempty"`
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VulnerabilityHistoryList contains a list of VulnerabilityHistory
+type VulnerabilityHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VulnerabilityHistory `json:"items"`
+}
+
+// +genclient
+// +genclient:onlyVerbs=get,list,watch
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.registry`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.registryURI`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.repository`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.tag`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.platform`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.digest`
+
+// VulnerabilityHistory is the read-only timeline of the vulnerabilities found in the image digest of a VulnerabilityReport.
+// It has the same name and namespace as the report, and is kept across the rescans overwriting the report.
+type VulnerabilityHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// ImageMetadata contains info about the scanned image
+	ImageMetadata ImageMetadata `json:"imageMetadata"`
+
+	// Timeline lists the vulnerabilities ever found in the image digest, ordered by the time they were first seen
+	Timeline []VulnerabilityTimelineEntry `json:"timeline"`
+}
+
+// VulnerabilityTimelineEntry records when a vulnerability of a package was found in an image digest.
+type VulnerabilityTimelineEntry struct {
+	// CVE identifier
+	CVE string `json:"cve"`
+
+	// PackageName is the name of the vulnerable package
+	PackageName string `json:"packageName,omitempty"`
+
+	// PURL (Package URL) identify the package uniquely
+	PURL string `json:"purl"`
+
+	// Severity rating reported by the latest scan finding the vulnerability
+	Severity string `json:"severity"`
+
+	// FirstSeen is the time of the first scan finding the vulnerability
+	FirstSeen metav1.Time `json:"firstSeen"`
+
+	// LastSeen is the time of the latest scan finding the vulnerability
+	LastSeen metav1.Time `json:"lastSeen"`
+
+	// ResolvedAt is the time of the first scan of the same image digest no longer finding the vulnerability,
+	// unset while the vulnerability is found. A vulnerability fixed by a new digest of the image
+	// is not resolved in the timeline of the previous digest.
+	ResolvedAt *metav1.Time `json:"resolvedAt,omitempty"`
+}
+
+func (v *VulnerabilityHistory) GetImageMetadata() ImageMetadata {
+	return v.ImageMetadata
+}
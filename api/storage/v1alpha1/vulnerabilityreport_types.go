@@ -60,6 +60,55 @@ type VulnerabilityReport struct {
 
 	// Report is the actual vulnerability scan report
 	Report Report `json:"report"`
+
+	// LastChange is the difference between the report and the report of the previous scan of the image.
+	// It is unset until the image is scanned again.
+	LastChange *ReportChange `json:"lastChange,omitempty"`
+}
+
+// ReportChange lists the vulnerabilities that changed between two scans of an image.
+// A vulnerability is identified by its CVE, package name and PURL.
+type ReportChange struct {
+	// Added lists the vulnerabilities found by the scan and not by the previous one
+	Added []VulnerabilityChange `json:"added,omitempty"`
+
+	// Resolved lists the vulnerabilities found by the previous scan and no longer by the scan
+	Resolved []VulnerabilityChange `json:"resolved,omitempty"`
+
+	// Changed lists the vulnerabilities found by both scans, whose severity or VEX status changed
+	Changed []VulnerabilityChange `json:"changed,omitempty"`
+}
+
+// VulnerabilityChange describes a vulnerability that changed between two scans of an image.
+type VulnerabilityChange struct {
+	// CVE identifier
+	CVE string `json:"cve"`
+
+	// PackageName is the name of the vulnerable package
+	PackageName string `json:"packageName,omitempty"`
+
+	// PURL (Package URL) identify the package uniquely
+	PURL string `json:"purl"`
+
+	// Severity rating of the vulnerability,
+	// as found by the previous scan for the resolved vulnerabilities
+	Severity string `json:"severity"`
+
+	// Suppressed identify when vulnerability has
+	// been suppressed by VEX documents
+	Suppressed bool `json:"suppressed"`
+
+	// VEXStatus information
+	VEXStatus *VEXStatus `json:"vexStatus,omitempty"`
+
+	// PreviousSeverity is the severity rating found by the previous scan, set for the changed vulnerabilities
+	PreviousSeverity string `json:"previousSeverity,omitempty"`
+
+	// PreviousSuppressed is the suppression found by the previous scan, set for the changed vulnerabilities
+	PreviousSuppressed bool `json:"previousSuppressed,omitempty"`
+
+	// PreviousVEXStatus is the VEX status found by the previous scan, set for the changed vulnerabilities
+	PreviousVEXStatus *VEXStatus `json:"previousVEXStatus,omitempty"`
 }
 
 // Report contains metadata about the scanned image and a list of vulnerability results.
@@ -91,6 +140,17 @@ type Summary struct {
 	Suppressed int `json:"suppressed"`
 }
 
+// The labels set by the storage on the VulnerabilityReports to the counts of their summary,
+// so that the reports can be selected by their number of vulnerabilities with the > and < label selector operators.
+const (
+	LabelSummaryCriticalKey   = "sbomscanner.kubewarden.io/summary-critical"
+	LabelSummaryHighKey       = "sbomscanner.kubewarden.io/summary-high"
+	LabelSummaryMediumKey     = "sbomscanner.kubewarden.io/summary-medium"
+	LabelSummaryLowKey        = "sbomscanner.kubewarden.io/summary-low"
+	LabelSummaryUnknownKey    = "sbomscanner.kubewarden.io/summary-unknown"
+	LabelSummarySuppressedKey = "sbomscanner.kubewarden.io/summary-suppressed"
+)
+
 // Result represents scan findings for a specific target and class of packages
 type Result struct {
 	// Target is the specific target scanned
@@ -177,6 +237,10 @@ type Vulnerability struct {
 
 	// VEXStatus information
 	VEXStatus *VEXStatus `json:"vexStatus,omitempty"`
+
+	// FirstSeen is the time the vulnerability was first found in the image digest.
+	// It is set by the storage and kept across the rescans of the image.
+	FirstSeen *metav1.Time `json:"firstSeen,omitempty"`
 }
 
 func (v *VulnerabilityReport) GetImageMetadata() ImageMetadata {
@@ -0,0 +1,119 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VulnerabilityRollupList contains a list of VulnerabilityRollup
+type VulnerabilityRollupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VulnerabilityRollup `json:"items"`
+}
+
+// +genclient
+// +genclient:onlyVerbs=get,list
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VulnerabilityRollup aggregates the VulnerabilityReports of the images of a registry.
+// It is a read-only resource computed from the VulnerabilityReports,
+// named after the Registry and living in its namespace.
+type VulnerabilityRollup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Rollup holds the aggregated vulnerabilities
+	Rollup Rollup `json:"rollup"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterVulnerabilityRollupList contains a list of ClusterVulnerabilityRollup
+type ClusterVulnerabilityRollupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterVulnerabilityRollup `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=get,list
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster
+
+// ClusterVulnerabilityRollup aggregates the VulnerabilityReports of the images of a namespace.
+// It is a read-only resource computed from the VulnerabilityReports, named after the namespace.
+type ClusterVulnerabilityRollup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Rollup holds the aggregated vulnerabilities
+	Rollup Rollup `json:"rollup"`
+}
+
+// Rollup holds the vulnerabilities aggregated over a set of images.
+type Rollup struct {
+	// Images is the number of scanned images
+	Images int `json:"images"`
+
+	// Summary is the sum of the summaries of the VulnerabilityReports of the images
+	Summary Summary `json:"summary"`
+
+	// Fixable is the number of vulnerabilities, not suppressed, with a fixed version available
+	Fixable int `json:"fixable"`
+
+	// FixablePercentage is the percentage, rounded down, of the vulnerabilities not suppressed
+	// with a fixed version available
+	FixablePercentage int `json:"fixablePercentage"`
+
+	// TopCVEs lists the 10 CVEs, not suppressed, affecting the most images
+	TopCVEs []CVECount `json:"topCVEs"`
+
+	// MostVulnerableImages lists the 10 images with the most critical, then high, vulnerabilities
+	MostVulnerableImages []VulnerableImage `json:"mostVulnerableImages"`
+}
+
+// CVECount is the number of images affected by a CVE
+type CVECount struct {
+	// CVE identifier
+	CVE string `json:"cve"`
+
+	// Title is the title of the vulnerability
+	Title string `json:"title,omitempty"`
+
+	// Images is the number of affected images
+	Images int `json:"images"`
+}
+
+// VulnerableImage is the summary of the vulnerabilities of an image
+type VulnerableImage struct {
+	// Namespace of the VulnerabilityReport
+	Namespace string `json:"namespace"`
+
+	// VulnerabilityReport is the name of the VulnerabilityReport of the image
+	VulnerabilityReport string `json:"vulnerabilityReport"`
+
+	// ImageMetadata contains info about the image
+	ImageMetadata ImageMetadata `json:"imageMetadata"`
+
+	// Summary of vulnerabilities found
+	Summary Summary `json:"summary"`
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VulnerabilitySummaryList contains a list of VulnerabilitySummary
+type VulnerabilitySummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VulnerabilitySummary `json:"items"`
+}
+
+// +genclient
+// +genclient:onlyVerbs=get,list,watch
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.registry`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.registryURI`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.repository`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.tag`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.platform`
+// +kubebuilder:selectablefield:JSONPath=`.imageMetadata.digest`
+
+// VulnerabilitySummary is a read-only summary of a VulnerabilityReport.
+// It has the same name and namespace as the report, without the vulnerabilities.
+type VulnerabilitySummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// ImageMetadata contains info about the scanned image
+	ImageMetadata ImageMetadata `json:"imageMetadata"`
+
+	// Summary of vulnerabilities found
+	Summary Summary `json:"summary"`
+
+	// Fixable is the number of vulnerabilities, not suppressed, with a fixed version available
+	Fixable int `json:"fixable"`
+
+	// FirstScannedAt is the time the image was scanned for the first time
+	FirstScannedAt metav1.Time `json:"firstScannedAt"`
+
+	// LastScannedAt is the time the image was scanned for the last time
+	LastScannedAt metav1.Time `json:"lastScannedAt"`
+}
+
+func (v *VulnerabilitySummary) GetImageMetadata() ImageMetadata {
+	return v.ImageMetadata
+}
@@ -10,47 +10,66 @@ import (
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CVSS) DeepCopyInto(out *CVSS) {
+func (in *AffectedImage) DeepCopyInto(out *AffectedImage) {
 	*out = *in
+	out.ImageMetadata = in.ImageMetadata
+	if in.FixedVersions != nil {
+		in, out := &in.FixedVersions, &out.FixedVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVSS.
-func (in *CVSS) DeepCopy() *CVSS {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AffectedImage.
+func (in *AffectedImage) DeepCopy() *AffectedImage {
 	if in == nil {
 		return nil
 	}
-	out := new(CVSS)
+	out := new(AffectedImage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *CVE) DeepCopyInto(out *CVE) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.ImageMetadata = in.ImageMetadata
-	if in.Layers != nil {
-		in, out := &in.Layers, &out.Layers
-		*out = make([]ImageLayer, len(*in))
+	if in.References != nil {
+		in, out := &in.References, &out.References
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CVSS != nil {
+		in, out := &in.CVSS, &out.CVSS
+		*out = make(map[string]CVSS, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AffectedImages != nil {
+		in, out := &in.AffectedImages, &out.AffectedImages
+		*out = make([]AffectedImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
-func (in *Image) DeepCopy() *Image {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVE.
+func (in *CVE) DeepCopy() *CVE {
 	if in == nil {
 		return nil
 	}
-	out := new(Image)
+	out := new(CVE)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Image) DeepCopyObject() runtime.Object {
+func (in *CVE) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -58,29 +77,29 @@ func (in *Image) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageLayer) DeepCopyInto(out *ImageLayer) {
+func (in *CVECount) DeepCopyInto(out *CVECount) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLayer.
-func (in *ImageLayer) DeepCopy() *ImageLayer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVECount.
+func (in *CVECount) DeepCopy() *CVECount {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageLayer)
+	out := new(CVECount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageList) DeepCopyInto(out *ImageList) {
+func (in *CVEList) DeepCopyInto(out *CVEList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Image, len(*in))
+		*out = make([]CVE, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -88,18 +107,18 @@ func (in *ImageList) DeepCopyInto(out *ImageList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageList.
-func (in *ImageList) DeepCopy() *ImageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVEList.
+func (in *CVEList) DeepCopy() *CVEList {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageList)
+	out := new(CVEList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ImageList) DeepCopyObject() runtime.Object {
+func (in *CVEList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -107,51 +126,56 @@ func (in *ImageList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageMetadata) DeepCopyInto(out *ImageMetadata) {
+func (in *CVSS) DeepCopyInto(out *CVSS) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMetadata.
-func (in *ImageMetadata) DeepCopy() *ImageMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVSS.
+func (in *CVSS) DeepCopy() *CVSS {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageMetadata)
+	out := new(CVSS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Report) DeepCopyInto(out *Report) {
+func (in *ClusterVulnerabilityRollup) DeepCopyInto(out *ClusterVulnerabilityRollup) {
 	*out = *in
-	out.Summary = in.Summary
-	if in.Results != nil {
-		in, out := &in.Results, &out.Results
-		*out = make([]Result, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Rollup.DeepCopyInto(&out.Rollup)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Report.
-func (in *Report) DeepCopy() *Report {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVulnerabilityRollup.
+func (in *ClusterVulnerabilityRollup) DeepCopy() *ClusterVulnerabilityRollup {
 	if in == nil {
 		return nil
 	}
-	out := new(Report)
+	out := new(ClusterVulnerabilityRollup)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterVulnerabilityRollup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Result) DeepCopyInto(out *Result) {
+func (in *ClusterVulnerabilityRollupList) DeepCopyInto(out *ClusterVulnerabilityRollupList) {
 	*out = *in
-	if in.Vulnerabilities != nil {
-		in, out := &in.Vulnerabilities, &out.Vulnerabilities
-		*out = make([]Vulnerability, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterVulnerabilityRollup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -159,38 +183,50 @@ func (in *Result) DeepCopyInto(out *Result) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Result.
-func (in *Result) DeepCopy() *Result {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVulnerabilityRollupList.
+func (in *ClusterVulnerabilityRollupList) DeepCopy() *ClusterVulnerabilityRollupList {
 	if in == nil {
 		return nil
 	}
-	out := new(Result)
+	out := new(ClusterVulnerabilityRollupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterVulnerabilityRollupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SBOM) DeepCopyInto(out *SBOM) {
+func (in *Image) DeepCopyInto(out *Image) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.ImageMetadata = in.ImageMetadata
-	in.SPDX.DeepCopyInto(&out.SPDX)
+	if in.Layers != nil {
+		in, out := &in.Layers, &out.Layers
+		*out = make([]ImageLayer, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOM.
-func (in *SBOM) DeepCopy() *SBOM {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
+func (in *Image) DeepCopy() *Image {
 	if in == nil {
 		return nil
 	}
-	out := new(SBOM)
+	out := new(Image)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SBOM) DeepCopyObject() runtime.Object {
+func (in *Image) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -198,13 +234,96 @@ func (in *SBOM) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SBOMList) DeepCopyInto(out *SBOMList) {
+func (in *ImageBatch) DeepCopyInto(out *ImageBatch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBatch.
+func (in *ImageBatch) DeepCopy() *ImageBatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageBatch) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBatchSpec) DeepCopyInto(out *ImageBatchSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]Image, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBatchSpec.
+func (in *ImageBatchSpec) DeepCopy() *ImageBatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBatchStatus) DeepCopyInto(out *ImageBatchStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBatchStatus.
+func (in *ImageBatchStatus) DeepCopy() *ImageBatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageLayer) DeepCopyInto(out *ImageLayer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLayer.
+func (in *ImageLayer) DeepCopy() *ImageLayer {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageLayer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageList) DeepCopyInto(out *ImageList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SBOM, len(*in))
+		*out = make([]Image, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -212,18 +331,18 @@ func (in *SBOMList) DeepCopyInto(out *SBOMList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOMList.
-func (in *SBOMList) DeepCopy() *SBOMList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageList.
+func (in *ImageList) DeepCopy() *ImageList {
 	if in == nil {
 		return nil
 	}
-	out := new(SBOMList)
+	out := new(ImageList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SBOMList) DeepCopyObject() runtime.Object {
+func (in *ImageList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -231,97 +350,76 @@ func (in *SBOMList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Summary) DeepCopyInto(out *Summary) {
+func (in *ImageMetadata) DeepCopyInto(out *ImageMetadata) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Summary.
-func (in *Summary) DeepCopy() *Summary {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMetadata.
+func (in *ImageMetadata) DeepCopy() *ImageMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(Summary)
+	out := new(ImageMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VEXStatus) DeepCopyInto(out *VEXStatus) {
+func (in *PackageChange) DeepCopyInto(out *PackageChange) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VEXStatus.
-func (in *VEXStatus) DeepCopy() *VEXStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageChange.
+func (in *PackageChange) DeepCopy() *PackageChange {
 	if in == nil {
 		return nil
 	}
-	out := new(VEXStatus)
+	out := new(PackageChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Vulnerability) DeepCopyInto(out *Vulnerability) {
+func (in *PackageMatch) DeepCopyInto(out *PackageMatch) {
 	*out = *in
-	if in.FixedVersions != nil {
-		in, out := &in.FixedVersions, &out.FixedVersions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.References != nil {
-		in, out := &in.References, &out.References
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.CVSS != nil {
-		in, out := &in.CVSS, &out.CVSS
-		*out = make(map[string]CVSS, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.VEXStatus != nil {
-		in, out := &in.VEXStatus, &out.VEXStatus
-		*out = new(VEXStatus)
-		**out = **in
-	}
+	out.ImageMetadata = in.ImageMetadata
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Vulnerability.
-func (in *Vulnerability) DeepCopy() *Vulnerability {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageMatch.
+func (in *PackageMatch) DeepCopy() *PackageMatch {
 	if in == nil {
 		return nil
 	}
-	out := new(Vulnerability)
+	out := new(PackageMatch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VulnerabilityReport) DeepCopyInto(out *VulnerabilityReport) {
+func (in *PackageSearch) DeepCopyInto(out *PackageSearch) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.ImageMetadata = in.ImageMetadata
-	in.Report.DeepCopyInto(&out.Report)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityReport.
-func (in *VulnerabilityReport) DeepCopy() *VulnerabilityReport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSearch.
+func (in *PackageSearch) DeepCopy() *PackageSearch {
 	if in == nil {
 		return nil
 	}
-	out := new(VulnerabilityReport)
+	out := new(PackageSearch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VulnerabilityReport) DeepCopyObject() runtime.Object {
+func (in *PackageSearch) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -329,34 +427,688 @@ func (in *VulnerabilityReport) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VulnerabilityReportList) DeepCopyInto(out *VulnerabilityReportList) {
+func (in *PackageSearchSpec) DeepCopyInto(out *PackageSearchSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VulnerabilityReport, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityReportList.
-func (in *VulnerabilityReportList) DeepCopy() *VulnerabilityReportList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSearchSpec.
+func (in *PackageSearchSpec) DeepCopy() *PackageSearchSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VulnerabilityReportList)
+	out := new(PackageSearchSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VulnerabilityReportList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageSearchStatus) DeepCopyInto(out *PackageSearchStatus) {
+	*out = *in
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]PackageMatch, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSearchStatus.
+func (in *PackageSearchStatus) DeepCopy() *PackageSearchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageSearchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Report) DeepCopyInto(out *Report) {
+	*out = *in
+	out.Summary = in.Summary
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]Result, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Report.
+func (in *Report) DeepCopy() *Report {
+	if in == nil {
+		return nil
+	}
+	out := new(Report)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportChange) DeepCopyInto(out *ReportChange) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]VulnerabilityChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resolved != nil {
+		in, out := &in.Resolved, &out.Resolved
+		*out = make([]VulnerabilityChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]VulnerabilityChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportChange.
+func (in *ReportChange) DeepCopy() *ReportChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Result) DeepCopyInto(out *Result) {
+	*out = *in
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = make([]Vulnerability, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Result.
+func (in *Result) DeepCopy() *Result {
+	if in == nil {
+		return nil
+	}
+	out := new(Result)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollup) DeepCopyInto(out *Rollup) {
+	*out = *in
+	out.Summary = in.Summary
+	if in.TopCVEs != nil {
+		in, out := &in.TopCVEs, &out.TopCVEs
+		*out = make([]CVECount, len(*in))
+		copy(*out, *in)
+	}
+	if in.MostVulnerableImages != nil {
+		in, out := &in.MostVulnerableImages, &out.MostVulnerableImages
+		*out = make([]VulnerableImage, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollup.
+func (in *Rollup) DeepCopy() *Rollup {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SBOM) DeepCopyInto(out *SBOM) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.ImageMetadata = in.ImageMetadata
+	in.SPDX.DeepCopyInto(&out.SPDX)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOM.
+func (in *SBOM) DeepCopy() *SBOM {
+	if in == nil {
+		return nil
+	}
+	out := new(SBOM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SBOM) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SBOMComparison) DeepCopyInto(out *SBOMComparison) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOMComparison.
+func (in *SBOMComparison) DeepCopy() *SBOMComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(SBOMComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SBOMComparison) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SBOMComparisonSpec) DeepCopyInto(out *SBOMComparisonSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOMComparisonSpec.
+func (in *SBOMComparisonSpec) DeepCopy() *SBOMComparisonSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SBOMComparisonSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SBOMComparisonStatus) DeepCopyInto(out *SBOMComparisonStatus) {
+	*out = *in
+	out.Base = in.Base
+	out.Target = in.Target
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]PackageChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]PackageChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgraded != nil {
+		in, out := &in.Upgraded, &out.Upgraded
+		*out = make([]PackageChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Downgraded != nil {
+		in, out := &in.Downgraded, &out.Downgraded
+		*out = make([]PackageChange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOMComparisonStatus.
+func (in *SBOMComparisonStatus) DeepCopy() *SBOMComparisonStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SBOMComparisonStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SBOMList) DeepCopyInto(out *SBOMList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SBOM, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOMList.
+func (in *SBOMList) DeepCopy() *SBOMList {
+	if in == nil {
+		return nil
+	}
+	out := new(SBOMList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SBOMList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Summary) DeepCopyInto(out *Summary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Summary.
+func (in *Summary) DeepCopy() *Summary {
+	if in == nil {
+		return nil
+	}
+	out := new(Summary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VEXStatus) DeepCopyInto(out *VEXStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VEXStatus.
+func (in *VEXStatus) DeepCopy() *VEXStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VEXStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Vulnerability) DeepCopyInto(out *Vulnerability) {
+	*out = *in
+	if in.FixedVersions != nil {
+		in, out := &in.FixedVersions, &out.FixedVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.References != nil {
+		in, out := &in.References, &out.References
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CVSS != nil {
+		in, out := &in.CVSS, &out.CVSS
+		*out = make(map[string]CVSS, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VEXStatus != nil {
+		in, out := &in.VEXStatus, &out.VEXStatus
+		*out = new(VEXStatus)
+		**out = **in
+	}
+	if in.FirstSeen != nil {
+		in, out := &in.FirstSeen, &out.FirstSeen
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Vulnerability.
+func (in *Vulnerability) DeepCopy() *Vulnerability {
+	if in == nil {
+		return nil
+	}
+	out := new(Vulnerability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityChange) DeepCopyInto(out *VulnerabilityChange) {
+	*out = *in
+	if in.VEXStatus != nil {
+		in, out := &in.VEXStatus, &out.VEXStatus
+		*out = new(VEXStatus)
+		**out = **in
+	}
+	if in.PreviousVEXStatus != nil {
+		in, out := &in.PreviousVEXStatus, &out.PreviousVEXStatus
+		*out = new(VEXStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityChange.
+func (in *VulnerabilityChange) DeepCopy() *VulnerabilityChange {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityHistory) DeepCopyInto(out *VulnerabilityHistory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.ImageMetadata = in.ImageMetadata
+	if in.Timeline != nil {
+		in, out := &in.Timeline, &out.Timeline
+		*out = make([]VulnerabilityTimelineEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityHistory.
+func (in *VulnerabilityHistory) DeepCopy() *VulnerabilityHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilityHistory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityHistoryList) DeepCopyInto(out *VulnerabilityHistoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VulnerabilityHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityHistoryList.
+func (in *VulnerabilityHistoryList) DeepCopy() *VulnerabilityHistoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityHistoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilityHistoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityReport) DeepCopyInto(out *VulnerabilityReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.ImageMetadata = in.ImageMetadata
+	in.Report.DeepCopyInto(&out.Report)
+	if in.LastChange != nil {
+		in, out := &in.LastChange, &out.LastChange
+		*out = new(ReportChange)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityReport.
+func (in *VulnerabilityReport) DeepCopy() *VulnerabilityReport {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilityReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityReportList) DeepCopyInto(out *VulnerabilityReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VulnerabilityReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityReportList.
+func (in *VulnerabilityReportList) DeepCopy() *VulnerabilityReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilityReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityRollup) DeepCopyInto(out *VulnerabilityRollup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Rollup.DeepCopyInto(&out.Rollup)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityRollup.
+func (in *VulnerabilityRollup) DeepCopy() *VulnerabilityRollup {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityRollup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilityRollup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityRollupList) DeepCopyInto(out *VulnerabilityRollupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VulnerabilityRollup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityRollupList.
+func (in *VulnerabilityRollupList) DeepCopy() *VulnerabilityRollupList {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityRollupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilityRollupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilitySummary) DeepCopyInto(out *VulnerabilitySummary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.ImageMetadata = in.ImageMetadata
+	out.Summary = in.Summary
+	in.FirstScannedAt.DeepCopyInto(&out.FirstScannedAt)
+	in.LastScannedAt.DeepCopyInto(&out.LastScannedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilitySummary.
+func (in *VulnerabilitySummary) DeepCopy() *VulnerabilitySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilitySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilitySummary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilitySummaryList) DeepCopyInto(out *VulnerabilitySummaryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VulnerabilitySummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilitySummaryList.
+func (in *VulnerabilitySummaryList) DeepCopy() *VulnerabilitySummaryList {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilitySummaryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VulnerabilitySummaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityTimelineEntry) DeepCopyInto(out *VulnerabilityTimelineEntry) {
+	*out = *in
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+	if in.ResolvedAt != nil {
+		in, out := &in.ResolvedAt, &out.ResolvedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityTimelineEntry.
+func (in *VulnerabilityTimelineEntry) DeepCopy() *VulnerabilityTimelineEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityTimelineEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerableImage) DeepCopyInto(out *VulnerableImage) {
+	*out = *in
+	out.ImageMetadata = in.ImageMetadata
+	out.Summary = in.Summary
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerableImage.
+func (in *VulnerableImage) DeepCopy() *VulnerableImage {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerableImage)
+	in.DeepCopyInto(out)
+	return out
 }
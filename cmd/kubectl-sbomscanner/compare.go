@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+	"github.com/kubewarden/sbomscanner/pkg/generated/clientset/versioned"
+)
+
+// newCompareCommand returns the command comparing the packages of two SBOMs.
+func newCompareCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	return &cobra.Command{
+		Use:   "compare BASE TARGET",
+		Short: "Show the packages added, removed, upgraded and downgraded from the BASE SBOM to the TARGET one",
+		Example: `  # Compare the SBOMs of two digests of a tag
+  kubectl sbomscanner compare -n my-namespace 1a2b3c 4d5e6f`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			namespace, _, err := clientConfig.Namespace()
+			if err != nil {
+				return fmt.Errorf("failed to get the namespace: %w", err)
+			}
+			restConfig, err := clientConfig.ClientConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load the kubeconfig: %w", err)
+			}
+			client, err := versioned.NewForConfig(restConfig)
+			if err != nil {
+				return fmt.Errorf("failed to create the storage client: %w", err)
+			}
+
+			comparison, err := client.StorageV1alpha1().SBOMComparisons(namespace).Create(
+				c.Context(),
+				&v1alpha1.SBOMComparison{Spec: v1alpha1.SBOMComparisonSpec{Base: args[0], Target: args[1]}},
+				metav1.CreateOptions{},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to compare the SBOMs: %w", err)
+			}
+
+			return printComparison(c.OutOrStdout(), comparison)
+		},
+	}
+}
+
+// printComparison writes the package changes of the comparison as a table, one line per package.
+func printComparison(out io.Writer, comparison *v1alpha1.SBOMComparison) error {
+	fmt.Fprintf(out, "BASE:   %s\n", imageReference(comparison.Status.Base))
+	fmt.Fprintf(out, "TARGET: %s\n\n", imageReference(comparison.Status.Target))
+
+	changes := len(comparison.Status.Added) + len(comparison.Status.Removed) +
+		len(comparison.Status.Upgraded) + len(comparison.Status.Downgraded)
+	if changes == 0 {
+		fmt.Fprintln(out, "No package changes.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tPACKAGE\tPREVIOUS VERSION\tVERSION\tPURL")
+	for _, group := range []struct {
+		change   string
+		packages []v1alpha1.PackageChange
+	}{
+		{"added", comparison.Status.Added},
+		{"removed", comparison.Status.Removed},
+		{"upgraded", comparison.Status.Upgraded},
+		{"downgraded", comparison.Status.Downgraded},
+	} {
+		for _, pkg := range group.packages {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", group.change, pkg.Name, pkg.PreviousVersion, pkg.Version, pkg.PURL)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write the comparison: %w", err)
+	}
+
+	return nil
+}
+
+// imageReference returns the reference of the image of an SBOM, with its tag and digest.
+func imageReference(image v1alpha1.ImageMetadata) string {
+	return fmt.Sprintf("%s/%s:%s@%s (%s)", image.RegistryURI, image.Repository, image.Tag, image.Digest, image.Platform)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func TestPrintComparison(t *testing.T) {
+	base := v1alpha1.ImageMetadata{
+		RegistryURI: "ghcr.io",
+		Repository:  "kubewarden/test",
+		Tag:         "1.0",
+		Digest:      "sha256:base",
+		Platform:    "linux/amd64",
+	}
+	target := base
+	target.Tag = "1.1"
+	target.Digest = "sha256:target"
+
+	comparison := &v1alpha1.SBOMComparison{
+		Status: v1alpha1.SBOMComparisonStatus{
+			Base:   base,
+			Target: target,
+			Added: []v1alpha1.PackageChange{
+				{Name: "openssl", PURL: "pkg:apk/alpine/openssl@1.1.1g-r0", Version: "1.1.1g-r0"},
+			},
+			Upgraded: []v1alpha1.PackageChange{
+				{
+					Name:            "busybox",
+					PURL:            "pkg:apk/alpine/busybox@1.31.1-r10",
+					Version:         "1.31.1-r10",
+					PreviousVersion: "1.31.1-r9",
+				},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printComparison(&out, comparison))
+	assert.Equal(t, `BASE:   ghcr.io/kubewarden/test:1.0@sha256:base (linux/amd64)
+TARGET: ghcr.io/kubewarden/test:1.1@sha256:target (linux/amd64)
+
+CHANGE     PACKAGE   PREVIOUS VERSION   VERSION      PURL
+added      openssl                      1.1.1g-r0    pkg:apk/alpine/openssl@1.1.1g-r0
+upgraded   busybox   1.31.1-r9          1.31.1-r10   pkg:apk/alpine/busybox@1.31.1-r10
+`, out.String())
+
+	out.Reset()
+	comparison.Status.Added = nil
+	comparison.Status.Upgraded = nil
+	require.NoError(t, printComparison(&out, comparison))
+	assert.Contains(t, out.String(), "No package changes.")
+}
@@ -0,0 +1,33 @@
+// Command kubectl-sbomscanner is a kubectl plugin querying the resources of the sbomscanner storage API.
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(newRootCommand()))
+}
+
+func newRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubectl-sbomscanner",
+		Short: "Query the SBOMs and the vulnerability reports of sbomscanner",
+		Args:  cobra.NoArgs,
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
+	cmd.PersistentFlags().StringVar(&loadingRules.ExplicitPath, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.PersistentFlags().StringVar(&overrides.CurrentContext, "context", "", "The kubeconfig context to use")
+	cmd.PersistentFlags().StringVarP(&overrides.Context.Namespace, "namespace", "n", "", "The namespace of the resources")
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	cmd.AddCommand(newCompareCommand(clientConfig))
+
+	return cmd
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/pflag"
+
+	"github.com/kubewarden/sbomscanner/internal/storage"
+)
+
+// blobStoreOptions select the S3-compatible bucket the large payloads of the objects are stored in.
+// No blob store is used unless a bucket is set.
+type blobStoreOptions struct {
+	s3Endpoint  string
+	s3Bucket    string
+	s3Region    string
+	s3Prefix    string
+	s3PathStyle bool
+}
+
+func newBlobStoreOptions() *blobStoreOptions {
+	return &blobStoreOptions{}
+}
+
+func (o *blobStoreOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&o.s3Bucket,
+		"blob-store-s3-bucket",
+		o.s3Bucket,
+		"The bucket the SPDX documents of the SBOMs are stored in, instead of the database. "+
+			"The credentials are read from the standard AWS environment variables and files. "+
+			"Only used by the postgres storage backend.",
+	)
+	flags.StringVar(
+		&o.s3Endpoint,
+		"blob-store-s3-endpoint",
+		o.s3Endpoint,
+		"The URL of the S3-compatible service of the bucket. Defaults to the AWS S3 endpoint of the region.",
+	)
+	flags.StringVar(
+		&o.s3Region,
+		"blob-store-s3-region",
+		o.s3Region,
+		"The region of the bucket. Defaults to the region of the AWS configuration.",
+	)
+	flags.StringVar(
+		&o.s3Prefix,
+		"blob-store-s3-prefix",
+		o.s3Prefix,
+		"The prefix of the keys of the blobs in the bucket. It must not change once blobs have been stored.",
+	)
+	flags.BoolVar(
+		&o.s3PathStyle,
+		"blob-store-s3-path-style",
+		o.s3PathStyle,
+		"Address the bucket in the path of the requests instead of the host, as required by most S3-compatible services.",
+	)
+}
+
+// open returns the blob store of the selected bucket, or nil if no bucket is set.
+func (o *blobStoreOptions) open(ctx context.Context) (storage.BlobStore, error) {
+	if o.s3Bucket == "" {
+		return nil, nil //nolint:nilnil // No blob store is configured.
+	}
+
+	var loadOptions []func(*config.LoadOptions) error
+	if o.s3Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(o.s3Region))
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	blobStore, err := storage.NewS3BlobStore(awsConfig, storage.S3BlobStoreOptions{
+		Endpoint:  o.s3Endpoint,
+		Bucket:    o.s3Bucket,
+		Prefix:    o.s3Prefix,
+		PathStyle: o.s3PathStyle,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob store: %w", err)
+	}
+
+	return blobStore, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/pflag"
+
+	"github.com/kubewarden/sbomscanner/internal/storage"
+)
+
+// databaseOptions select the database the objects are stored in.
+type databaseOptions struct {
+	backend    string
+	sqlitePath string
+}
+
+func newDatabaseOptions() *databaseOptions {
+	return &databaseOptions{
+		backend:    string(storage.PostgresBackend),
+		sqlitePath: "/data/storage.db",
+	}
+}
+
+func (o *databaseOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&o.backend,
+		"storage-backend",
+		o.backend,
+		fmt.Sprintf(
+			"The database the objects are stored in: %q, or %q for single-node, edge and development deployments.",
+			storage.PostgresBackend,
+			storage.SQLiteBackend,
+		),
+	)
+	flags.StringVar(
+		&o.sqlitePath,
+		"sqlite-path",
+		o.sqlitePath,
+		"The file of the SQLite database, created if it does not exist. Only used by the sqlite storage backend.",
+	)
+}
+
+// open opens the database of the selected backend.
+func (o *databaseOptions) open(ctx context.Context) (storage.Database, error) {
+	switch storage.Backend(o.backend) {
+	case storage.PostgresBackend:
+		return openPostgres(ctx)
+	case storage.SQLiteBackend:
+		return storage.OpenSQLiteDatabase(o.sqlitePath) //nolint:wrapcheck // The error already names the database.
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", o.backend)
+	}
+}
+
+// openPostgres connects to the PostgreSQL database with the URI and the server CA certificate mounted in /pg.
+func openPostgres(ctx context.Context) (storage.Database, error) {
+	dbURI, err := os.ReadFile("/pg/uri")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database URI: %w", err)
+	}
+
+	config, err := pgxpool.ParseConfig(string(dbURI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URI: %w", err)
+	}
+
+	// Use the BeforeConnect callback so that whenever a connection is created or reset,
+	// the TLS configuration is reapplied.
+	// This ensures that certificates are reloaded from disk if they have been updated.
+	// See https://github.com/jackc/pgx/discussions/2103
+	config.BeforeConnect = func(_ context.Context, connConfig *pgx.ConnConfig) error {
+		connConfig.Fallbacks = nil // disable TLS fallback to force TLS connection
+
+		serverCA, err := os.ReadFile("/pg/tls/server/ca.crt")
+		if err != nil {
+			return fmt.Errorf("failed to read database server CA certificate: %w", err)
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(serverCA) {
+			return errors.New("failed to append database server CA certificate to pool")
+		}
+
+		connConfig.TLSConfig = &tls.Config{
+			RootCAs:            caCertPool,
+			ServerName:         config.ConnConfig.Host,
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: false,
+		}
+
+		return nil
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+	}
+
+	return storage.NewPostgresDatabase(pool), nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubewarden/sbomscanner/internal/storage"
+)
+
+// newMigrateCommand returns the command managing the schema migrations of the storage database.
+func newMigrateCommand(
+	openDatabase func(ctx context.Context) (storage.Database, error),
+	logger *slog.Logger,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the schema migrations of the storage database",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return errors.New("a migrate subcommand is required: up or status")
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "Apply the pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) (err error) {
+			db, err := openDatabase(c.Context())
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				err = errors.Join(err, db.Close())
+			}()
+
+			if err = storage.Migrate(c.Context(), db, logger); err != nil {
+				return fmt.Errorf("failed to run migrations: %w", err)
+			}
+
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the status of the migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) (err error) {
+			db, err := openDatabase(c.Context())
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				err = errors.Join(err, db.Close())
+			}()
+
+			statuses, err := storage.GetMigrationStatus(c.Context(), db)
+			if err != nil {
+				return fmt.Errorf("failed to get migration status: %w", err)
+			}
+
+			w := tabwriter.NewWriter(c.OutOrStdout(), 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+			for _, status := range statuses {
+				state := "pending"
+				appliedAt := ""
+				if status.AppliedAt != nil {
+					state = "applied"
+					appliedAt = status.AppliedAt.Format(time.RFC3339)
+				}
+				if status.Unknown {
+					state = "unknown"
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+			}
+
+			if err = w.Flush(); err != nil {
+				return fmt.Errorf("failed to write migration status: %w", err)
+			}
+
+			return nil
+		},
+	})
+
+	return cmd
+}
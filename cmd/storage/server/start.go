@@ -21,17 +21,19 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/cobra"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/apiserver/pkg/endpoints/openapi"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/server/dynamiccertificates"
 	genericoptions "k8s.io/apiserver/pkg/server/options"
+	cacherstorage "k8s.io/apiserver/pkg/storage/cacher"
 	"k8s.io/apiserver/pkg/util/compatibility"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/component-base/featuregate"
@@ -41,10 +43,14 @@ import (
 
 	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 	"github.com/kubewarden/sbomscanner/internal/apiserver"
+	"github.com/kubewarden/sbomscanner/internal/storage"
 	informers "github.com/kubewarden/sbomscanner/pkg/generated/informers/externalversions"
 	sampleopenapi "github.com/kubewarden/sbomscanner/pkg/generated/openapi"
 )
 
+// sbomRequestOverhead is the room left in the requests writing an SBOM for the fields other than the SPDX document.
+const sbomRequestOverhead = 1024 * 1024
+
 // WardleServerOptions contains state for master/api server
 type WardleServerOptions struct {
 	RecommendedOptions *genericoptions.RecommendedOptions
@@ -58,8 +64,21 @@ type WardleServerOptions struct {
 	SharedInformerFactory informers.SharedInformerFactory
 	AlternateDNS          []string
 
-	DB     *pgxpool.Pool
-	Logger *slog.Logger
+	// WatchEventsRetention is the amount of time the watch events are kept before being compacted.
+	WatchEventsRetention time.Duration
+	// WatchCacheResources are the resources whose reads are served from the watch cache.
+	WatchCacheResources []string
+	// MaxSPDXSize is the maximum size in bytes of the SPDX document of an SBOM.
+	MaxSPDXSize int
+	// BlobSweepInterval is the interval between the deletions of the blobs no longer referenced.
+	BlobSweepInterval time.Duration
+
+	// OpenDatabase opens the database the objects are stored in.
+	OpenDatabase func(ctx context.Context) (storage.Database, error)
+	// OpenBlobStore opens the blob store the large payloads of the objects are stored in.
+	// It returns nil if no blob store is configured.
+	OpenBlobStore func(ctx context.Context) (storage.BlobStore, error)
+	Logger        *slog.Logger
 }
 
 func WardleVersionToKubeVersion(ver *version.Version) *version.Version {
@@ -83,21 +102,28 @@ func WardleVersionToKubeVersion(ver *version.Version) *version.Version {
 }
 
 // NewWardleServerOptions returns a new WardleServerOptions
-func NewWardleServerOptions(db *pgxpool.Pool, logger *slog.Logger) *WardleServerOptions {
+func NewWardleServerOptions(
+	openDatabase func(ctx context.Context) (storage.Database, error),
+	openBlobStore func(ctx context.Context) (storage.BlobStore, error),
+	logger *slog.Logger,
+) *WardleServerOptions {
 	o := &WardleServerOptions{
 		RecommendedOptions: genericoptions.NewRecommendedOptions(
 			"/registry/sbomscanner.kubewarden.io",
 			apiserver.Codecs.LegacyCodec(v1alpha1.SchemeGroupVersion),
 		),
 		ComponentGlobalsRegistry: compatibility.DefaultComponentGlobalsRegistry,
-		DB:                       db,
+		WatchEventsRetention:     storage.DefaultWatchEventsRetention,
+		WatchCacheResources:      []string{"images", "vulnerabilitysummaries"},
+		MaxSPDXSize:              storage.DefaultMaxSPDXSize,
+		BlobSweepInterval:        storage.DefaultBlobSweepInterval,
+		OpenDatabase:             openDatabase,
+		OpenBlobStore:            openBlobStore,
 		Logger:                   logger,
 	}
 
 	// Disable etcd
 	o.RecommendedOptions.Etcd = nil
-	// Disable admission plugins
-	o.RecommendedOptions.Admission = nil
 	// Disable priority and fairness as it is not compatible with old versions of Kubernetes
 	o.RecommendedOptions.Features.EnablePriorityAndFairness = false
 	return o
@@ -130,6 +156,34 @@ func NewCommandStartWardleServer(ctx context.Context, defaults *WardleServerOpti
 
 	flags := cmd.Flags()
 	o.RecommendedOptions.AddFlags(flags)
+	flags.DurationVar(
+		&o.WatchEventsRetention,
+		"watch-events-retention",
+		o.WatchEventsRetention,
+		"The amount of time the watch events are kept. Watches resuming from an older resourceVersion receive a 410 Gone error.",
+	)
+	flags.StringSliceVar(
+		&o.WatchCacheResources,
+		"watch-cache-resources",
+		o.WatchCacheResources,
+		fmt.Sprintf(
+			"The resources whose reads are served from an in-memory watch cache, among %v. "+
+				"Caching sboms and vulnerabilityreports trades a large amount of memory for fewer database reads.",
+			sets.List(apiserver.WatchCacheResources),
+		),
+	)
+	flags.IntVar(
+		&o.MaxSPDXSize,
+		"max-spdx-size",
+		o.MaxSPDXSize,
+		"The maximum size in bytes of the SPDX document of an SBOM. Larger SBOMs are rejected.",
+	)
+	flags.DurationVar(
+		&o.BlobSweepInterval,
+		"blob-sweep-interval",
+		o.BlobSweepInterval,
+		"The interval between the deletions from the blob store of the blobs no longer referenced.",
+	)
 
 	// The following lines demonstrate how to configure version compatibility and feature gates
 	// for the "Wardle" component, as an example of KEP-4330.
@@ -151,13 +205,6 @@ func NewCommandStartWardleServer(ctx context.Context, defaults *WardleServerOpti
 		apiserver.WardleComponentName, basecompatibility.NewEffectiveVersionFromString(defaultWardleVersion, "", ""),
 		featuregate.NewVersionedFeatureGate(version.MustParse(defaultWardleVersion)))
 
-	// TODO: remove this once we implement WatchList in the storage.
-	mutableFeatureGate := utilfeature.DefaultMutableFeatureGate
-	err := mutableFeatureGate.Set("WatchList=false")
-	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("failed to set default feature gate: %w", err))
-	}
-
 	// Register the default kube component if not already present in the global registry.
 	_, _ = defaults.ComponentGlobalsRegistry.ComponentGlobalsOrRegister(
 		basecompatibility.DefaultKubeComponent,
@@ -166,7 +213,7 @@ func NewCommandStartWardleServer(ctx context.Context, defaults *WardleServerOpti
 			"",
 			"",
 		),
-		mutableFeatureGate,
+		utilfeature.DefaultMutableFeatureGate,
 	)
 
 	// Set the emulation version mapping from the "Wardle" component to the kube component.
@@ -189,6 +236,23 @@ func (o *WardleServerOptions) Validate(_ []string) error {
 	errors := []error{}
 	errors = append(errors, o.RecommendedOptions.Validate()...)
 	errors = append(errors, o.ComponentGlobalsRegistry.Validate()...)
+	for _, resource := range o.WatchCacheResources {
+		if !apiserver.WatchCacheResources.Has(resource) {
+			errors = append(errors, fmt.Errorf("--watch-cache-resources: resource %q cannot be served from the watch cache", resource))
+		}
+	}
+	if len(o.WatchCacheResources) > 0 && o.WatchEventsRetention < cacherstorage.DefaultEventFreshDuration {
+		errors = append(errors, fmt.Errorf(
+			"--watch-events-retention must be at least %s when --watch-cache-resources is set",
+			cacherstorage.DefaultEventFreshDuration,
+		))
+	}
+	if o.MaxSPDXSize <= 0 {
+		errors = append(errors, fmt.Errorf("--max-spdx-size must be positive, got %d", o.MaxSPDXSize))
+	}
+	if o.BlobSweepInterval <= 0 {
+		errors = append(errors, fmt.Errorf("--blob-sweep-interval must be positive, got %s", o.BlobSweepInterval))
+	}
 	return utilerrors.NewAggregate(errors)
 }
 
@@ -231,22 +295,51 @@ func (o *WardleServerOptions) Config() (*apiserver.Config, error) {
 	if err := o.RecommendedOptions.ApplyTo(serverConfig); err != nil {
 		return nil, fmt.Errorf("error applying options to server config: %w", err)
 	}
+	// The requests writing an SBOM must fit the SPDX document and the rest of the object.
+	serverConfig.MaxRequestBodyBytes = max(serverConfig.MaxRequestBodyBytes, int64(o.MaxSPDXSize)+sbomRequestOverhead)
 
 	config := &apiserver.Config{
 		GenericConfig: serverConfig,
-		ExtraConfig:   apiserver.ExtraConfig{},
+		ExtraConfig: apiserver.ExtraConfig{
+			WatchEventsRetention: o.WatchEventsRetention,
+			WatchCacheResources:  sets.New(o.WatchCacheResources...),
+			MaxSPDXSize:          o.MaxSPDXSize,
+			BlobSweepInterval:    o.BlobSweepInterval,
+		},
 	}
 	return config, nil
 }
 
 // RunWardleServer starts a new WardleServer given WardleServerOptions
 func (o *WardleServerOptions) RunWardleServer(ctx context.Context) error {
+	db, err := o.OpenDatabase(ctx)
+	if err != nil {
+		return fmt.Errorf("error opening database: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			o.Logger.Error("failed to close database", "error", err)
+		}
+	}()
+
+	if err = storage.Migrate(ctx, db, o.Logger); err != nil {
+		return fmt.Errorf("error running migrations: %w", err)
+	}
+
+	blobs, err := o.OpenBlobStore(ctx)
+	if err != nil {
+		return fmt.Errorf("error opening blob store: %w", err)
+	}
+	if blobs != nil && db.Backend() != storage.PostgresBackend {
+		return fmt.Errorf("the blob store is not supported by the %s storage backend", db.Backend())
+	}
+
 	config, err := o.Config()
 	if err != nil {
 		return err
 	}
 
-	server, err := config.Complete().New(o.DB, o.Logger)
+	server, err := config.Complete().New(db, blobs, o.Logger)
 	if err != nil {
 		return fmt.Errorf("error creating server: %w", err)
 	}
@@ -17,12 +17,25 @@ limitations under the License.
 package server
 
 import (
+	"context"
+	"log/slog"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/util/compatibility"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	basecompatibility "k8s.io/component-base/compatibility"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubewarden/sbomscanner/internal/apiserver"
 )
 
 func TestWardleEmulationVersionToKubeEmulationVersion(t *testing.T) {
@@ -66,3 +79,78 @@ func TestWardleEmulationVersionToKubeEmulationVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestAdmissionChain(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		args            []string
+		expectedPlugins []string
+		expectedError   string
+	}{
+		{
+			desc: "default plugins",
+			expectedPlugins: []string{
+				"NamespaceLifecycle",
+				"MutatingAdmissionPolicy",
+				"MutatingAdmissionWebhook",
+				"ValidatingAdmissionPolicy",
+				"ValidatingAdmissionWebhook",
+			},
+		},
+		{
+			desc: "disabled plugin",
+			args: []string{"--disable-admission-plugins=MutatingAdmissionWebhook"},
+			expectedPlugins: []string{
+				"NamespaceLifecycle",
+				"MutatingAdmissionPolicy",
+				"ValidatingAdmissionPolicy",
+				"ValidatingAdmissionWebhook",
+			},
+		},
+		{
+			desc:          "unknown plugin",
+			args:          []string{"--enable-admission-plugins=PodSecurity"},
+			expectedError: `enable-admission-plugins plugin "PodSecurity" is unknown`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			o := NewWardleServerOptions(nil, nil, slog.Default())
+			o.ComponentGlobalsRegistry = basecompatibility.NewComponentGlobalsRegistry()
+			cmd := NewCommandStartWardleServer(context.Background(), o)
+			require.NoError(t, cmd.Flags().Parse(tc.args))
+			require.NoError(t, o.ComponentGlobalsRegistry.Set())
+
+			// The storage server runs without etcd and without the priority and fairness API groups.
+			err := o.Validate(nil)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			var plugins []string
+			o.RecommendedOptions.Admission.Decorators = append(o.RecommendedOptions.Admission.Decorators,
+				admission.DecoratorFunc(func(handler admission.Interface, name string) admission.Interface {
+					plugins = append(plugins, name)
+					return handler
+				}),
+			)
+
+			kubeClient := kubefake.NewClientset()
+			serverConfig := genericapiserver.NewRecommendedConfig(apiserver.Codecs)
+			serverConfig.Authorization.Authorizer = authorizerfactory.NewAlwaysAllowAuthorizer()
+			require.NoError(t, o.RecommendedOptions.Admission.ApplyTo(
+				&serverConfig.Config,
+				kubeinformers.NewSharedInformerFactory(kubeClient, 0),
+				kubeClient,
+				dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+				o.ComponentGlobalsRegistry.FeatureGateFor(basecompatibility.DefaultKubeComponent),
+			))
+
+			assert.NotNil(t, serverConfig.AdmissionControl)
+			assert.Equal(t, tc.expectedPlugins, plugins)
+		})
+	}
+}
@@ -19,15 +19,16 @@ package apiserver
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kubewarden/sbomscanner/api/storage/install"
 	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 	"github.com/kubewarden/sbomscanner/internal/storage"
@@ -40,6 +41,8 @@ var (
 	// versions and content types.
 	Codecs              = serializer.NewCodecFactory(Scheme)
 	WardleComponentName = "wardle"
+	// WatchCacheResources are the resources that can be served from the watch cache.
+	WatchCacheResources = sets.New("images", "sboms", "vulnerabilityreports", "vulnerabilitysummaries")
 )
 
 func init() {
@@ -63,7 +66,14 @@ func init() {
 
 // ExtraConfig holds custom apiserver config
 type ExtraConfig struct {
-	// Place you custom config here.
+	// WatchEventsRetention is the amount of time the watch events are kept before being compacted.
+	WatchEventsRetention time.Duration
+	// WatchCacheResources are the resources whose reads are served from the watch cache.
+	WatchCacheResources sets.Set[string]
+	// MaxSPDXSize is the maximum size in bytes of the SPDX document of an SBOM.
+	MaxSPDXSize int
+	// BlobSweepInterval is the interval between the deletions of the blobs no longer referenced.
+	BlobSweepInterval time.Duration
 }
 
 // Config defines the config for the apiserver
@@ -98,7 +108,8 @@ func (cfg *Config) Complete() CompletedConfig {
 }
 
 // New returns a new instance of WardleServer from the given config.
-func (c completedConfig) New(db *pgxpool.Pool, logger *slog.Logger) (*WardleServer, error) {
+// The large payloads of the objects are stored in the blob store, if it is not nil.
+func (c completedConfig) New(db storage.Database, blobs storage.BlobStore, logger *slog.Logger) (*WardleServer, error) {
 	genericServer, err := c.GenericConfig.New("sample-apiserver", genericapiserver.NewEmptyDelegate())
 	if err != nil {
 		return nil, fmt.Errorf("error creating generic server: %w", err)
@@ -110,11 +121,25 @@ func (c completedConfig) New(db *pgxpool.Pool, logger *slog.Logger) (*WardleServ
 
 	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(v1alpha1.GroupName, Scheme, metav1.ParameterCodec, Codecs)
 
-	imageStore, err := storage.NewImageStore(Scheme, c.GenericConfig.RESTOptionsGetter, db, logger)
+	imageStore, err := storage.NewImageStore(
+		Scheme,
+		c.GenericConfig.RESTOptionsGetter,
+		db,
+		c.watchCacheConfig("images"),
+		logger,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating Image store: %w", err)
 	}
-	sbomStore, err := storage.NewSBOMStore(Scheme, c.GenericConfig.RESTOptionsGetter, db, logger)
+	sbomStore, err := storage.NewSBOMStore(
+		Scheme,
+		c.GenericConfig.RESTOptionsGetter,
+		db,
+		blobs,
+		c.watchCacheConfig("sboms"),
+		c.ExtraConfig.MaxSPDXSize,
+		logger,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating SBOM store: %w", err)
 	}
@@ -122,6 +147,7 @@ func (c completedConfig) New(db *pgxpool.Pool, logger *slog.Logger) (*WardleServ
 		Scheme,
 		c.GenericConfig.RESTOptionsGetter,
 		db,
+		c.watchCacheConfig("vulnerabilityreports"),
 		logger,
 	)
 	if err != nil {
@@ -130,13 +156,120 @@ func (c completedConfig) New(db *pgxpool.Pool, logger *slog.Logger) (*WardleServ
 
 	v1alpha1storage := map[string]rest.Storage{}
 	v1alpha1storage["images"] = imageStore
+	v1alpha1storage["imagebatches"] = storage.NewImageBatchStore(
+		imageStore,
+		c.GenericConfig.Authorization.Authorizer,
+		logger,
+	)
 	v1alpha1storage["sboms"] = sbomStore
+	v1alpha1storage["sbomcomparisons"] = storage.NewSBOMComparisonStore(
+		sbomStore,
+		c.GenericConfig.Authorization.Authorizer,
+		logger,
+	)
 	v1alpha1storage["vulnerabilityreports"] = vulnerabilityReportStore
+
+	// The resources computed from the findings and packages tables are only served by PostgreSQL.
+	if db.Backend() == storage.PostgresBackend {
+		vulnerabilitySummaryStore, err := storage.NewVulnerabilitySummaryStore(
+			Scheme,
+			c.GenericConfig.RESTOptionsGetter,
+			db,
+			c.watchCacheConfig("vulnerabilitysummaries"),
+			logger,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating VulnerabilitySummary store: %w", err)
+		}
+		vulnerabilityHistoryStore, err := storage.NewVulnerabilityHistoryStore(
+			Scheme,
+			c.GenericConfig.RESTOptionsGetter,
+			db,
+			logger,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating VulnerabilityHistory store: %w", err)
+		}
+
+		v1alpha1storage["vulnerabilitysummaries"] = vulnerabilitySummaryStore
+		v1alpha1storage["vulnerabilityhistories"] = vulnerabilityHistoryStore
+		v1alpha1storage["vulnerabilityrollups"] = storage.NewVulnerabilityRollupStore(db, logger)
+		v1alpha1storage["clustervulnerabilityrollups"] = storage.NewClusterVulnerabilityRollupStore(db, logger)
+		v1alpha1storage["cves"] = storage.NewCVEStore(
+			db,
+			c.GenericConfig.Authorization.Authorizer,
+			logger,
+		)
+		v1alpha1storage["packagesearches"] = storage.NewPackageSearchStore(
+			db,
+			c.GenericConfig.Authorization.Authorizer,
+			logger,
+		)
+	}
 	apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
 
 	if err = s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
 		return nil, fmt.Errorf("error installing API group: %w", err)
 	}
 
+	if err = s.GenericAPIServer.AddReadyzChecks(storage.NewDatabaseHealthCheck(db)); err != nil {
+		return nil, fmt.Errorf("error adding database readiness check: %w", err)
+	}
+	storage.RegisterDatabaseMetrics(db)
+
+	compactor := storage.NewCompactor(db, c.ExtraConfig.WatchEventsRetention, logger)
+	s.GenericAPIServer.AddPostStartHookOrDie("start-watch-events-compactor", func(ctx genericapiserver.PostStartHookContext) error {
+		go compactor.Start(ctx)
+		return nil
+	})
+
+	// The SBOMs written before the SPDX blobs are moved in the background, they are served meanwhile.
+	s.GenericAPIServer.AddPostStartHookOrDie("move-spdx-documents-to-blobs", func(ctx genericapiserver.PostStartHookContext) error {
+		go func() {
+			moved, err := storage.MoveSPDXDocumentsToBlobs(ctx, db, blobs, logger)
+			if err != nil {
+				logger.ErrorContext(ctx, "failed to move SPDX documents to blobs", "moved", moved, "error", err)
+				return
+			}
+			if moved > 0 {
+				logger.InfoContext(ctx, "Moved SPDX documents to blobs", "moved", moved)
+			}
+
+			if blobs == nil {
+				return
+			}
+			offloaded, err := storage.OffloadSPDXBlobs(ctx, db, blobs, logger)
+			if err != nil {
+				logger.ErrorContext(ctx, "failed to offload SPDX blobs", "offloaded", offloaded, "error", err)
+				return
+			}
+			if offloaded > 0 {
+				logger.InfoContext(ctx, "Offloaded SPDX blobs to the blob store", "offloaded", offloaded)
+			}
+		}()
+		return nil
+	})
+
+	if blobs != nil {
+		sweeper := storage.NewBlobSweeper(db, blobs, c.ExtraConfig.BlobSweepInterval, logger)
+		s.GenericAPIServer.AddPostStartHookOrDie("start-blob-sweeper", func(ctx genericapiserver.PostStartHookContext) error {
+			go sweeper.Start(ctx)
+			return nil
+		})
+	}
+
 	return s, nil
 }
+
+// watchCacheConfig returns the watch cache configuration of the resource,
+// or nil if the resource is not served from the watch cache.
+func (c completedConfig) watchCacheConfig(resource string) *storage.WatchCacheConfig {
+	if !c.ExtraConfig.WatchCacheResources.Has(resource) {
+		return nil
+	}
+
+	return &storage.WatchCacheConfig{
+		Codec:               Codecs.LegacyCodec(v1alpha1.SchemeGroupVersion),
+		EventsHistoryWindow: c.ExtraConfig.WatchEventsRetention,
+	}
+}
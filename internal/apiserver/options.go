@@ -2,6 +2,7 @@ package apiserver
 
 import (
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -20,6 +21,7 @@ func (o *RestOptionsGetter) GetRESTOptions(
 	return generic.RESTOptions{
 		EnableGarbageCollection: true,
 		DeleteCollectionWorkers: 1,
+		CountMetricPollPeriod:   time.Minute,
 		ResourcePrefix:          fmt.Sprintf("/%s/%s", resource.Group, resource.Resource),
 	}, nil
 }
@@ -5,8 +5,8 @@ import (
 	"fmt"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -24,7 +24,7 @@ type RegistryReconciler struct {
 // +kubebuilder:rbac:groups=sbomscanner.kubewarden.io,resources=registries,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sbomscanner.kubewarden.io,resources=registries/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sbomscanner.kubewarden.io,resources=registries/finalizers,verbs=update
-// +kubebuilder:rbac:groups=storage.sbomscanner.kubewarden.io,resources=images,verbs=list;watch
+// +kubebuilder:rbac:groups=storage.sbomscanner.kubewarden.io,resources=images,verbs=list;watch;deletecollection
 
 // Reconcile reconciles a Registry.
 // If the Registry doesn't have the last discovered timestamp, it sends a create catalog request to the workers.
@@ -45,26 +45,20 @@ func (r *RegistryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		log.V(1).
 			Info("Deleting Images that are not in the current list of repositories", "name", registry.Name, "namespace", registry.Namespace, "repositories", registry.Spec.Repositories)
 
-		images := &storagev1alpha1.ImageList{}
-		listOpts := []client.ListOption{
-			client.InNamespace(req.Namespace),
-			client.MatchingFields{
-				storagev1alpha1.IndexImageMetadataRegistry: registry.Name,
-			},
+		// The images of the registry outside of the repositories are deleted with a single request,
+		// which the storage serves in a single transaction.
+		selectors := []fields.Selector{
+			fields.OneTermEqualSelector(storagev1alpha1.IndexImageMetadataRegistry, registry.Name),
 		}
-		if err := r.List(ctx, images, listOpts...); err != nil {
-			return ctrl.Result{}, fmt.Errorf("unable to list Images: %w", err)
+		for _, repository := range registry.Spec.Repositories {
+			selectors = append(selectors, fields.OneTermNotEqualSelector(storagev1alpha1.IndexImageMetadataRepository, repository))
 		}
-
-		allowedRepositories := sets.NewString(registry.Spec.Repositories...)
-
-		for _, image := range images.Items {
-			if !allowedRepositories.Has(image.GetImageMetadata().Repository) {
-				if err := r.Delete(ctx, &image); err != nil {
-					return ctrl.Result{}, fmt.Errorf("unable to delete Image %s: %w", image.Name, err)
-				}
-				log.V(1).Info("Deleted Image", "name", image.Name, "repository", image.GetImageMetadata().Repository)
-			}
+		deleteOpts := []client.DeleteAllOfOption{
+			client.InNamespace(req.Namespace),
+			client.MatchingFieldsSelector{Selector: fields.AndSelectors(selectors...)},
+		}
+		if err := r.DeleteAllOf(ctx, &storagev1alpha1.Image{}, deleteOpts...); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to delete Images: %w", err)
 		}
 	}
 
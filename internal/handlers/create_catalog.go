@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"slices"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	cranev1 "github.com/google/go-containerregistry/pkg/v1"
@@ -36,6 +37,9 @@ import (
 	"github.com/kubewarden/sbomscanner/internal/messaging"
 )
 
+// imageBatchSize is the number of images created by each ImageBatch.
+const imageBatchSize = 100
+
 // CreateCatalogHandler is a handler for creating a catalog of images in a registry.
 type CreateCatalogHandler struct {
 	registryClientFactory registryclient.ClientFactory
@@ -189,44 +193,53 @@ func (h *CreateCatalogHandler) Handle(ctx context.Context, message messaging.Mes
 			continue
 		}
 
-		for _, image := range images {
-			// Re-fetch the scanjob to be sure it was not deleted while we were processing images.
-			// If the scanjob is not found, we circuit-break the image creation.
-			err = h.k8sClient.Get(ctx, types.NamespacedName{
-				Name:      createCatalogMessage.ScanJob.Name,
-				Namespace: createCatalogMessage.ScanJob.Namespace,
-			}, scanJob)
-			if err != nil {
-				if apierrors.IsNotFound(err) {
-					h.logger.InfoContext(ctx, "ScanJob not found, stopping catalog creation", "scanjob", createCatalogMessage.ScanJob.Name, "namespace", createCatalogMessage.ScanJob.Namespace)
-					return nil
-				}
-				return fmt.Errorf("cannot get scanjob %s/%s: %w", createCatalogMessage.ScanJob.Namespace, createCatalogMessage.ScanJob.Name, err)
-			}
-			if string(scanJob.GetUID()) != createCatalogMessage.ScanJob.UID {
-				h.logger.InfoContext(ctx, "ScanJob not founnd, stopping SBOM generation (UID changed)", "scanjob", createCatalogMessage.ScanJob.Name, "namespace", createCatalogMessage.ScanJob.Namespace,
-					"uid", createCatalogMessage.ScanJob.UID)
-				return nil
-			}
+		discoveredImages = append(discoveredImages, images...)
+	}
 
-			discoveredImages = append(discoveredImages, image)
+	var newImages []storagev1alpha1.Image
+	for _, image := range discoveredImages {
+		if !existingImageNames.Has(image.Name) {
+			newImages = append(newImages, image)
+		}
+	}
 
-			if existingImageNames.Has(image.Name) {
-				continue
+	// The new images are created in batches, each written by the storage in a single transaction.
+	for batch := range slices.Chunk(newImages, imageBatchSize) {
+		// Re-fetch the scanjob to be sure it was not deleted while we were processing images.
+		// If the scanjob is not found, we circuit-break the image creation.
+		err = h.k8sClient.Get(ctx, types.NamespacedName{
+			Name:      createCatalogMessage.ScanJob.Name,
+			Namespace: createCatalogMessage.ScanJob.Namespace,
+		}, scanJob)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				h.logger.InfoContext(ctx, "ScanJob not found, stopping catalog creation", "scanjob", createCatalogMessage.ScanJob.Name, "namespace", createCatalogMessage.ScanJob.Namespace)
+				return nil
 			}
+			return fmt.Errorf("cannot get scanjob %s/%s: %w", createCatalogMessage.ScanJob.Namespace, createCatalogMessage.ScanJob.Name, err)
+		}
+		if string(scanJob.GetUID()) != createCatalogMessage.ScanJob.UID {
+			h.logger.InfoContext(ctx, "ScanJob not founnd, stopping SBOM generation (UID changed)", "scanjob", createCatalogMessage.ScanJob.Name, "namespace", createCatalogMessage.ScanJob.Namespace,
+				"uid", createCatalogMessage.ScanJob.UID)
+			return nil
+		}
 
-			h.logger.InfoContext(ctx, "Creating image", "image", image.Name, "namespace", image.Namespace)
-			if err = h.k8sClient.Create(ctx, &image); err != nil {
-				if apierrors.IsAlreadyExists(err) {
-					h.logger.InfoContext(ctx, "Image already exists, skipping creation", "image", image.Name, "namespace", image.Namespace)
-					continue
-				}
-				return fmt.Errorf("cannot create image %s: %w", image.Name, err)
-			}
+		h.logger.InfoContext(ctx, "Creating images", "count", len(batch), "namespace", registry.Namespace)
+		imageBatch := &storagev1alpha1.ImageBatch{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: registry.Namespace,
+			},
+			Spec: storagev1alpha1.ImageBatchSpec{
+				Images: batch,
+			},
+		}
+		if err = h.k8sClient.Create(ctx, imageBatch); err != nil {
+			return fmt.Errorf("cannot create images in registry %s: %w", registry.Name, err)
+		}
+		h.logger.DebugContext(ctx, "Images created", "created", imageBatch.Status.Created, "updated", imageBatch.Status.Updated, "unchanged", imageBatch.Status.Unchanged)
 
-			if err = message.InProgress(); err != nil {
-				return fmt.Errorf("failed to ack message as in progress: %w", err)
-			}
+		if err = message.InProgress(); err != nil {
+			return fmt.Errorf("failed to ack message as in progress: %w", err)
 		}
 	}
 
@@ -234,7 +247,7 @@ func (h *CreateCatalogHandler) Handle(ctx context.Context, message messaging.Mes
 	for _, image := range discoveredImages {
 		discoveredImageNames.Insert(image.Name)
 	}
-	if err = h.deleteObsoleteImages(ctx, existingImageNames, discoveredImageNames, registry.Namespace, message); err != nil {
+	if err = h.deleteObsoleteImages(ctx, existingImageList.Items, discoveredImageNames, registry, message); err != nil {
 		return fmt.Errorf("cannot delete obsolete images in registry %s: %w", registry.Name, err)
 	}
 
@@ -461,34 +474,65 @@ func (h *CreateCatalogHandler) transportFromRegistry(registry *v1alpha1.Registry
 }
 
 // deleteObsoleteImages deletes images that are not present in the discovered registry anymore.
+// The repositories whose images are all obsolete are deleted with a single request,
+// the other obsolete images are deleted one at a time.
 func (h *CreateCatalogHandler) deleteObsoleteImages(
 	ctx context.Context,
-	existingImageNames sets.Set[string],
+	existingImages []storagev1alpha1.Image,
 	discoveredImageNames sets.Set[string],
-	namespace string,
+	registry *v1alpha1.Registry,
 	message messaging.Message,
 ) error {
-	obsoleteImageNames := existingImageNames.Difference(discoveredImageNames)
+	obsoleteImageNames := map[string][]string{}
+	keptRepositories := sets.Set[string]{}
+	for _, existingImage := range existingImages {
+		repository := existingImage.GetImageMetadata().Repository
+		if discoveredImageNames.Has(existingImage.Name) {
+			keptRepositories.Insert(repository)
+			continue
+		}
+		obsoleteImageNames[repository] = append(obsoleteImageNames[repository], existingImage.Name)
+	}
 
-	h.logger.DebugContext(ctx, "Existing images", "names", existingImageNames)
 	h.logger.DebugContext(ctx, "Discovered images", "names", discoveredImageNames)
 	h.logger.DebugContext(ctx, "Obsolete images", "names", obsoleteImageNames)
 
-	for obsoleteImageName := range obsoleteImageNames {
-		existingImage := storagev1alpha1.Image{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      obsoleteImageName,
-				Namespace: namespace,
-			},
+	for repository, imageNames := range obsoleteImageNames {
+		if !keptRepositories.Has(repository) {
+			h.logger.DebugContext(ctx, "Deleting obsolete repository images", "repository", repository, "count", len(imageNames), "namespace", registry.Namespace)
+
+			if err := h.k8sClient.DeleteAllOf(ctx, &storagev1alpha1.Image{},
+				client.InNamespace(registry.Namespace),
+				client.MatchingFields{
+					storagev1alpha1.IndexImageMetadataRegistry:   registry.Name,
+					storagev1alpha1.IndexImageMetadataRepository: repository,
+				},
+			); err != nil {
+				return fmt.Errorf("cannot delete images of repository %s: %w", repository, err)
+			}
+			if err := message.InProgress(); err != nil {
+				return fmt.Errorf("cannot mark message as in progress: %w", err)
+			}
+
+			continue
 		}
 
-		h.logger.DebugContext(ctx, "Deleting obsolete image", "name", obsoleteImageName, "namespace", namespace)
+		for _, obsoleteImageName := range imageNames {
+			existingImage := storagev1alpha1.Image{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      obsoleteImageName,
+					Namespace: registry.Namespace,
+				},
+			}
 
-		if err := h.k8sClient.Delete(ctx, &existingImage); err != nil {
-			return fmt.Errorf("cannot delete image %s/%s: %w", obsoleteImageName, namespace, err)
-		}
-		if err := message.InProgress(); err != nil {
-			return fmt.Errorf("cannot mark message as in progress: %w", err)
+			h.logger.DebugContext(ctx, "Deleting obsolete image", "name", obsoleteImageName, "namespace", registry.Namespace)
+
+			if err := h.k8sClient.Delete(ctx, &existingImage); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("cannot delete image %s/%s: %w", obsoleteImageName, registry.Namespace, err)
+			}
+			if err := message.InProgress(); err != nil {
+				return fmt.Errorf("cannot mark message as in progress: %w", err)
+			}
 		}
 	}
 
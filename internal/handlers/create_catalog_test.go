@@ -173,6 +173,7 @@ func TestCreateCatalogHandler_Handle(t *testing.T) {
 
 			return []string{image.GetImageMetadata().Registry}
 		}).
+		WithInterceptorFuncs(storageInterceptor).
 		Build()
 
 	handler := NewCreateCatalogHandler(
@@ -428,6 +429,7 @@ func TestCreateCatalogHandler_Handle_ObsoleteImages(t *testing.T) {
 			}
 			return []string{image.GetImageMetadata().Registry}
 		}).
+		WithInterceptorFuncs(storageInterceptor).
 		Build()
 
 	handler := NewCreateCatalogHandler(
@@ -517,21 +519,55 @@ func TestCreateCatalogHandler_DiscoverRepositories(t *testing.T) {
 func TestCatalogHandler_DeleteObsoleteImages(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
-	existingImages := []runtime.Object{
-		&storagev1alpha1.Image{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "image-1",
-				Namespace: "default",
-			},
+
+	registry := &v1alpha1.Registry{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-registry",
+			Namespace: "default",
 		},
-		&storagev1alpha1.Image{
+	}
+	newImage := func(name, repository string) *storagev1alpha1.Image {
+		return &storagev1alpha1.Image{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "image-2",
+				Name:      name,
 				Namespace: "default",
 			},
-		},
+			ImageMetadata: storagev1alpha1.ImageMetadata{
+				Registry:   registry.Name,
+				Repository: repository,
+			},
+		}
+	}
+	existingImages := []storagev1alpha1.Image{
+		*newImage("image-1", "repo1"),
+		*newImage("image-2", "repo1"),
+		*newImage("image-3", "repo2"),
+		*newImage("image-4", "repo2"),
+	}
+	existingObjects := make([]runtime.Object, 0, len(existingImages))
+	for i := range existingImages {
+		existingObjects = append(existingObjects, &existingImages[i])
 	}
-	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(existingImages...).Build()
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(existingObjects...).
+		WithIndex(&storagev1alpha1.Image{}, storagev1alpha1.IndexImageMetadataRegistry, func(obj client.Object) []string {
+			image, ok := obj.(*storagev1alpha1.Image)
+			if !ok {
+				return nil
+			}
+			return []string{image.GetImageMetadata().Registry}
+		}).
+		WithIndex(&storagev1alpha1.Image{}, storagev1alpha1.IndexImageMetadataRepository, func(obj client.Object) []string {
+			image, ok := obj.(*storagev1alpha1.Image)
+			if !ok {
+				return nil
+			}
+			return []string{image.GetImageMetadata().Repository}
+		}).
+		WithInterceptorFuncs(storageInterceptor).
+		Build()
 
 	handler := &CreateCatalogHandler{
 		k8sClient: k8sClient,
@@ -540,15 +576,12 @@ func TestCatalogHandler_DeleteObsoleteImages(t *testing.T) {
 
 	ctx := t.Context()
 
-	existingImageNames := sets.New(
-		"image-1",
-		"image-2",
-	)
+	// Image 2 is obsolete, as well as the whole repo2.
 	newImageNames := sets.New(
-		"image-1", // Image 2 is obsolete
+		"image-1",
 	)
 
-	err := handler.deleteObsoleteImages(ctx, existingImageNames, newImageNames, "default", &testMessage{})
+	err := handler.deleteObsoleteImages(ctx, existingImages, newImageNames, registry, &testMessage{})
 	require.NoError(t, err)
 
 	var remainingImages storagev1alpha1.ImageList
@@ -730,6 +763,7 @@ func TestCreateCatalogHandler_Handle_StopProcessing(t *testing.T) {
 					}
 					return []string{image.GetImageMetadata().Registry}
 				}).
+				WithInterceptorFuncs(storageInterceptor).
 				Build()
 
 			mockRegistryClient := registryMocks.NewClient(t)
@@ -1002,6 +1036,7 @@ func TestCreateCatalogHandler_Handle_PrivateRegistry(t *testing.T) {
 
 			return []string{image.GetImageMetadata().Registry}
 		}).
+		WithInterceptorFuncs(storageInterceptor).
 		Build()
 
 	handler := NewCreateCatalogHandler(
@@ -253,6 +253,12 @@ func (h *ScanSBOMHandler) Handle(ctx context.Context, message messaging.Message)
 			api.LabelPartOfKey:          api.LabelPartOfValue,
 		}
 
+		// An existing report holds the results of the previous scan, which are about to be overwritten.
+		if !vulnerabilityReport.CreationTimestamp.IsZero() {
+			change := vulnReport.ComputeChange(vulnerabilityReport.Report.Results, results)
+			vulnerabilityReport.LastChange = &change
+		}
+
 		vulnerabilityReport.ImageMetadata = sbom.GetImageMetadata()
 		vulnerabilityReport.Report = storagev1alpha1.Report{
 			Summary: summary,
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	storagev1alpha1 "github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
@@ -194,6 +195,8 @@ func testScanSBOM(t *testing.T, cacheDir, platform, sourceSBOMJSON, expectedRepo
 	assert.Equal(t, sbom.GetImageMetadata(), vulnerabilityReport.GetImageMetadata())
 	assert.Equal(t, sbom.UID, vulnerabilityReport.GetOwnerReferences()[0].UID)
 	assert.Equal(t, string(scanJob.UID), vulnerabilityReport.Labels[v1alpha1.LabelScanJobUIDKey])
+	// The first scan of the image has no previous report to compare with.
+	assert.Nil(t, vulnerabilityReport.LastChange)
 
 	report := &vulnerabilityReport.Report
 	require.NotEmpty(t, report)
@@ -204,6 +207,150 @@ func testScanSBOM(t *testing.T, cacheDir, platform, sourceSBOMJSON, expectedRepo
 	assert.Equal(t, expectedReport, report)
 }
 
+func TestScanSBOMHandler_Handle_Rescan(t *testing.T) {
+	spdxData, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	require.NoError(t, err)
+	reportData, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.sbomscanner.json"))
+	require.NoError(t, err)
+
+	scanJob := &v1alpha1.ScanJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-scanjob",
+			Namespace: "default",
+			UID:       "test-scanjob-uid",
+		},
+		Spec: v1alpha1.ScanJobSpec{
+			Registry: "test-registry",
+		},
+	}
+
+	sbom := &storagev1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sbom",
+			Namespace: "default",
+		},
+		SPDX: runtime.RawExtension{Raw: spdxData},
+	}
+
+	// The previous scan did not find the first vulnerability, found the second one with another severity,
+	// and found a vulnerability that is no longer found.
+	previousReport := &storagev1alpha1.Report{}
+	require.NoError(t, json.Unmarshal(reportData, previousReport))
+	resultIndex := slices.IndexFunc(previousReport.Results, func(result storagev1alpha1.Result) bool {
+		return len(result.Vulnerabilities) >= 2
+	})
+	require.GreaterOrEqual(t, resultIndex, 0, "the report has no result with two vulnerabilities")
+	result := &previousReport.Results[resultIndex]
+
+	addedVulnerability := result.Vulnerabilities[0]
+	changedVulnerability := result.Vulnerabilities[1]
+	previousSeverity := "UNKNOWN"
+	if changedVulnerability.Severity == previousSeverity {
+		previousSeverity = "LOW"
+	}
+	resolvedVulnerability := storagev1alpha1.Vulnerability{
+		CVE:              "CVE-0000-0000",
+		PackageName:      addedVulnerability.PackageName,
+		PURL:             addedVulnerability.PURL,
+		InstalledVersion: addedVulnerability.InstalledVersion,
+		Severity:         "HIGH",
+	}
+	for i := range previousReport.Results {
+		previousReport.Results[i].Vulnerabilities = slices.DeleteFunc(
+			previousReport.Results[i].Vulnerabilities,
+			func(vulnerability storagev1alpha1.Vulnerability) bool {
+				return sameVulnerability(vulnerability, addedVulnerability)
+			},
+		)
+		for j, vulnerability := range previousReport.Results[i].Vulnerabilities {
+			if sameVulnerability(vulnerability, changedVulnerability) {
+				previousReport.Results[i].Vulnerabilities[j].Severity = previousSeverity
+			}
+		}
+	}
+	result.Vulnerabilities = append(result.Vulnerabilities, resolvedVulnerability)
+
+	existingReport := &storagev1alpha1.VulnerabilityReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              sbom.Name,
+			Namespace:         sbom.Namespace,
+			CreationTimestamp: metav1.Now(),
+		},
+		Report: *previousReport,
+	}
+
+	scheme := scheme.Scheme
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(scanJob, sbom, existingReport, &v1alpha1.VEXHubList{}).
+		Build()
+
+	handler := NewScanSBOMHandler(k8sClient, scheme, t.TempDir(), testTrivyDBRepository, testTrivyJavaDBRepository, slog.Default())
+
+	message, err := json.Marshal(&ScanSBOMMessage{
+		BaseMessage: BaseMessage{
+			ScanJob: ObjectRef{
+				Name:      scanJob.Name,
+				Namespace: scanJob.Namespace,
+				UID:       string(scanJob.UID),
+			},
+		},
+		SBOM: ObjectRef{
+			Name:      sbom.Name,
+			Namespace: sbom.Namespace,
+		},
+	})
+	require.NoError(t, err)
+
+	err = handler.Handle(t.Context(), &testMessage{data: message})
+	require.NoError(t, err)
+
+	vulnerabilityReport := &storagev1alpha1.VulnerabilityReport{}
+	err = k8sClient.Get(t.Context(), client.ObjectKey{
+		Name:      sbom.Name,
+		Namespace: sbom.Namespace,
+	}, vulnerabilityReport)
+	require.NoError(t, err)
+
+	require.NotNil(t, vulnerabilityReport.LastChange)
+	assert.Equal(t, []storagev1alpha1.VulnerabilityChange{
+		{
+			CVE:         addedVulnerability.CVE,
+			PackageName: addedVulnerability.PackageName,
+			PURL:        addedVulnerability.PURL,
+			Severity:    addedVulnerability.Severity,
+			Suppressed:  addedVulnerability.Suppressed,
+			VEXStatus:   addedVulnerability.VEXStatus,
+		},
+	}, vulnerabilityReport.LastChange.Added)
+	assert.Equal(t, []storagev1alpha1.VulnerabilityChange{
+		{
+			CVE:         resolvedVulnerability.CVE,
+			PackageName: resolvedVulnerability.PackageName,
+			PURL:        resolvedVulnerability.PURL,
+			Severity:    resolvedVulnerability.Severity,
+		},
+	}, vulnerabilityReport.LastChange.Resolved)
+	assert.Equal(t, []storagev1alpha1.VulnerabilityChange{
+		{
+			CVE:              changedVulnerability.CVE,
+			PackageName:      changedVulnerability.PackageName,
+			PURL:             changedVulnerability.PURL,
+			Severity:         changedVulnerability.Severity,
+			Suppressed:       changedVulnerability.Suppressed,
+			VEXStatus:        changedVulnerability.VEXStatus,
+			PreviousSeverity: previousSeverity,
+		},
+	}, vulnerabilityReport.LastChange.Changed)
+}
+
+// sameVulnerability reports whether the vulnerabilities are the same vulnerability of the same package.
+func sameVulnerability(a, b storagev1alpha1.Vulnerability) bool {
+	return a.CVE == b.CVE && a.PackageName == b.PackageName && a.PURL == b.PURL
+}
+
 func fakeVEXHubRepository(t *testing.T) *httptest.Server {
 	handler := http.FileServer(http.Dir("../../test/fixtures/vexhub"))
 	server := httptest.NewUnstartedServer(handler)
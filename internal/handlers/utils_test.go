@@ -10,6 +10,10 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/registry"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	storagev1alpha1 "github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 )
 
 const (
@@ -41,6 +45,49 @@ func (m *testMessage) InProgress() error {
 	return nil
 }
 
+// storageInterceptor serves the storage requests the fake client does not:
+// it creates the images of the create-only ImageBatches one at a time,
+// and deletes the collections of images matching the field selectors, which the fake client ignores.
+var storageInterceptor = interceptor.Funcs{
+	Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+		imageBatch, ok := obj.(*storagev1alpha1.ImageBatch)
+		if !ok {
+			return c.Create(ctx, obj, opts...)
+		}
+
+		for _, image := range imageBatch.Spec.Images {
+			image.Namespace = imageBatch.Namespace
+			if err := c.Create(ctx, &image, opts...); err != nil {
+				return err
+			}
+			imageBatch.Status.Created++
+		}
+		imageBatch.Spec.Images = nil
+
+		return nil
+	},
+	DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+		if _, ok := obj.(*storagev1alpha1.Image); !ok {
+			return c.DeleteAllOf(ctx, obj, opts...)
+		}
+
+		deleteAllOfOptions := &client.DeleteAllOfOptions{}
+		deleteAllOfOptions.ApplyOptions(opts)
+
+		images := &storagev1alpha1.ImageList{}
+		if err := c.List(ctx, images, &deleteAllOfOptions.ListOptions); err != nil {
+			return err
+		}
+		for _, image := range images.Items {
+			if err := c.Delete(ctx, &image, &deleteAllOfOptions.DeleteOptions); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
 type testPrivateRegistry struct {
 	registry    *registry.RegistryContainer
 	registryURL string
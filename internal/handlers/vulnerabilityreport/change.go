@@ -0,0 +1,91 @@
+package vulnerabilityreport
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+
+	storagev1alpha1 "github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// vulnerabilityKey identifies a vulnerability of a package across the scans of an image.
+type vulnerabilityKey struct {
+	cve         string
+	packageName string
+	purl        string
+}
+
+// ComputeChange returns the vulnerabilities added, resolved and changed between
+// the results of the previous scan of an image and the results of the current one.
+// A vulnerability found in several results is compared once, by its first occurrence.
+// The changes are sorted by CVE, package name and PURL.
+func ComputeChange(previous, current []storagev1alpha1.Result) storagev1alpha1.ReportChange {
+	previousVulnerabilities := indexVulnerabilities(previous)
+	currentVulnerabilities := indexVulnerabilities(current)
+
+	var change storagev1alpha1.ReportChange
+	for key, vulnerability := range currentVulnerabilities {
+		previousVulnerability, found := previousVulnerabilities[key]
+		if !found {
+			change.Added = append(change.Added, newVulnerabilityChange(vulnerability))
+			continue
+		}
+
+		if vulnerability.Severity != previousVulnerability.Severity ||
+			vulnerability.Suppressed != previousVulnerability.Suppressed ||
+			!reflect.DeepEqual(vulnerability.VEXStatus, previousVulnerability.VEXStatus) {
+			vulnerabilityChange := newVulnerabilityChange(vulnerability)
+			vulnerabilityChange.PreviousSeverity = previousVulnerability.Severity
+			vulnerabilityChange.PreviousSuppressed = previousVulnerability.Suppressed
+			vulnerabilityChange.PreviousVEXStatus = previousVulnerability.VEXStatus
+			change.Changed = append(change.Changed, vulnerabilityChange)
+		}
+	}
+	for key, vulnerability := range previousVulnerabilities {
+		if _, found := currentVulnerabilities[key]; !found {
+			change.Resolved = append(change.Resolved, newVulnerabilityChange(vulnerability))
+		}
+	}
+
+	for _, changes := range [][]storagev1alpha1.VulnerabilityChange{change.Added, change.Resolved, change.Changed} {
+		slices.SortFunc(changes, func(a, b storagev1alpha1.VulnerabilityChange) int {
+			return cmp.Or(
+				cmp.Compare(a.CVE, b.CVE),
+				cmp.Compare(a.PackageName, b.PackageName),
+				cmp.Compare(a.PURL, b.PURL),
+			)
+		})
+	}
+
+	return change
+}
+
+// indexVulnerabilities returns the first occurrence of each vulnerability of the results.
+func indexVulnerabilities(results []storagev1alpha1.Result) map[vulnerabilityKey]storagev1alpha1.Vulnerability {
+	vulnerabilities := map[vulnerabilityKey]storagev1alpha1.Vulnerability{}
+	for _, result := range results {
+		for _, vulnerability := range result.Vulnerabilities {
+			key := vulnerabilityKey{
+				cve:         vulnerability.CVE,
+				packageName: vulnerability.PackageName,
+				purl:        vulnerability.PURL,
+			}
+			if _, found := vulnerabilities[key]; !found {
+				vulnerabilities[key] = vulnerability
+			}
+		}
+	}
+
+	return vulnerabilities
+}
+
+func newVulnerabilityChange(vulnerability storagev1alpha1.Vulnerability) storagev1alpha1.VulnerabilityChange {
+	return storagev1alpha1.VulnerabilityChange{
+		CVE:         vulnerability.CVE,
+		PackageName: vulnerability.PackageName,
+		PURL:        vulnerability.PURL,
+		Severity:    vulnerability.Severity,
+		Suppressed:  vulnerability.Suppressed,
+		VEXStatus:   vulnerability.VEXStatus,
+	}
+}
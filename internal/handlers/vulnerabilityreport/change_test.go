@@ -0,0 +1,102 @@
+package vulnerabilityreport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storagev1alpha1 "github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func TestComputeChange(t *testing.T) {
+	notAffected := &storagev1alpha1.VEXStatus{Repository: "https://vex.test", Status: "not_affected"}
+	underInvestigation := &storagev1alpha1.VEXStatus{Repository: "https://vex.test", Status: "under_investigation"}
+
+	previous := []storagev1alpha1.Result{
+		{
+			Target: "test (debian 12)",
+			Vulnerabilities: []storagev1alpha1.Vulnerability{
+				{CVE: "CVE-2024-0001", PackageName: "libc6", PURL: "pkg:deb/debian/libc6@2.36", Severity: "HIGH"},
+				{CVE: "CVE-2024-0002", PackageName: "libc6", PURL: "pkg:deb/debian/libc6@2.36", Severity: "MEDIUM"},
+				{
+					CVE:         "CVE-2024-0003",
+					PackageName: "openssl",
+					PURL:        "pkg:deb/debian/openssl@3.0",
+					Severity:    "LOW",
+					VEXStatus:   underInvestigation,
+				},
+				{CVE: "CVE-2024-0004", PackageName: "zlib1g", PURL: "pkg:deb/debian/zlib1g@1.2", Severity: "CRITICAL"},
+			},
+		},
+	}
+	current := []storagev1alpha1.Result{
+		{
+			Target: "test (debian 12)",
+			Vulnerabilities: []storagev1alpha1.Vulnerability{
+				{CVE: "CVE-2024-0001", PackageName: "libc6", PURL: "pkg:deb/debian/libc6@2.36", Severity: "CRITICAL"},
+				{
+					CVE:         "CVE-2024-0003",
+					PackageName: "openssl",
+					PURL:        "pkg:deb/debian/openssl@3.0",
+					Severity:    "LOW",
+					Suppressed:  true,
+					VEXStatus:   notAffected,
+				},
+				{CVE: "CVE-2024-0004", PackageName: "zlib1g", PURL: "pkg:deb/debian/zlib1g@1.2", Severity: "CRITICAL"},
+			},
+		},
+		{
+			Target: "app",
+			Vulnerabilities: []storagev1alpha1.Vulnerability{
+				{CVE: "CVE-2025-0005", PackageName: "stdlib", PURL: "pkg:golang/stdlib@v1.23.4", Severity: "MEDIUM"},
+				{CVE: "CVE-2024-0002", PackageName: "stdlib", PURL: "pkg:golang/stdlib@v1.23.4", Severity: "MEDIUM"},
+				// A vulnerability found in several results is compared once.
+				{CVE: "CVE-2025-0005", PackageName: "stdlib", PURL: "pkg:golang/stdlib@v1.23.4", Severity: "MEDIUM"},
+			},
+		},
+	}
+
+	expected := storagev1alpha1.ReportChange{
+		Added: []storagev1alpha1.VulnerabilityChange{
+			{CVE: "CVE-2024-0002", PackageName: "stdlib", PURL: "pkg:golang/stdlib@v1.23.4", Severity: "MEDIUM"},
+			{CVE: "CVE-2025-0005", PackageName: "stdlib", PURL: "pkg:golang/stdlib@v1.23.4", Severity: "MEDIUM"},
+		},
+		Resolved: []storagev1alpha1.VulnerabilityChange{
+			{CVE: "CVE-2024-0002", PackageName: "libc6", PURL: "pkg:deb/debian/libc6@2.36", Severity: "MEDIUM"},
+		},
+		Changed: []storagev1alpha1.VulnerabilityChange{
+			{
+				CVE:              "CVE-2024-0001",
+				PackageName:      "libc6",
+				PURL:             "pkg:deb/debian/libc6@2.36",
+				Severity:         "CRITICAL",
+				PreviousSeverity: "HIGH",
+			},
+			{
+				CVE:               "CVE-2024-0003",
+				PackageName:       "openssl",
+				PURL:              "pkg:deb/debian/openssl@3.0",
+				Severity:          "LOW",
+				Suppressed:        true,
+				VEXStatus:         notAffected,
+				PreviousSeverity:  "LOW",
+				PreviousVEXStatus: underInvestigation,
+			},
+		},
+	}
+
+	assert.Equal(t, expected, ComputeChange(previous, current))
+}
+
+func TestComputeChangeUnchanged(t *testing.T) {
+	results := []storagev1alpha1.Result{
+		{
+			Target: "test (debian 12)",
+			Vulnerabilities: []storagev1alpha1.Vulnerability{
+				{CVE: "CVE-2024-0001", PackageName: "libc6", PURL: "pkg:deb/debian/libc6@2.36", Severity: "HIGH"},
+			},
+		},
+	}
+
+	assert.Equal(t, storagev1alpha1.ReportChange{}, ComputeChange(results, results))
+}
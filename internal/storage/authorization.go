@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// authorizedNamespaces returns the namespaces where the user of the request is allowed to list the resource,
+// for the cluster-scoped resources computed from the objects of every namespace.
+// The resource is one of the object tables, and only the namespaces with stored objects are returned.
+// It returns all as true, and no namespaces, if the user is allowed to list the resource in all the namespaces.
+func authorizedNamespaces(
+	ctx context.Context,
+	authz authorizer.Authorizer,
+	q querier,
+	resource string,
+) (namespaces []string, all bool, err error) {
+	allowed, err := authorizeList(ctx, authz, resource, "")
+	if err != nil || allowed {
+		return nil, allowed, err
+	}
+
+	query, args, err := psql.Select(
+		sm.Distinct(),
+		sm.Columns(psql.Quote("namespace")),
+		sm.From(psql.Quote(resource)),
+	).Build(ctx)
+	if err != nil {
+		return nil, false, apierrors.NewInternalError(err)
+	}
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, false, apierrors.NewInternalError(fmt.Errorf("failed to query the namespaces of %s: %w", resource, err))
+	}
+	storedNamespaces, err := collectRows(rows, func(row row) (string, error) {
+		var namespace string
+		err := row.Scan(&namespace)
+		return namespace, err
+	})
+	if err != nil {
+		return nil, false, apierrors.NewInternalError(fmt.Errorf("failed to scan the namespaces of %s: %w", resource, err))
+	}
+
+	namespaces = []string{}
+	for _, namespace := range storedNamespaces {
+		allowed, err := authorizeList(ctx, authz, resource, namespace)
+		if err != nil {
+			return nil, false, err
+		}
+		if allowed {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	return namespaces, false, nil
+}
+
+// authorizeNamespace checks that the user of the request is allowed to list the resource in the namespace.
+func authorizeNamespace(ctx context.Context, authz authorizer.Authorizer, resource, namespace string) error {
+	allowed, err := authorizeList(ctx, authz, resource, namespace)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return apierrors.NewForbidden(
+			v1alpha1.Resource(resource),
+			"",
+			fmt.Errorf("the user cannot list %s in the namespace %q", resource, namespace),
+		)
+	}
+
+	return nil
+}
+
+// authorizeList reports whether the user of the request is allowed to list the resource in the namespace,
+// or in all the namespaces if the namespace is empty.
+func authorizeList(ctx context.Context, authz authorizer.Authorizer, resource, namespace string) (bool, error) {
+	requestUser, ok := genericapirequest.UserFrom(ctx)
+	if !ok {
+		return false, apierrors.NewForbidden(v1alpha1.Resource(resource), "", errors.New("the user of the request is unknown"))
+	}
+
+	decision, _, err := authz.Authorize(ctx, authorizer.AttributesRecord{
+		User:            requestUser,
+		Verb:            "list",
+		Namespace:       namespace,
+		APIGroup:        v1alpha1.GroupName,
+		APIVersion:      v1alpha1.SchemeGroupVersion.Version,
+		Resource:        resource,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return false, apierrors.NewInternalError(fmt.Errorf("failed to authorize the %s of namespace %q: %w", resource, namespace, err))
+	}
+
+	return decision == authorizer.DecisionAllow, nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func (suite *storeTestSuite) TestAuthorizedNamespaces() {
+	for _, namespace := range []string{"default", "private", "team"} {
+		sbom := &v1alpha1.SBOM{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace}}
+		err := suite.store.Create(context.Background(), keyPrefix+"/"+namespace+"/test", sbom, &v1alpha1.SBOM{}, 0)
+		suite.Require().NoError(err)
+	}
+
+	// The admin user can list the SBOMs of every namespace, the other users the ones of the default and team namespaces.
+	authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if a.GetVerb() != "list" || a.GetResource() != "sboms" || a.GetAPIGroup() != v1alpha1.GroupName {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+		if a.GetUser().GetName() == "admin" || a.GetNamespace() == "default" || a.GetNamespace() == "team" {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "not allowed", nil
+	})
+	namespaces, all, err := authorizedNamespaces(contextAs("admin"), authz, suite.db, "sboms")
+	suite.Require().NoError(err)
+	suite.True(all)
+	suite.Nil(namespaces)
+
+	namespaces, all, err = authorizedNamespaces(contextAs("reader"), authz, suite.db, "sboms")
+	suite.Require().NoError(err)
+	suite.False(all)
+	suite.ElementsMatch([]string{"default", "team"}, namespaces)
+
+	namespaces, all, err = authorizedNamespaces(contextAs("reader"), authz, suite.db, "images")
+	suite.Require().NoError(err)
+	suite.False(all)
+	suite.Empty(namespaces)
+
+	suite.Require().NoError(authorizeNamespace(contextAs("reader"), authz, "sboms", "team"))
+	err = authorizeNamespace(contextAs("reader"), authz, "sboms", "private")
+	suite.True(apierrors.IsForbidden(err), "unexpected error: %v", err)
+
+	_, _, err = authorizedNamespaces(context.Background(), authz, suite.db, "sboms")
+	suite.True(apierrors.IsForbidden(err), "unexpected error: %v", err)
+}
+
+// contextAs returns a request context of the given user.
+func contextAs(userName string) context.Context {
+	return genericapirequest.WithUser(context.Background(), &user.DefaultInfo{Name: userName})
+}
@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// blobDeletionsBatchSize is the number of blobs deleted from the blob store in each transaction.
+	blobDeletionsBatchSize = 100
+	// DefaultBlobSweepInterval is the default interval between the deletions of the unreferenced blobs.
+	DefaultBlobSweepInterval = time.Minute
+	// blobUploadsGracePeriod is the time after which the blobs uploaded and not referenced are deleted.
+	// It is longer than any write transaction, so that the uploads are not deleted before being referenced.
+	blobUploadsGracePeriod = time.Hour
+)
+
+// errBlobNotFound is returned when a blob does not exist in the blob store.
+var errBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores the large payloads of the objects outside of the database,
+// which keeps only their location.
+// The locations are never reused, so that a deleted blob is never confused with a newer one.
+type BlobStore interface {
+	// Put stores the content at the location.
+	Put(ctx context.Context, location string, content []byte) error
+	// Get returns the content stored at the location.
+	Get(ctx context.Context, location string) ([]byte, error)
+	// Delete deletes the content stored at the location. Deleting a missing location is not an error.
+	Delete(ctx context.Context, location string) error
+}
+
+// S3BlobStoreOptions configure the bucket of an S3BlobStore.
+type S3BlobStoreOptions struct {
+	// Endpoint is the URL of the S3-compatible service.
+	// It defaults to the AWS endpoint of the region.
+	Endpoint string
+	// Bucket is the name of the bucket the blobs are stored in.
+	Bucket string
+	// Prefix is prepended to the locations of the blobs to get their keys, so that the bucket can be shared.
+	// Like the bucket, it must not change once blobs have been stored.
+	Prefix string
+	// PathStyle addresses the bucket in the path of the requests instead of the host,
+	// as required by most of the S3-compatible services.
+	PathStyle bool
+}
+
+var _ BlobStore = &S3BlobStore{}
+
+// S3BlobStore stores the blobs in a bucket of an S3-compatible object storage.
+// The requests are signed with the credentials and region of the AWS configuration.
+type S3BlobStore struct {
+	client  *s3.Client
+	options S3BlobStoreOptions
+}
+
+// NewS3BlobStore returns a blob store writing to the bucket with the given AWS configuration.
+func NewS3BlobStore(config aws.Config, options S3BlobStoreOptions) (*S3BlobStore, error) {
+	if options.Bucket == "" {
+		return nil, errors.New("the bucket of the blob store is required")
+	}
+	if config.Region == "" {
+		return nil, errors.New("the region of the blob store is required")
+	}
+	if options.Endpoint != "" {
+		endpoint, err := url.Parse(options.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob store endpoint %q: %w", options.Endpoint, err)
+		}
+		if endpoint.Scheme == "" || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid blob store endpoint %q: the scheme and host are required", options.Endpoint)
+		}
+	}
+
+	client := s3.NewFromConfig(config, func(o *s3.Options) {
+		if options.Endpoint != "" {
+			o.BaseEndpoint = aws.String(options.Endpoint)
+		}
+		o.UsePathStyle = options.PathStyle
+	})
+
+	return &S3BlobStore{
+		client:  client,
+		options: options,
+	}, nil
+}
+
+// Put stores the content in the bucket under the location.
+func (s *S3BlobStore) Put(ctx context.Context, location string, content []byte) error {
+	if _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.options.Bucket),
+		Key:           aws.String(s.key(location)),
+		Body:          bytes.NewReader(content),
+		ContentLength: aws.Int64(int64(len(content))),
+	}); err != nil {
+		return fmt.Errorf("failed to put blob %q: %w", location, err)
+	}
+
+	return nil
+}
+
+// Get returns the content stored in the bucket under the location.
+func (s *S3BlobStore) Get(ctx context.Context, location string) ([]byte, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.options.Bucket),
+		Key:    aws.String(s.key(location)),
+	})
+	if isS3NotFound(err) {
+		return nil, fmt.Errorf("failed to get blob %q: %w", location, errBlobNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob %q: %w", location, err)
+	}
+	defer output.Body.Close()
+
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %q: %w", location, err)
+	}
+
+	return content, nil
+}
+
+// Delete deletes the content stored in the bucket under the location.
+func (s *S3BlobStore) Delete(ctx context.Context, location string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.options.Bucket),
+		Key:    aws.String(s.key(location)),
+	})
+	if err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("failed to delete blob %q: %w", location, err)
+	}
+
+	return nil
+}
+
+// key returns the key of the object at the location.
+func (s *S3BlobStore) key(location string) string {
+	return s.options.Prefix + location
+}
+
+// isS3NotFound reports whether the request failed because the object does not exist.
+// The status is checked instead of the NoSuchKey error code, as some S3-compatible services answer without an error body.
+func isS3NotFound(err error) bool {
+	var responseError *awshttp.ResponseError
+	return errors.As(err, &responseError) && responseError.HTTPStatusCode() == http.StatusNotFound
+}
+
+// BlobSweeper periodically deletes from the blob store the blobs no longer referenced by the database.
+// The deletions are recorded in the blob_deletions table in the transaction deleting the reference,
+// then deleted from the blob store, which cannot take part in the transaction.
+// The uploads never referenced, such as the ones of the rolled back transactions, are deleted after a grace period.
+type BlobSweeper struct {
+	db       Database
+	blobs    BlobStore
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewBlobSweeper creates a new BlobSweeper.
+func NewBlobSweeper(db Database, blobs BlobStore, interval time.Duration, logger *slog.Logger) *BlobSweeper {
+	return &BlobSweeper{
+		db:       db,
+		blobs:    blobs,
+		interval: interval,
+		logger:   logger.With("component", "blob-sweeper"),
+	}
+}
+
+// Start runs the deletions periodically until the context is canceled.
+func (s *BlobSweeper) Start(ctx context.Context) {
+	s.logger.InfoContext(ctx, "Starting blob sweeper", "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.Sweep(ctx)
+			if err != nil {
+				s.logger.ErrorContext(ctx, "failed to delete unreferenced blobs", "deleted", deleted, "error", err)
+				continue
+			}
+			s.logger.DebugContext(ctx, "Deleted unreferenced blobs", "deleted", deleted)
+		}
+	}
+}
+
+// Sweep deletes the unreferenced blobs from the blob store and returns the number of deleted blobs.
+func (s *BlobSweeper) Sweep(ctx context.Context) (int, error) {
+	if err := s.expireUploads(ctx, blobUploadsGracePeriod); err != nil {
+		return 0, err
+	}
+
+	return runBatches(blobDeletionsBatchSize, func() (int, error) {
+		return s.sweepBatch(ctx)
+	})
+}
+
+// expireUploads records the deletion of the uploads started before the grace period and never referenced.
+func (s *BlobSweeper) expireUploads(ctx context.Context, gracePeriod time.Duration) error {
+	if _, err := s.db.Exec(ctx, `
+WITH expired AS (
+	DELETE FROM blob_uploads
+	WHERE started_at < now() - make_interval(secs => $1)
+	RETURNING location
+)
+INSERT INTO blob_deletions (location)
+SELECT location FROM expired
+ON CONFLICT DO NOTHING`,
+		gracePeriod.Seconds(),
+	); err != nil {
+		return fmt.Errorf("failed to expire blob uploads: %w", err)
+	}
+
+	return nil
+}
+
+// sweepBatch deletes a batch of unreferenced blobs.
+// A blob whose deletion fails is kept in the blob_deletions table and retried by the next sweep.
+func (s *BlobSweeper) sweepBatch(ctx context.Context) (int, error) {
+	tx, err := s.db.begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	rows, err := tx.Query(
+		ctx,
+		"SELECT location FROM blob_deletions ORDER BY location LIMIT $1 FOR UPDATE SKIP LOCKED",
+		blobDeletionsBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query blob deletions: %w", err)
+	}
+	locations, err := collectRows(rows, func(row row) (string, error) {
+		var location string
+		err := row.Scan(&location)
+		return location, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan blob deletions: %w", err)
+	}
+
+	for _, location := range locations {
+		if err = s.blobs.Delete(ctx, location); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM blob_deletions WHERE location = ANY($1)", locations); err != nil {
+		return 0, fmt.Errorf("failed to delete blob deletions: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(locations), nil
+}
+
+// runBatches runs the batch until it processes less than the batch size, and returns the number of processed rows.
+func runBatches(batchSize int, batch func() (int, error)) (int, error) {
+	total := 0
+	for {
+		count, err := batch()
+		if err != nil {
+			return total, err
+		}
+		total += count
+
+		if count < batchSize {
+			return total, nil
+		}
+	}
+}
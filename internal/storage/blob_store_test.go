@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Server is an in-memory stand-in for an S3-compatible service, addressed in path style.
+// It checks that the requests are signed and that their payload matches its hash, when the payload is signed.
+type fakeS3Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3Server(t *testing.T) *fakeS3Server {
+	t.Helper()
+
+	s := &fakeS3Server{objects: map[string][]byte{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *fakeS3Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-access-key/") {
+		writeS3Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeS3Error(w, "InternalError", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body)
+	if payloadHash := r.Header.Get("X-Amz-Content-Sha256"); payloadHash != "UNSIGNED-PAYLOAD" &&
+		payloadHash != hex.EncodeToString(sum[:]) {
+		writeS3Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = body
+	case http.MethodGet:
+		content, ok := s.objects[r.URL.Path]
+		if !ok {
+			writeS3Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeS3Error writes an error response with the code, in the XML format of S3.
+func writeS3Error(w http.ResponseWriter, code string, status int) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+// keys returns the keys of the stored objects, with the bucket.
+func (s *fakeS3Server) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// newBlobStore returns a blob store writing to the bucket of the server.
+func (s *fakeS3Server) newBlobStore(t *testing.T, prefix string) *S3BlobStore {
+	t.Helper()
+
+	blobs, err := NewS3BlobStore(
+		aws.Config{
+			Region:      "us-east-1",
+			Credentials: credentials.NewStaticCredentialsProvider("test-access-key", "test-secret-key", ""),
+		},
+		S3BlobStoreOptions{
+			Endpoint:  s.URL,
+			Bucket:    "test-bucket",
+			Prefix:    prefix,
+			PathStyle: true,
+		},
+	)
+	require.NoError(t, err)
+
+	return blobs
+}
+
+func TestS3BlobStore(t *testing.T) {
+	server := newFakeS3Server(t)
+	blobs := server.newBlobStore(t, "sbomscanner/")
+	ctx := context.Background()
+
+	require.NoError(t, blobs.Put(ctx, "spdx/test", []byte("content")))
+	assert.Equal(t, []string{"/test-bucket/sbomscanner/spdx/test"}, server.keys())
+
+	content, err := blobs.Get(ctx, "spdx/test")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("content"), content)
+
+	require.NoError(t, blobs.Delete(ctx, "spdx/test"))
+	assert.Empty(t, server.keys())
+
+	_, err = blobs.Get(ctx, "spdx/test")
+	require.ErrorIs(t, err, errBlobNotFound)
+
+	// Deleting a missing blob is not an error, so that the deletions can be retried.
+	require.NoError(t, blobs.Delete(ctx, "spdx/test"))
+}
+
+func TestS3BlobStoreError(t *testing.T) {
+	server := newFakeS3Server(t)
+
+	blobs, err := NewS3BlobStore(
+		aws.Config{
+			Region:      "us-east-1",
+			Credentials: credentials.NewStaticCredentialsProvider("other-access-key", "test-secret-key", ""),
+		},
+		S3BlobStoreOptions{Endpoint: server.URL, Bucket: "test-bucket", PathStyle: true},
+	)
+	require.NoError(t, err)
+
+	err = blobs.Put(context.Background(), "spdx/test", []byte("content"))
+	require.ErrorContains(t, err, "StatusCode: 403")
+	require.ErrorContains(t, err, "api error AccessDenied")
+}
+
+func TestNewS3BlobStore(t *testing.T) {
+	tests := []struct {
+		name              string
+		region            string
+		options           S3BlobStoreOptions
+		expectedEndpoint  string
+		expectedPathStyle bool
+		expectedError     string
+	}{
+		{
+			name:    "AWS endpoint of the region",
+			region:  "eu-west-1",
+			options: S3BlobStoreOptions{Bucket: "test-bucket"},
+		},
+		{
+			name:              "path style",
+			region:            "us-east-1",
+			options:           S3BlobStoreOptions{Endpoint: "http://minio:9000", Bucket: "test-bucket", PathStyle: true},
+			expectedEndpoint:  "http://minio:9000",
+			expectedPathStyle: true,
+		},
+		{
+			name:          "missing bucket",
+			region:        "us-east-1",
+			expectedError: "the bucket of the blob store is required",
+		},
+		{
+			name:          "missing region",
+			options:       S3BlobStoreOptions{Bucket: "test-bucket"},
+			expectedError: "the region of the blob store is required",
+		},
+		{
+			name:          "endpoint without scheme",
+			region:        "us-east-1",
+			options:       S3BlobStoreOptions{Endpoint: "minio:9000", Bucket: "test-bucket"},
+			expectedError: "the scheme and host are required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blobs, err := NewS3BlobStore(aws.Config{Region: test.region}, test.options)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			options := blobs.client.Options()
+			assert.Equal(t, test.expectedEndpoint, aws.ToString(options.BaseEndpoint))
+			assert.Equal(t, test.expectedPathStyle, options.UsePathStyle)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dm"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+)
+
+// DefaultWatchEventsRetention is the default amount of time the watch events are kept in the log.
+// Watches can be resumed from any resourceVersion recorded within this window.
+const DefaultWatchEventsRetention = 10 * time.Minute
+
+// Compactor periodically removes the events older than the retention from the watch_events table.
+// The latest event is always kept, so that the current resourceVersion survives the compaction.
+type Compactor struct {
+	db        Database
+	retention time.Duration
+	interval  time.Duration
+	logger    *slog.Logger
+}
+
+// NewCompactor creates a new Compactor.
+func NewCompactor(db Database, retention time.Duration, logger *slog.Logger) *Compactor {
+	return &Compactor{
+		db:        db,
+		retention: retention,
+		interval:  max(retention/10, time.Second),
+		logger:    logger.With("component", "compactor"),
+	}
+}
+
+// Start runs the compaction periodically until the context is canceled.
+func (c *Compactor) Start(ctx context.Context) {
+	c.logger.InfoContext(ctx, "Starting watch events compactor", "retention", c.retention, "interval", c.interval)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := c.Compact(ctx)
+			if err != nil {
+				c.logger.ErrorContext(ctx, "failed to compact watch events", "error", err)
+				continue
+			}
+			c.logger.DebugContext(ctx, "Compacted watch events", "deleted", deleted)
+		}
+	}
+}
+
+// Compact deletes the events older than the retention and returns the number of deleted events.
+// The events recorded exactly the retention ago are deleted too, as SQLite keeps the timestamps to the millisecond.
+func (c *Compactor) Compact(ctx context.Context) (int64, error) {
+	query, args, err := psql.Delete(
+		dm.From("watch_events"),
+		dm.Where(psql.Quote("created_at").LTE(c.db.dialect().ago(c.retention))),
+		dm.Where(psql.Quote("resource_version").LT(psql.Group(psql.Select(
+			sm.Columns(psql.Raw("MAX(resource_version)")),
+			sm.From("watch_events"),
+		)))),
+	).Build(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build compaction query: %w", err)
+	}
+
+	deleted, err := c.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete watch events: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,421 @@
+//nolint:wrapcheck // We want to return the errors from k8s.io/apiserver as they are.
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+var (
+	_ rest.Storage              = &cveStore{}
+	_ rest.Scoper               = &cveStore{}
+	_ rest.Getter               = &cveStore{}
+	_ rest.Lister               = &cveStore{}
+	_ rest.SingularNameProvider = &cveStore{}
+)
+
+// cveFieldColumns maps the CVE field selectors to the columns of the vulnerability_findings table.
+var cveFieldColumns = map[string]string{
+	"metadata.name":            "cve",
+	"affectedImages.namespace": "report_namespace",
+	"affectedImages.severity":  "severity",
+}
+
+// cveStore serves the read-only CVE resource.
+// The CVEs are computed from the vulnerability findings of the stored VulnerabilityReports.
+type cveStore struct {
+	db         Database
+	authorizer authorizer.Authorizer
+	logger     *slog.Logger
+	cveTableConvertor
+}
+
+// NewCVEStore returns a read-only store listing the images affected by each CVE.
+// The resource is cluster-scoped, so the authorizer restricts the affected images to the namespaces
+// where the user of the request is allowed to list the VulnerabilityReports.
+func NewCVEStore(db Database, authz authorizer.Authorizer, logger *slog.Logger) rest.Storage {
+	return &cveStore{
+		db:         db,
+		authorizer: authz,
+		logger:     logger.With("store", "cve"),
+	}
+}
+
+// New returns an empty CVE.
+func (s *cveStore) New() runtime.Object {
+	return &v1alpha1.CVE{}
+}
+
+// NewList returns an empty CVEList.
+func (s *cveStore) NewList() runtime.Object {
+	return &v1alpha1.CVEList{}
+}
+
+// Destroy cleans up the resources of the store.
+func (s *cveStore) Destroy() {
+	// Nothing to clean up, the database connection pool is shared with the other stores.
+}
+
+// NamespaceScoped returns false, as a CVE lists the affected images of all the namespaces.
+func (s *cveStore) NamespaceScoped() bool {
+	return false
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *cveStore) GetSingularName() string {
+	return "cve"
+}
+
+// Get returns the CVE with the given identifier.
+// It returns a NotFound error if no image the user can list the VulnerabilityReport of is affected by the CVE.
+func (s *cveStore) Get(ctx context.Context, name string, _ *metav1.GetOptions) (runtime.Object, error) {
+	s.logger.DebugContext(ctx, "Getting CVE", "name", name)
+
+	filters, err := s.namespaceFilters(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	if filters == nil {
+		return nil, apierrors.NewNotFound(v1alpha1.Resource("cves"), name)
+	}
+
+	cves, err := s.query(ctx, s.db, []string{name}, filters)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	if len(cves) == 0 {
+		return nil, apierrors.NewNotFound(v1alpha1.Resource("cves"), name)
+	}
+
+	return &cves[0], nil
+}
+
+// List returns the CVEs affecting at least one image, ordered by identifier.
+// The field selectors filter the affected images,
+// which are restricted to the namespaces where the user can list the VulnerabilityReports.
+// The list is at the resourceVersion of the snapshot it is read from.
+func (s *cveStore) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	label := labels.Everything()
+	field := fields.Everything()
+	var limit int64
+	var continueValue string
+	if options != nil {
+		if options.LabelSelector != nil {
+			label = options.LabelSelector
+		}
+		if options.FieldSelector != nil {
+			field = options.FieldSelector
+		}
+		limit = options.Limit
+		continueValue = options.Continue
+	}
+
+	s.logger.DebugContext(ctx, "Listing CVEs",
+		"labelSelector", label.String(),
+		"fieldSelector", field.String(),
+		"limit", limit,
+		"continue", continueValue,
+	)
+
+	filters, err := buildCVEFieldSelectorFilters(field)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	// Read the resourceVersion, the identifiers and the affected images from the same snapshot.
+	tx, err := s.db.beginSnapshot(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	resourceVersion, err := currentResourceVersion(ctx, tx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	list := &v1alpha1.CVEList{Items: []v1alpha1.CVE{}}
+	list.ResourceVersion = strconv.FormatUint(resourceVersion, 10)
+
+	// CVEs have no labels.
+	if !label.Matches(labels.Set{}) {
+		return list, nil
+	}
+
+	namespaceFilters, err := s.namespaceFilters(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if namespaceFilters == nil {
+		return list, nil
+	}
+	filters = append(filters, namespaceFilters...)
+
+	if continueValue != "" {
+		lastID, err := base64.RawURLEncoding.DecodeString(continueValue)
+		if err != nil {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid continue token: %v", err))
+		}
+		filters = append(filters, sm.Where(psql.Quote("f", "cve").GT(psql.Arg(string(lastID)))))
+	}
+
+	queryBuilder := psql.Select(
+		sm.Distinct(),
+		sm.Columns(psql.Quote("f", "cve")),
+		sm.From("vulnerability_findings").As("f"),
+		sm.OrderBy(psql.Quote("f", "cve")),
+	)
+	queryBuilder.Apply(filters...)
+	if limit > 0 {
+		// Fetch one more CVE to know whether there are more results.
+		queryBuilder.Apply(sm.Limit(limit + 1))
+	}
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	ids, err := collectRows(rows, func(row row) (string, error) {
+		var id string
+		err := row.Scan(&id)
+		return id, err
+	})
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	if limit > 0 && int64(len(ids)) > limit {
+		ids = ids[:limit]
+		list.Continue = base64.RawURLEncoding.EncodeToString([]byte(ids[len(ids)-1]))
+	}
+
+	if len(ids) == 0 {
+		return list, nil
+	}
+
+	list.Items, err = s.query(ctx, tx, ids, filters)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	return list, nil
+}
+
+// namespaceFilters returns the SQL filters restricting the vulnerability findings to the namespaces
+// where the user of the request can list the VulnerabilityReports.
+// It returns no filters, but an empty list, if the user can list them in all the namespaces,
+// and nil if the user cannot list them in any namespace.
+func (s *cveStore) namespaceFilters(ctx context.Context, q querier) ([]bob.Mod[*dialect.SelectQuery], error) {
+	namespaces, all, err := authorizedNamespaces(ctx, s.authorizer, q, "vulnerabilityreports")
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return []bob.Mod[*dialect.SelectQuery]{}, nil
+	}
+	if len(namespaces) == 0 {
+		return nil, nil
+	}
+
+	return []bob.Mod[*dialect.SelectQuery]{
+		sm.Where(psql.Raw("f.report_namespace = ANY(?)", namespaces)),
+	}, nil
+}
+
+// query returns the CVEs with the given identifiers, with the affected images matching the filters.
+// The CVEs without affected images are omitted.
+func (s *cveStore) query(
+	ctx context.Context,
+	q querier,
+	ids []string,
+	filters []bob.Mod[*dialect.SelectQuery],
+) ([]v1alpha1.CVE, error) {
+	queryBuilder := psql.Select(
+		sm.Columns(
+			psql.Quote("f", "cve"),
+			psql.Quote("c", "title"),
+			psql.Quote("c", "description"),
+			psql.Quote("c", "references"),
+			psql.Quote("c", "cvss"),
+			psql.Quote("f", "report_namespace"),
+			psql.Quote("f", "report_name"),
+			psql.Raw("r.object->'imageMetadata'"),
+			psql.Quote("f", "package_name"),
+			psql.Quote("f", "package_path"),
+			psql.Quote("f", "purl"),
+			psql.Quote("f", "installed_version"),
+			psql.Quote("f", "fixed_versions"),
+			psql.Quote("f", "severity"),
+			psql.Quote("f", "suppressed"),
+		),
+		sm.From("vulnerability_findings").As("f"),
+		sm.InnerJoin("cve_metadata").As("c").OnEQ(psql.Quote("c", "id"), psql.Quote("f", "cve")),
+		sm.InnerJoin("vulnerabilityreports").As("r").On(
+			psql.Quote("r", "name").EQ(psql.Quote("f", "report_name")),
+			psql.Quote("r", "namespace").EQ(psql.Quote("f", "report_namespace")),
+		),
+		sm.Where(psql.Raw("f.cve = ANY(?)", ids)),
+		sm.OrderBy(psql.Quote("f", "cve")),
+		sm.OrderBy(psql.Quote("f", "report_namespace")),
+		sm.OrderBy(psql.Quote("f", "report_name")),
+		sm.OrderBy(psql.Quote("f", "result_index")),
+		sm.OrderBy(psql.Quote("f", "vulnerability_index")),
+	)
+	queryBuilder.Apply(filters...)
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CVEs: %w", err)
+	}
+	defer rows.Close()
+
+	var cves []v1alpha1.CVE
+	for rows.Next() {
+		var cve v1alpha1.CVE
+		var affectedImage v1alpha1.AffectedImage
+		var references, cvss, imageMetadata []byte
+		if err = rows.Scan(
+			&cve.Name,
+			&cve.Title,
+			&cve.Description,
+			&references,
+			&cvss,
+			&affectedImage.Namespace,
+			&affectedImage.VulnerabilityReport,
+			&imageMetadata,
+			&affectedImage.PackageName,
+			&affectedImage.PackagePath,
+			&affectedImage.PURL,
+			&affectedImage.InstalledVersion,
+			&affectedImage.FixedVersions,
+			&affectedImage.Severity,
+			&affectedImage.Suppressed,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan CVE: %w", err)
+		}
+
+		if err = unmarshalNullable(imageMetadata, &affectedImage.ImageMetadata); err != nil {
+			return nil, err
+		}
+
+		if len(cves) == 0 || cves[len(cves)-1].Name != cve.Name {
+			if err = unmarshalNullable(references, &cve.References); err != nil {
+				return nil, err
+			}
+			if err = unmarshalNullable(cvss, &cve.CVSS); err != nil {
+				return nil, err
+			}
+			cves = append(cves, cve)
+		}
+
+		last := &cves[len(cves)-1]
+		last.AffectedImages = append(last.AffectedImages, affectedImage)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query CVEs: %w", err)
+	}
+
+	return cves, nil
+}
+
+// buildCVEFieldSelectorFilters builds the SQL filters on the vulnerability findings
+// from the provided CVE field selector.
+func buildCVEFieldSelectorFilters(fieldSelector fields.Selector) ([]bob.Mod[*dialect.SelectQuery], error) {
+	var filters []bob.Mod[*dialect.SelectQuery]
+	for _, req := range fieldSelector.Requirements() {
+		column, ok := cveFieldColumns[req.Field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported field selector: %s", req.Field)
+		}
+
+		switch req.Operator {
+		case selection.Equals, selection.DoubleEquals:
+			filters = append(filters, sm.Where(psql.Quote("f", column).EQ(psql.Arg(req.Value))))
+		case selection.NotEquals:
+			filters = append(filters, sm.Where(psql.Quote("f", column).NE(psql.Arg(req.Value))))
+		case selection.In, selection.NotIn, selection.Exists, selection.DoesNotExist, selection.GreaterThan, selection.LessThan:
+			return nil, fmt.Errorf("unsupported field selector operator: %v", req.Operator)
+		}
+	}
+
+	return filters, nil
+}
+
+type cveTableConvertor struct{}
+
+func (c cveTableConvertor) ConvertToTable(_ context.Context, obj runtime.Object, _ runtime.Object) (*metav1.Table, error) {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name", Description: "CVE identifier"},
+			{Name: "Images", Type: "integer", Description: "Number of affected images"},
+			{Name: "Namespaces", Type: "integer", Description: "Number of namespaces with affected images"},
+			{Name: "Title", Type: "string", Description: "Title of the vulnerability"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	// Handle both single object and list
+	var cves []v1alpha1.CVE
+	switch t := obj.(type) {
+	case *v1alpha1.CVEList:
+		cves = t.Items
+		table.ResourceVersion = t.ResourceVersion
+		table.Continue = t.Continue
+	case *v1alpha1.CVE:
+		cves = []v1alpha1.CVE{*t}
+	default:
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+
+	for _, cve := range cves {
+		images := sets.New[string]()
+		namespaces := sets.New[string]()
+		for _, affectedImage := range cve.AffectedImages {
+			images.Insert(affectedImage.Namespace + "/" + affectedImage.VulnerabilityReport)
+			namespaces.Insert(affectedImage.Namespace)
+		}
+
+		row := metav1.TableRow{
+			Object: runtime.RawExtension{Object: &cve},
+			Cells:  []interface{}{cve.Name, images.Len(), namespaces.Len(), cve.Title},
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
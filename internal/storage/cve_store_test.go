@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func (suite *storeTestSuite) createTestVulnerabilityReports() (*v1alpha1.VulnerabilityReport, *v1alpha1.VulnerabilityReport) {
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+
+	report1 := newTestVulnerabilityReport("test1", testVulnerability1, testVulnerability2)
+	err := vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		report1,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	report2 := newTestVulnerabilityReport("test2", testVulnerability1)
+	report2.Namespace = "other"
+	err = vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/other/test2",
+		report2,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	return report1, report2
+}
+
+// newCVEStore returns a CVE store whose admin user can list the VulnerabilityReports of every namespace,
+// and whose other users only the ones of the default namespace.
+func (suite *storeTestSuite) newCVEStore() *cveStore {
+	authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if a.GetVerb() != "list" || a.GetResource() != "vulnerabilityreports" || a.GetAPIGroup() != v1alpha1.GroupName {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+		if a.GetUser().GetName() == "admin" || a.GetNamespace() == "default" {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "not allowed", nil
+	})
+	cveStore, ok := NewCVEStore(suite.db, authz, slog.Default()).(*cveStore)
+	suite.Require().True(ok)
+
+	return cveStore
+}
+
+func newTestAffectedImage(report *v1alpha1.VulnerabilityReport, vulnerability v1alpha1.Vulnerability) v1alpha1.AffectedImage {
+	return v1alpha1.AffectedImage{
+		Namespace:           report.Namespace,
+		VulnerabilityReport: report.Name,
+		ImageMetadata:       report.ImageMetadata,
+		PackageName:         vulnerability.PackageName,
+		PackagePath:         vulnerability.PackagePath,
+		PURL:                vulnerability.PURL,
+		InstalledVersion:    vulnerability.InstalledVersion,
+		FixedVersions:       vulnerability.FixedVersions,
+		Severity:            vulnerability.Severity,
+		Suppressed:          vulnerability.Suppressed,
+	}
+}
+
+func (suite *storeTestSuite) TestCVEStoreGet() {
+	suite.requirePostgres()
+
+	report1, report2 := suite.createTestVulnerabilityReports()
+	cveStore := suite.newCVEStore()
+
+	obj, err := cveStore.Get(contextAs("admin"), testVulnerability1.CVE, &metav1.GetOptions{})
+	suite.Require().NoError(err)
+	suite.Equal(&v1alpha1.CVE{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testVulnerability1.CVE,
+		},
+		Title:       testVulnerability1.Title,
+		Description: testVulnerability1.Description,
+		References:  testVulnerability1.References,
+		CVSS:        testVulnerability1.CVSS,
+		AffectedImages: []v1alpha1.AffectedImage{
+			newTestAffectedImage(report1, testVulnerability1),
+			newTestAffectedImage(report2, testVulnerability1),
+		},
+	}, obj)
+
+	_, err = cveStore.Get(contextAs("admin"), "CVE-0000-0000", &metav1.GetOptions{})
+	suite.Require().Error(err)
+	suite.True(apierrors.IsNotFound(err))
+
+	// The images of the namespaces the user cannot list the reports of are not returned.
+	obj, err = cveStore.Get(contextAs("reader"), testVulnerability1.CVE, &metav1.GetOptions{})
+	suite.Require().NoError(err)
+	cve, ok := obj.(*v1alpha1.CVE)
+	suite.Require().True(ok)
+	suite.Equal([]v1alpha1.AffectedImage{newTestAffectedImage(report1, testVulnerability1)}, cve.AffectedImages)
+
+	_, err = cveStore.Get(context.Background(), testVulnerability1.CVE, &metav1.GetOptions{})
+	suite.True(apierrors.IsForbidden(err), "unexpected error: %v", err)
+}
+
+func (suite *storeTestSuite) TestCVEStoreList() {
+	suite.requirePostgres()
+
+	report1, report2 := suite.createTestVulnerabilityReports()
+	cveStore := suite.newCVEStore()
+	resourceVersion, err := currentResourceVersion(context.Background(), suite.db)
+	suite.Require().NoError(err)
+
+	tests := []struct {
+		name          string
+		user          string
+		fieldSelector fields.Selector
+		expectedCVEs  map[string][]v1alpha1.AffectedImage
+	}{
+		{
+			name:          "all CVEs",
+			fieldSelector: fields.Everything(),
+			expectedCVEs: map[string][]v1alpha1.AffectedImage{
+				testVulnerability1.CVE: {
+					newTestAffectedImage(report1, testVulnerability1),
+					newTestAffectedImage(report2, testVulnerability1),
+				},
+				testVulnerability2.CVE: {
+					newTestAffectedImage(report1, testVulnerability2),
+				},
+			},
+		},
+		{
+			name:          "all CVEs of the authorized namespaces",
+			user:          "reader",
+			fieldSelector: fields.Everything(),
+			expectedCVEs: map[string][]v1alpha1.AffectedImage{
+				testVulnerability1.CVE: {
+					newTestAffectedImage(report1, testVulnerability1),
+				},
+				testVulnerability2.CVE: {
+					newTestAffectedImage(report1, testVulnerability2),
+				},
+			},
+		},
+		{
+			name:          "by unauthorized namespace",
+			user:          "reader",
+			fieldSelector: fields.OneTermEqualSelector("affectedImages.namespace", "other"),
+			expectedCVEs:  map[string][]v1alpha1.AffectedImage{},
+		},
+		{
+			name:          "by namespace",
+			fieldSelector: fields.OneTermEqualSelector("affectedImages.namespace", "other"),
+			expectedCVEs: map[string][]v1alpha1.AffectedImage{
+				testVulnerability1.CVE: {
+					newTestAffectedImage(report2, testVulnerability1),
+				},
+			},
+		},
+		{
+			name:          "by severity",
+			fieldSelector: fields.OneTermEqualSelector("affectedImages.severity", "HIGH"),
+			expectedCVEs: map[string][]v1alpha1.AffectedImage{
+				testVulnerability2.CVE: {
+					newTestAffectedImage(report1, testVulnerability2),
+				},
+			},
+		},
+		{
+			name:          "by name",
+			fieldSelector: fields.OneTermEqualSelector("metadata.name", testVulnerability2.CVE),
+			expectedCVEs: map[string][]v1alpha1.AffectedImage{
+				testVulnerability2.CVE: {
+					newTestAffectedImage(report1, testVulnerability2),
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			userName := test.user
+			if userName == "" {
+				userName = "admin"
+			}
+			obj, err := cveStore.List(contextAs(userName), &metainternalversion.ListOptions{
+				FieldSelector: test.fieldSelector,
+			})
+			suite.Require().NoError(err)
+
+			list, ok := obj.(*v1alpha1.CVEList)
+			suite.Require().True(ok)
+			suite.Equal(strconv.FormatUint(resourceVersion, 10), list.ResourceVersion)
+
+			cves := map[string][]v1alpha1.AffectedImage{}
+			for _, cve := range list.Items {
+				cves[cve.Name] = cve.AffectedImages
+			}
+			suite.Equal(test.expectedCVEs, cves)
+		})
+	}
+}
+
+func (suite *storeTestSuite) TestCVEStoreListPagination() {
+	suite.requirePostgres()
+
+	suite.createTestVulnerabilityReports()
+	cveStore := suite.newCVEStore()
+
+	obj, err := cveStore.List(contextAs("admin"), &metainternalversion.ListOptions{Limit: 1})
+	suite.Require().NoError(err)
+	list, ok := obj.(*v1alpha1.CVEList)
+	suite.Require().True(ok)
+	suite.Require().Len(list.Items, 1)
+	suite.Equal(testVulnerability2.CVE, list.Items[0].Name)
+	suite.NotEmpty(list.Continue)
+
+	obj, err = cveStore.List(contextAs("admin"), &metainternalversion.ListOptions{Limit: 1, Continue: list.Continue})
+	suite.Require().NoError(err)
+	list, ok = obj.(*v1alpha1.CVEList)
+	suite.Require().True(ok)
+	suite.Require().Len(list.Items, 1)
+	suite.Equal(testVulnerability1.CVE, list.Items[0].Name)
+	suite.Empty(list.Continue)
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/stephenafamo/bob/dialect/psql"
+)
+
+// Backend is the database the objects are stored in.
+type Backend string
+
+const (
+	// PostgresBackend stores the objects in PostgreSQL.
+	// It is the backend of the production deployments, supporting multiple storage replicas.
+	PostgresBackend Backend = "postgres"
+	// SQLiteBackend stores the objects in a SQLite file.
+	// It is meant for single-node, edge and development deployments running a single storage replica.
+	SQLiteBackend Backend = "sqlite"
+)
+
+// Database is the SQL database backing the stores.
+type Database interface {
+	querier
+	// Backend returns the backend of the database.
+	Backend() Backend
+	// Close closes the connections to the database.
+	Close() error
+	// Ping checks that the database is reachable.
+	Ping(ctx context.Context) error
+
+	// begin starts a read-write transaction.
+	begin(ctx context.Context) (transaction, error)
+	// beginSnapshot starts a read-only transaction reading a consistent snapshot of the database.
+	beginSnapshot(ctx context.Context) (transaction, error)
+	// listen calls notify with the resource of every event committed, until the context is canceled
+	// or the connection to the database fails.
+	// listening is called once the notifications are delivered, as the events committed before might be missed.
+	listen(ctx context.Context, listening func(), notify func(resource string)) error
+	// withMigrationsLock runs fn while holding the lock serializing the migrations of the storage replicas.
+	withMigrationsLock(ctx context.Context, fn func() error) error
+	// dialect returns the SQL dialect of the database.
+	dialect() sqlDialect
+}
+
+// querier is implemented by both the databases and their transactions.
+type querier interface {
+	// Exec executes the statement and returns the number of affected rows.
+	Exec(ctx context.Context, sql string, args ...any) (int64, error)
+	Query(ctx context.Context, sql string, args ...any) (rows, error)
+	// QueryRow executes the query returning at most one row.
+	// Scanning the row returns an error matching sql.ErrNoRows if the query selected no rows.
+	QueryRow(ctx context.Context, sql string, args ...any) row
+}
+
+// rows is the result of a query.
+type rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Close()
+	Err() error
+}
+
+// row is the result of a query returning at most one row.
+type row interface {
+	Scan(dest ...any) error
+}
+
+// transaction is a database transaction.
+type transaction interface {
+	querier
+	Commit(ctx context.Context) error
+	// Rollback rolls back the transaction.
+	// It is a no-op if the transaction has already been committed, so that it can always be deferred.
+	Rollback(ctx context.Context) error
+
+	// notify notifies the listeners of the database that an event of the resource was recorded.
+	// The notification is delivered on commit.
+	notify(ctx context.Context, resource string) error
+	// copyFrom bulk inserts the rows in the given columns of the table.
+	copyFrom(ctx context.Context, table string, columns []string, rows [][]any) error
+}
+
+// sqlDialect builds the SQL that differs between the backends.
+type sqlDialect interface {
+	// lockWrites serializes the write transactions, across the replicas of the storage.
+	// The writers take it before locking any row, so that they all lock in the same order and cannot deadlock.
+	// It is held until the end of the transaction, and can be taken again by the transaction holding it.
+	// A single write transaction runs at a time, including the writes of the hooks such as the findings of the reports,
+	// so the write throughput of the storage is bounded by the duration of the write transactions and does not grow with the replicas.
+	// BenchmarkCreateVulnerabilityReports measures it.
+	lockWrites(ctx context.Context, tx transaction) error
+	// nextResourceVersions serializes the writers and reserves count consecutive resourceVersions,
+	// returning the first one.
+	// It must be called inside the transaction that records the events.
+	nextResourceVersions(ctx context.Context, tx transaction, count int) (uint64, error)
+	// jsonText returns an expression extracting the value at the given path of the object column as text.
+	jsonText(path ...string) psql.Expression
+	// labelEquals returns an expression checking whether the object has the label with the given value.
+	labelEquals(key, value string) psql.Expression
+	// labelExists returns an expression checking whether the object has the label.
+	labelExists(key string) psql.Expression
+	// in returns an expression checking whether the expression is one of the values.
+	in(expression psql.Expression, values []string) psql.Expression
+	// notIn returns an expression checking whether the expression is none of the values.
+	notIn(expression psql.Expression, values []string) psql.Expression
+	// integer returns an expression converting the text expression to an integer,
+	// or NULL if it is not a base 10 integer.
+	integer(expression psql.Expression) psql.Expression
+	// rowLocks reports whether selected rows can be locked until the end of the transaction.
+	// Without row locks, the write transactions must be serialized by the database.
+	rowLocks() bool
+	// ago returns an expression computing the timestamp the given duration before now.
+	ago(duration time.Duration) psql.Expression
+	// objectSize returns an expression computing the size in bytes of the stored JSON expression.
+	objectSize(expression psql.Expression) psql.Expression
+
+	// migrationsDir returns the directory of the embedded migrations of the backend.
+	migrationsDir() string
+	// createSchemaMigrationsTableSQL returns the statement creating the schema_migrations table.
+	createSchemaMigrationsTableSQL() string
+	// tableExists reports whether the table exists.
+	tableExists(ctx context.Context, q querier, table string) (bool, error)
+}
+
+// collectRows scans every row with fn, then closes the rows.
+func collectRows[T any](r rows, fn func(row row) (T, error)) ([]T, error) {
+	defer r.Close()
+
+	values := []T{}
+	for r.Next() {
+		value, err := fn(r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/healthz"
+)
+
+// databaseCheckTimeout bounds the time the health and readiness checks wait for the database.
+const databaseCheckTimeout = 5 * time.Second
+
+// NewDatabaseHealthCheck returns the health check named "database",
+// failing if the database is unreachable or its schema is not migrated to the version of the storage.
+func NewDatabaseHealthCheck(db Database) healthz.HealthChecker {
+	return healthz.NamedCheck("database", func(r *http.Request) error {
+		ctx, cancel := context.WithTimeout(r.Context(), databaseCheckTimeout)
+		defer cancel()
+
+		if err := db.Ping(ctx); err != nil {
+			return fmt.Errorf("database is unreachable: %w", err)
+		}
+
+		if err := checkSchema(ctx, db); err != nil {
+			return fmt.Errorf("database schema is not current: %w", err)
+		}
+
+		return nil
+	})
+}
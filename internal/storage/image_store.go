@@ -5,36 +5,28 @@ import (
 	"fmt"
 	"log/slog"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/generic/registry"
 )
 
-const CreateImageTableSQL = `
-CREATE TABLE IF NOT EXISTS images (
-    name VARCHAR(253) NOT NULL,
-    namespace VARCHAR(253) NOT NULL,
-    object JSONB NOT NULL,
-    PRIMARY KEY (name, namespace)
-);
-`
-
 // NewImageStore returns a store registry that will work against API services.
 func NewImageStore(
 	scheme *runtime.Scheme,
 	optsGetter generic.RESTOptionsGetter,
-	db *pgxpool.Pool,
+	db Database,
+	watchCache *WatchCacheConfig,
 	logger *slog.Logger,
-) (*registry.Store, error) {
+) (*RegistryStore, error) {
 	strategy := newImageStrategy(scheme)
 
 	newFunc := func() runtime.Object { return &v1alpha1.Image{} }
 	newListFunc := func() runtime.Object { return &v1alpha1.ImageList{} }
 
+	objectStore := newStore(db, "images", newFunc, newListFunc, nil, logger.With("store", "image"))
+
 	store := &registry.Store{
 		NewFunc:                   newFunc,
 		NewListFunc:               newListFunc,
@@ -42,27 +34,20 @@ func NewImageStore(
 		DefaultQualifiedResource:  v1alpha1.Resource("images"),
 		SingularQualifiedResource: v1alpha1.Resource("image"),
 		Storage: registry.DryRunnableStorage{
-			Storage: &store{
-				db:          db,
-				broadcaster: watch.NewBroadcaster(1000, watch.WaitIfChannelFull),
-				table:       "images",
-				newFunc:     newFunc,
-				newListFunc: newListFunc,
-				logger:      logger.With("store", "image"),
-			},
+			Storage: objectStore,
 		},
+		DestroyFunc:    objectStore.destroy,
 		CreateStrategy: strategy,
 		UpdateStrategy: strategy,
 		DeleteStrategy: strategy,
 		TableConvertor: &imageTableConvertor{},
 	}
 
-	options := &generic.StoreOptions{RESTOptions: optsGetter, AttrFunc: getAttrs}
-	if err := store.CompleteWithOptions(options); err != nil {
-		return nil, fmt.Errorf("unable to complete store with options: %w", err)
+	if err := completeStore(store, optsGetter, watchCache); err != nil {
+		return nil, err
 	}
 
-	return store, nil
+	return &RegistryStore{Store: store, objects: objectStore}, nil
 }
 
 type imageTableConvertor struct{}
@@ -6,6 +6,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/storage/names"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 )
 
 func newImageStrategy(typer runtime.ObjectTyper) imageStrategy {
@@ -27,12 +29,26 @@ func (imageStrategy) PrepareForCreate(_ context.Context, _ runtime.Object) {
 func (imageStrategy) PrepareForUpdate(_ context.Context, _, _ runtime.Object) {
 }
 
-func (imageStrategy) Validate(_ context.Context, _ runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+func (imageStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	image, ok := obj.(*v1alpha1.Image)
+	if !ok {
+		return unexpectedTypeError(obj)
+	}
+
+	return validateImage(image)
 }
 
 // WarningsOnCreate returns warnings for the creation of the given object.
-func (imageStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
+func (imageStrategy) WarningsOnCreate(_ context.Context, obj runtime.Object) []string {
+	image, ok := obj.(*v1alpha1.Image)
+	if !ok {
+		return nil
+	}
+
+	if len(image.Layers) == 0 {
+		return []string{"layers: the image has no layers"}
+	}
+
 	return nil
 }
 
@@ -47,11 +63,38 @@ func (imageStrategy) AllowUnconditionalUpdate() bool {
 func (imageStrategy) Canonicalize(_ runtime.Object) {
 }
 
-func (imageStrategy) ValidateUpdate(_ context.Context, _, _ runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+func (imageStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newImage, ok := obj.(*v1alpha1.Image)
+	if !ok {
+		return unexpectedTypeError(obj)
+	}
+	oldImage, ok := old.(*v1alpha1.Image)
+	if !ok {
+		return unexpectedTypeError(old)
+	}
+
+	allErrs := validateImage(newImage)
+	allErrs = append(allErrs, validateImageMetadataUpdate(
+		newImage.ImageMetadata, oldImage.ImageMetadata, field.NewPath("imageMetadata"),
+	)...)
+
+	return allErrs
 }
 
 // WarningsOnUpdate returns warnings for the given update.
 func (imageStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []string {
 	return nil
 }
+
+// validateImage validates the image metadata and the digests of the layers.
+func validateImage(image *v1alpha1.Image) field.ErrorList {
+	allErrs := validateImageMetadata(image.ImageMetadata, field.NewPath("imageMetadata"))
+
+	layersPath := field.NewPath("layers")
+	for i, layer := range image.Layers {
+		allErrs = append(allErrs, validateDigest(layer.Digest, layersPath.Index(i).Child("digest"))...)
+		allErrs = append(allErrs, validateDigest(layer.DiffID, layersPath.Index(i).Child("diffID"))...)
+	}
+
+	return allErrs
+}
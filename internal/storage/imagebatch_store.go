@@ -0,0 +1,270 @@
+//nolint:wrapcheck // We want to return the errors from k8s.io/apiserver as they are.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	storeerr "k8s.io/apiserver/pkg/storage/errors"
+	"k8s.io/apiserver/pkg/warning"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+var (
+	_ rest.Storage              = &imageBatchStore{}
+	_ rest.Scoper               = &imageBatchStore{}
+	_ rest.Creater              = &imageBatchStore{}
+	_ rest.SingularNameProvider = &imageBatchStore{}
+)
+
+// imageBatchStore serves the create-only ImageBatch resource.
+// The images of a batch are written to the Image store in a single transaction.
+type imageBatchStore struct {
+	images     *RegistryStore
+	authorizer authorizer.Authorizer
+	logger     *slog.Logger
+}
+
+// NewImageBatchStore returns a create-only store writing batches of images to the Image store.
+// The images are written to the store directly, so the authorizer checks that the user creating
+// the batch is allowed to create and update the images of its namespace.
+// The images are validated by the strategies of the Image store, like the images written one at a time,
+// but only the ImageBatch goes through the admission chain: the admission policies and webhooks
+// matching the images do not apply to the images written by a batch.
+func NewImageBatchStore(images *RegistryStore, authz authorizer.Authorizer, logger *slog.Logger) rest.Storage {
+	return &imageBatchStore{
+		images:     images,
+		authorizer: authz,
+		logger:     logger.With("store", "imagebatch"),
+	}
+}
+
+// New returns an empty ImageBatch.
+func (s *imageBatchStore) New() runtime.Object {
+	return &v1alpha1.ImageBatch{}
+}
+
+// Destroy cleans up the resources of the store.
+func (s *imageBatchStore) Destroy() {
+	// Nothing to clean up, the images are written by the Image store.
+}
+
+// NamespaceScoped returns true, as the images of a batch are in its namespace.
+func (s *imageBatchStore) NamespaceScoped() bool {
+	return true
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *imageBatchStore) GetSingularName() string {
+	return "imagebatch"
+}
+
+// Create creates the images of the batch that do not exist and updates the others,
+// then returns the ImageBatch with the number of written images in its status.
+// The ImageBatch is not persisted, and its images are not returned so that the response stays small.
+func (s *imageBatchStore) Create(
+	ctx context.Context,
+	obj runtime.Object,
+	createValidation rest.ValidateObjectFunc,
+	options *metav1.CreateOptions,
+) (runtime.Object, error) {
+	batch, ok := obj.(*v1alpha1.ImageBatch)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unexpected object type: %T", obj))
+	}
+
+	namespace, ok := genericapirequest.NamespaceFrom(ctx)
+	if !ok || namespace == "" {
+		return nil, apierrors.NewBadRequest("the namespace of the ImageBatch is required")
+	}
+
+	s.logger.DebugContext(ctx, "Writing image batch", "namespace", namespace, "images", len(batch.Spec.Images))
+
+	if errs := validateImageBatch(batch, namespace); len(errs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.Kind("ImageBatch"), batch.Name, errs)
+	}
+
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	// The batch creates the images that do not exist and updates the others, the user must be allowed to do both.
+	for _, verb := range []string{"create", "update"} {
+		if err := s.authorizeImages(ctx, namespace, verb); err != nil {
+			return nil, err
+		}
+	}
+
+	imagesPath := field.NewPath("spec", "images")
+	indexes := make(map[runtime.Object]int, len(batch.Spec.Images))
+	images := make([]runtime.Object, len(batch.Spec.Images))
+	for i := range batch.Spec.Images {
+		image := batch.Spec.Images[i].DeepCopy()
+		image.Namespace = namespace
+		images[i] = image
+		indexes[image] = i
+	}
+
+	prepare := func(obj, current runtime.Object) error {
+		imagePath := imagesPath.Index(indexes[obj])
+
+		var warnings []string
+		if current == nil {
+			objectMeta, err := meta.Accessor(obj)
+			if err != nil {
+				return apierrors.NewInternalError(err)
+			}
+			rest.FillObjectMetaSystemFields(objectMeta)
+			if err := rest.BeforeCreate(s.images.CreateStrategy, ctx, obj); err != nil {
+				return newImageBatchError(batch.Name, imagePath, err)
+			}
+			warnings = s.images.CreateStrategy.WarningsOnCreate(ctx, obj)
+		} else {
+			if err := keepCurrentMetadata(obj, current); err != nil {
+				return apierrors.NewInternalError(err)
+			}
+			if err := rest.BeforeUpdate(s.images.UpdateStrategy, ctx, obj, current); err != nil {
+				return newImageBatchError(batch.Name, imagePath, err)
+			}
+			warnings = s.images.UpdateStrategy.WarningsOnUpdate(ctx, obj, current)
+		}
+
+		for _, w := range warnings {
+			warning.AddWarning(ctx, "", fmt.Sprintf("%s: %s", imagePath, w))
+		}
+
+		return nil
+	}
+
+	created, updated, err := s.images.objects.upsert(
+		ctx,
+		s.images.KeyRootFunc(ctx),
+		images,
+		prepare,
+		len(options.DryRun) > 0,
+	)
+	if err != nil {
+		return nil, storeerr.InterpretUpdateError(err, s.images.DefaultQualifiedResource, "")
+	}
+
+	result := batch.DeepCopy()
+	result.Namespace = namespace
+	result.Spec.Images = nil
+	result.Status = v1alpha1.ImageBatchStatus{
+		Created:   created,
+		Updated:   updated,
+		Unchanged: len(images) - created - updated,
+	}
+
+	return result, nil
+}
+
+// authorizeImages checks that the user of the request is allowed to perform the verb on the images of the namespace.
+func (s *imageBatchStore) authorizeImages(ctx context.Context, namespace, verb string) error {
+	requestUser, ok := genericapirequest.UserFrom(ctx)
+	if !ok {
+		return apierrors.NewForbidden(v1alpha1.Resource("images"), "", errors.New("the user of the request is unknown"))
+	}
+
+	decision, reason, err := s.authorizer.Authorize(ctx, authorizer.AttributesRecord{
+		User:            requestUser,
+		Verb:            verb,
+		Namespace:       namespace,
+		APIGroup:        v1alpha1.GroupName,
+		APIVersion:      v1alpha1.SchemeGroupVersion.Version,
+		Resource:        "images",
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to authorize the images of the batch: %w", err))
+	}
+	if decision != authorizer.DecisionAllow {
+		message := fmt.Sprintf("user %q cannot %s the images of the batch", requestUser.GetName(), verb)
+		if reason != "" {
+			message += ": " + reason
+		}
+		return apierrors.NewForbidden(v1alpha1.Resource("images"), "", errors.New(message))
+	}
+
+	return nil
+}
+
+// validateImageBatch validates the size of the batch and the names and namespaces of its images.
+// The images themselves are validated by the strategies of the Image store.
+func validateImageBatch(batch *v1alpha1.ImageBatch, namespace string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	imagesPath := field.NewPath("spec", "images")
+
+	if len(batch.Spec.Images) == 0 {
+		allErrs = append(allErrs, field.Required(imagesPath, ""))
+	}
+	if len(batch.Spec.Images) > v1alpha1.MaxImageBatchSize {
+		allErrs = append(allErrs, field.TooMany(imagesPath, len(batch.Spec.Images), v1alpha1.MaxImageBatchSize))
+	}
+
+	names := sets.New[string]()
+	for i, image := range batch.Spec.Images {
+		metadataPath := imagesPath.Index(i).Child("metadata")
+		switch {
+		case image.Name == "":
+			allErrs = append(allErrs, field.Required(metadataPath.Child("name"), "the images are written by name"))
+		case names.Has(image.Name):
+			allErrs = append(allErrs, field.Duplicate(metadataPath.Child("name"), image.Name))
+		default:
+			names.Insert(image.Name)
+		}
+		if image.Namespace != "" && image.Namespace != namespace {
+			allErrs = append(allErrs, field.Invalid(
+				metadataPath.Child("namespace"), image.Namespace, "must be the namespace of the ImageBatch",
+			))
+		}
+		if image.ResourceVersion != "" {
+			allErrs = append(allErrs, field.Forbidden(
+				metadataPath.Child("resourceVersion"), "the images are written unconditionally",
+			))
+		}
+	}
+
+	return allErrs
+}
+
+// keepCurrentMetadata writes the image unconditionally over the current one,
+// keeping its managed fields if the new image has none, as the batches are not tracked by the field manager.
+func keepCurrentMetadata(obj, current runtime.Object) error {
+	image, ok := obj.(*v1alpha1.Image)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", obj)
+	}
+	currentImage, ok := current.(*v1alpha1.Image)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", current)
+	}
+
+	image.ResourceVersion = currentImage.ResourceVersion
+	if image.ManagedFields == nil {
+		image.ManagedFields = currentImage.ManagedFields
+	}
+
+	return nil
+}
+
+// newImageBatchError returns the error of a batch whose image at the given path cannot be written.
+func newImageBatchError(name string, imagePath *field.Path, err error) error {
+	return apierrors.NewInvalid(v1alpha1.Kind("ImageBatch"), name, field.ErrorList{
+		field.Invalid(imagePath, field.OmitValueType{}, err.Error()),
+	})
+}
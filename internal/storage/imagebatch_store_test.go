@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// newImageStore returns an Image registry store, not served from the watch cache.
+func (suite *storeTestSuite) newImageStore() *RegistryStore {
+	scheme := runtime.NewScheme()
+	suite.Require().NoError(v1alpha1.AddToScheme(scheme))
+
+	imageStore, err := NewImageStore(
+		scheme,
+		generic.RESTOptions{ResourcePrefix: imageKeyPrefix},
+		suite.db,
+		nil,
+		slog.Default(),
+	)
+	suite.Require().NoError(err)
+
+	return imageStore
+}
+
+// newValidImage returns a valid Image of the default namespace in the given repository.
+func newValidImage(name, repository string) v1alpha1.Image {
+	image := v1alpha1.Image{
+		ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: "default"},
+		ImageMetadata: newValidImageMetadata(),
+		Layers:        []v1alpha1.ImageLayer{{Digest: testDigest, DiffID: testDigest}},
+	}
+	image.Repository = repository
+
+	return image
+}
+
+func (suite *storeTestSuite) TestImageBatchStore() {
+	imageStore := suite.newImageStore()
+	defer imageStore.DestroyFunc()
+
+	// The writer user can create and update the images, the creator user can only create them.
+	authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if a.GetResource() != "images" || a.GetAPIGroup() != v1alpha1.GroupName || a.GetNamespace() != "default" {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+		if a.GetUser().GetName() == "writer" || (a.GetUser().GetName() == "creator" && a.GetVerb() == "create") {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "not allowed", nil
+	})
+	batchStore, ok := NewImageBatchStore(imageStore, authz, slog.Default()).(*imageBatchStore)
+	suite.Require().True(ok)
+
+	ctx := genericapirequest.WithNamespace(genericapirequest.NewContext(), "default")
+	createBatchAs := func(userName string, images ...v1alpha1.Image) (*v1alpha1.ImageBatch, error) {
+		ctx := ctx
+		if userName != "" {
+			ctx = genericapirequest.WithUser(ctx, &user.DefaultInfo{Name: userName})
+		}
+		result, err := batchStore.Create(
+			ctx,
+			&v1alpha1.ImageBatch{Spec: v1alpha1.ImageBatchSpec{Images: images}},
+			rest.ValidateAllObjectFunc,
+			&metav1.CreateOptions{},
+		)
+		if err != nil {
+			return nil, err
+		}
+		batch, ok := result.(*v1alpha1.ImageBatch)
+		suite.Require().True(ok)
+
+		return batch, nil
+	}
+	createBatch := func(images ...v1alpha1.Image) (*v1alpha1.ImageBatch, error) {
+		return createBatchAs("writer", images...)
+	}
+	getImage := func(name string) *v1alpha1.Image {
+		obj, err := imageStore.Get(ctx, name, &metav1.GetOptions{})
+		suite.Require().NoError(err)
+		image, ok := obj.(*v1alpha1.Image)
+		suite.Require().True(ok)
+
+		return image
+	}
+
+	batch, err := createBatch(newValidImage("test1", "repository"), newValidImage("test2", "repository"))
+	suite.Require().NoError(err)
+	suite.Equal(v1alpha1.ImageBatchStatus{Created: 2}, batch.Status)
+	suite.Nil(batch.Spec.Images)
+
+	image := getImage("test1")
+	suite.NotEmpty(image.UID)
+	suite.False(image.CreationTimestamp.IsZero())
+
+	// The existing images are updated, keeping their system fields, or left unchanged.
+	updatedImage := newValidImage("test1", "repository")
+	updatedImage.Labels = map[string]string{"updated": "true"}
+	batch, err = createBatch(updatedImage, newValidImage("test2", "repository"), newValidImage("test3", "repository"))
+	suite.Require().NoError(err)
+	suite.Equal(v1alpha1.ImageBatchStatus{Created: 1, Updated: 1, Unchanged: 1}, batch.Status)
+
+	updated := getImage("test1")
+	suite.Equal(image.UID, updated.UID)
+	suite.Equal(image.CreationTimestamp, updated.CreationTimestamp)
+	suite.Equal("true", updated.Labels["updated"])
+
+	// The users who cannot both create and update the images cannot write a batch, even of new images only.
+	for _, userName := range []string{"creator", "other", ""} {
+		_, err = createBatchAs(userName, newValidImage("test4", "repository"))
+		suite.True(apierrors.IsForbidden(err), "unexpected error for user %q: %v", userName, err)
+	}
+	_, err = imageStore.Get(ctx, "test4", &metav1.GetOptions{})
+	suite.True(apierrors.IsNotFound(err))
+
+	tests := []struct {
+		name           string
+		images         []v1alpha1.Image
+		expectedFields []string
+	}{
+		{
+			name:           "no images",
+			images:         nil,
+			expectedFields: []string{"spec.images"},
+		},
+		{
+			name: "duplicate and missing names",
+			images: []v1alpha1.Image{
+				newValidImage("test4", "repository"),
+				newValidImage("test4", "repository"),
+				newValidImage("", "repository"),
+			},
+			expectedFields: []string{"spec.images[1].metadata.name", "spec.images[2].metadata.name"},
+		},
+		{
+			name: "image of another namespace",
+			images: []v1alpha1.Image{
+				func() v1alpha1.Image {
+					image := newValidImage("test4", "repository")
+					image.Namespace = "other"
+					return image
+				}(),
+			},
+			expectedFields: []string{"spec.images[0].metadata.namespace"},
+		},
+		{
+			name: "invalid new image",
+			images: []v1alpha1.Image{
+				newValidImage("test4", "repository"),
+				func() v1alpha1.Image {
+					image := newValidImage("test5", "repository")
+					image.Digest = "latest"
+					return image
+				}(),
+			},
+			expectedFields: []string{"spec.images[1]"},
+		},
+		{
+			name: "image metadata changed",
+			images: []v1alpha1.Image{
+				newValidImage("test4", "repository"),
+				newValidImage("test1", "other"),
+			},
+			expectedFields: []string{"spec.images[1]"},
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			_, err := createBatch(test.images...)
+			suite.Require().True(apierrors.IsInvalid(err), "unexpected error: %v", err)
+
+			var fields []string
+			statusErr := &apierrors.StatusError{}
+			suite.Require().ErrorAs(err, &statusErr)
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			suite.Equal(test.expectedFields, fields)
+
+			// Nothing is written when an image is rejected.
+			_, err = imageStore.Get(ctx, "test4", &metav1.GetOptions{})
+			suite.True(apierrors.IsNotFound(err))
+		})
+	}
+}
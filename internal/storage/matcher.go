@@ -3,6 +3,7 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -48,5 +49,15 @@ func getAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
 		"imageMetadata.digest":      imageMetadataAccessor.GetImageMetadata().Digest,
 	}
 
+	if vulnerabilityReport, ok := obj.(*v1alpha1.VulnerabilityReport); ok {
+		summary := vulnerabilityReport.Report.Summary
+		selectableFields["report.summary.critical"] = strconv.Itoa(summary.Critical)
+		selectableFields["report.summary.high"] = strconv.Itoa(summary.High)
+		selectableFields["report.summary.medium"] = strconv.Itoa(summary.Medium)
+		selectableFields["report.summary.low"] = strconv.Itoa(summary.Low)
+		selectableFields["report.summary.unknown"] = strconv.Itoa(summary.Unknown)
+		selectableFields["report.summary.suppressed"] = strconv.Itoa(summary.Suppressed)
+	}
+
 	return labels.Set(objMeta.GetLabels()), generic.MergeFieldsSets(selectableMetadata, selectableFields), nil
 }
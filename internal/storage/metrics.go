@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	metricsNamespace = "sbomscanner"
+	metricsSubsystem = "storage"
+)
+
+var (
+	watchersGauge = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "watchers",
+			Help:           "Number of active watchers per resource.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+
+	droppedWatchersCounter = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "dropped_watchers_total",
+			Help:           "Number of watchers terminated because they were too slow to keep up with the events.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+)
+
+var registerMetricsOnce sync.Once
+
+// registerMetrics registers the storage metrics in the legacy registry served by the API server.
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(watchersGauge)
+		legacyregistry.MustRegister(droppedWatchersCounter)
+	})
+}
+
+var registerDatabaseMetricsOnce sync.Once
+
+// RegisterDatabaseMetrics registers the metrics of the connection pool of the database
+// in the legacy registry served by the API server.
+// Only the PostgreSQL connection pool is observed, and only the first database registered is.
+func RegisterDatabaseMetrics(db Database) {
+	postgresDB, ok := db.(*postgresDatabase)
+	if !ok {
+		return
+	}
+
+	registerDatabaseMetricsOnce.Do(func() {
+		legacyregistry.CustomMustRegister(newPoolCollector(postgresDB.pool))
+	})
+}
+
+var (
+	poolConnectionsDesc = metrics.NewDesc(
+		metrics.BuildFQName(metricsNamespace, metricsSubsystem, "database_connections"),
+		"Number of connections of the database pool per state.",
+		[]string{"state"}, nil, metrics.ALPHA, "",
+	)
+	poolMaxConnectionsDesc = metrics.NewDesc(
+		metrics.BuildFQName(metricsNamespace, metricsSubsystem, "database_max_connections"),
+		"Maximum number of connections of the database pool.",
+		nil, nil, metrics.ALPHA, "",
+	)
+	poolAcquiresDesc = metrics.NewDesc(
+		metrics.BuildFQName(metricsNamespace, metricsSubsystem, "database_acquires_total"),
+		"Number of connections acquired from the database pool.",
+		nil, nil, metrics.ALPHA, "",
+	)
+	poolEmptyAcquiresDesc = metrics.NewDesc(
+		metrics.BuildFQName(metricsNamespace, metricsSubsystem, "database_empty_acquires_total"),
+		"Number of connection acquisitions that waited for a connection, as none was idle in the database pool.",
+		nil, nil, metrics.ALPHA, "",
+	)
+	poolCanceledAcquiresDesc = metrics.NewDesc(
+		metrics.BuildFQName(metricsNamespace, metricsSubsystem, "database_canceled_acquires_total"),
+		"Number of connection acquisitions from the database pool canceled by their context.",
+		nil, nil, metrics.ALPHA, "",
+	)
+	poolAcquireWaitDesc = metrics.NewDesc(
+		metrics.BuildFQName(metricsNamespace, metricsSubsystem, "database_acquire_wait_seconds_total"),
+		"Total time spent waiting for a connection, as none was idle in the database pool.",
+		nil, nil, metrics.ALPHA, "",
+	)
+)
+
+// poolCollector collects the statistics of a PostgreSQL connection pool.
+type poolCollector struct {
+	metrics.BaseStableCollector
+
+	pool *pgxpool.Pool
+}
+
+func newPoolCollector(pool *pgxpool.Pool) metrics.StableCollector {
+	return &poolCollector{pool: pool}
+}
+
+func (c *poolCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- poolConnectionsDesc
+	ch <- poolMaxConnectionsDesc
+	ch <- poolAcquiresDesc
+	ch <- poolEmptyAcquiresDesc
+	ch <- poolCanceledAcquiresDesc
+	ch <- poolAcquireWaitDesc
+}
+
+func (c *poolCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- metrics.NewLazyConstMetric(poolConnectionsDesc, metrics.GaugeValue, float64(stat.AcquiredConns()), "acquired")
+	ch <- metrics.NewLazyConstMetric(poolConnectionsDesc, metrics.GaugeValue, float64(stat.IdleConns()), "idle")
+	ch <- metrics.NewLazyConstMetric(poolConnectionsDesc, metrics.GaugeValue, float64(stat.ConstructingConns()), "constructing")
+	ch <- metrics.NewLazyConstMetric(poolMaxConnectionsDesc, metrics.GaugeValue, float64(stat.MaxConns()))
+	ch <- metrics.NewLazyConstMetric(poolAcquiresDesc, metrics.CounterValue, float64(stat.AcquireCount()))
+	ch <- metrics.NewLazyConstMetric(poolEmptyAcquiresDesc, metrics.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- metrics.NewLazyConstMetric(poolCanceledAcquiresDesc, metrics.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- metrics.NewLazyConstMetric(poolAcquireWaitDesc, metrics.CounterValue, stat.EmptyAcquireWaitTime().Seconds())
+}
@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationsFS contains the schema migrations of each backend, in a directory named after the backend.
+// Migrations are named <version>_<name>.sql and applied in version order, and the versions of each backend are independent.
+// The SQLite backend only has the object tables, the watch events and the selector columns:
+// the findings, packages, SPDX blobs, blob uploads and vulnerability timeline tables only exist on PostgreSQL.
+// Once released, a migration must never be changed: schema changes go in a new migration of every backend having the table.
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationsFS embed.FS
+
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+// migration is a schema migration embedded in the binary.
+type migration struct {
+	version  int64
+	name     string
+	checksum string
+	sql      string
+}
+
+// MigrationStatus is the status of a schema migration.
+type MigrationStatus struct {
+	Version int64
+	Name    string
+	// AppliedAt is nil if the migration is pending.
+	AppliedAt *time.Time
+	// Unknown reports whether the migration was applied by a newer version of the storage.
+	Unknown bool
+}
+
+// loadMigrations returns the embedded migrations of the given directory sorted by version.
+func loadMigrations(dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	versions := map[int64]string{}
+	for _, entry := range entries {
+		matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", entry.Name(), err)
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, entry.Name())
+		}
+		versions[version] = entry.Name()
+
+		content, err := migrationsFS.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		checksum := sha256.Sum256(content)
+
+		migrations = append(migrations, migration{
+			version:  version,
+			name:     matches[2],
+			checksum: hex.EncodeToString(checksum[:]),
+			sql:      string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// appliedMigration is a row of the schema_migrations table.
+type appliedMigration struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrate applies the pending schema migrations.
+// Each migration is applied in its own transaction, while holding a lock
+// that serializes concurrent storage replicas.
+// It fails if an applied migration does not match the embedded one.
+func Migrate(ctx context.Context, db Database, logger *slog.Logger) error {
+	migrations, err := loadMigrations(db.dialect().migrationsDir())
+	if err != nil {
+		return err
+	}
+
+	return db.withMigrationsLock(ctx, func() error {
+		if _, err = db.Exec(ctx, db.dialect().createSchemaMigrationsTableSQL()); err != nil {
+			return fmt.Errorf("failed to create schema_migrations table: %w", err)
+		}
+
+		applied, err := getAppliedMigrations(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		if err = verifyMigrations(migrations, applied); err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if _, ok := applied[m.version]; ok {
+				continue
+			}
+
+			logger.InfoContext(ctx, "Applying migration", "version", m.version, "name", m.name)
+			if err = applyMigration(ctx, db, m, logger); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetMigrationStatus returns the status of the embedded migrations,
+// followed by the ones applied by a newer version of the storage.
+func GetMigrationStatus(ctx context.Context, db Database) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(db.dialect().migrationsDir())
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := db.dialect().tableExists(ctx, db, "schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations table: %w", err)
+	}
+
+	applied := map[int64]appliedMigration{}
+	if exists {
+		applied, err = getAppliedMigrations(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = verifyMigrations(migrations, applied); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{
+			Version: m.version,
+			Name:    m.name,
+		}
+		if a, ok := applied[m.version]; ok {
+			status.AppliedAt = &a.appliedAt
+			delete(applied, m.version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	unknown := make([]MigrationStatus, 0, len(applied))
+	for _, a := range applied {
+		unknown = append(unknown, MigrationStatus{
+			Version:   a.version,
+			Name:      a.name,
+			AppliedAt: &a.appliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(unknown, func(i, j int) bool {
+		return unknown[i].Version < unknown[j].Version
+	})
+
+	return append(statuses, unknown...), nil
+}
+
+// checkSchema checks that every embedded migration has been applied to the database.
+// The migrations applied by a newer version of the storage are expected to be compatible.
+func checkSchema(ctx context.Context, db Database) error {
+	statuses, err := GetMigrationStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, fmt.Sprintf("%d_%s", status.Version, status.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+func getAppliedMigrations(ctx context.Context, q querier) (map[int64]appliedMigration, error) {
+	rows, err := q.Query(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var a appliedMigration
+		if err = rows.Scan(&a.version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[a.version] = a
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// verifyMigrations checks that the applied migrations have not been changed.
+func verifyMigrations(migrations []migration, applied map[int64]appliedMigration) error {
+	var errs []error
+	for _, m := range migrations {
+		a, ok := applied[m.version]
+		if !ok {
+			continue
+		}
+		if a.checksum != m.checksum {
+			errs = append(errs, fmt.Errorf(
+				"checksum mismatch for migration %d_%s: applied %s, embedded %s",
+				m.version, m.name, a.checksum, m.checksum,
+			))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func applyMigration(ctx context.Context, db Database, m migration, logger *slog.Logger) error {
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, m.sql); err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", m.version, m.name, err)
+	}
+
+	if _, err = tx.Exec(
+		ctx,
+		"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+		m.version, m.name, m.checksum,
+	); err != nil {
+		return fmt.Errorf("failed to record migration %d_%s: %w", m.version, m.name, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration %d_%s: %w", m.version, m.name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS images (
+    name VARCHAR(253) NOT NULL,
+    namespace VARCHAR(253) NOT NULL,
+    object JSONB NOT NULL,
+    PRIMARY KEY (name, namespace)
+);
+
+CREATE TABLE IF NOT EXISTS sboms (
+    name VARCHAR(253) NOT NULL,
+    namespace VARCHAR(253) NOT NULL,
+    object JSONB NOT NULL,
+    PRIMARY KEY (name, namespace)
+);
+
+CREATE TABLE IF NOT EXISTS vulnerabilityreports (
+    name VARCHAR(253) NOT NULL,
+    namespace VARCHAR(253) NOT NULL,
+    object JSONB NOT NULL,
+    PRIMARY KEY (name, namespace)
+);
@@ -0,0 +1,17 @@
+-- The sequence generates the cluster-wide resourceVersion.
+CREATE SEQUENCE IF NOT EXISTS resource_version_seq;
+
+-- The log of the events produced by every write, shared by all the resources.
+-- It is used to replay the events to the watchers resuming from a given resourceVersion.
+CREATE TABLE IF NOT EXISTS watch_events (
+    resource_version BIGINT PRIMARY KEY,
+    resource VARCHAR(253) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    name VARCHAR(253) NOT NULL,
+    namespace VARCHAR(253) NOT NULL,
+    object JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS watch_events_resource_idx ON watch_events (resource, resource_version);
+CREATE INDEX IF NOT EXISTS watch_events_created_at_idx ON watch_events (created_at);
@@ -0,0 +1,102 @@
+-- The metadata of a CVE, shared by all the findings of the CVE across the vulnerability reports.
+CREATE TABLE IF NOT EXISTS cve_metadata (
+    id VARCHAR(253) PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    "references" JSONB,
+    cvss JSONB
+);
+
+-- A vulnerability found in a package of an image.
+-- The findings are stored outside of the vulnerabilityreports object column,
+-- which keeps the results without their vulnerabilities.
+-- result_index and vulnerability_index are the position of the vulnerability in the report.
+CREATE TABLE IF NOT EXISTS vulnerability_findings (
+    report_name VARCHAR(253) NOT NULL,
+    report_namespace VARCHAR(253) NOT NULL,
+    result_index INTEGER NOT NULL,
+    vulnerability_index INTEGER NOT NULL,
+    cve VARCHAR(253) NOT NULL REFERENCES cve_metadata (id),
+    package_name TEXT NOT NULL DEFAULT '',
+    package_path TEXT NOT NULL DEFAULT '',
+    purl TEXT NOT NULL DEFAULT '',
+    installed_version TEXT NOT NULL DEFAULT '',
+    fixed_versions TEXT[],
+    diff_id TEXT NOT NULL DEFAULT '',
+    severity VARCHAR(32) NOT NULL DEFAULT '',
+    suppressed BOOLEAN NOT NULL DEFAULT false,
+    vex_status JSONB,
+    PRIMARY KEY (report_namespace, report_name, result_index, vulnerability_index),
+    FOREIGN KEY (report_name, report_namespace) REFERENCES vulnerabilityreports (name, namespace) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS vulnerability_findings_cve_idx ON vulnerability_findings (cve);
+
+-- Move the vulnerabilities of the existing reports to the new tables.
+CREATE TEMPORARY TABLE existing_findings ON COMMIT DROP AS
+SELECT
+    r.name AS report_name,
+    r.namespace AS report_namespace,
+    (result.ordinality - 1)::INTEGER AS result_index,
+    (vulnerability.ordinality - 1)::INTEGER AS vulnerability_index,
+    vulnerability.value AS vulnerability
+FROM vulnerabilityreports r
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(r.object->'report'->'results') = 'array'
+        THEN r.object->'report'->'results' ELSE '[]'::JSONB END
+) WITH ORDINALITY AS result (value, ordinality)
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(result.value->'vulnerabilities') = 'array'
+        THEN result.value->'vulnerabilities' ELSE '[]'::JSONB END
+) WITH ORDINALITY AS vulnerability (value, ordinality);
+
+INSERT INTO cve_metadata (id, title, description, "references", cvss)
+SELECT DISTINCT ON (vulnerability->>'cve')
+    COALESCE(vulnerability->>'cve', ''),
+    COALESCE(vulnerability->>'title', ''),
+    COALESCE(vulnerability->>'description', ''),
+    NULLIF(vulnerability->'references', 'null'::JSONB),
+    NULLIF(vulnerability->'cvss', 'null'::JSONB)
+FROM existing_findings
+ORDER BY vulnerability->>'cve'
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO vulnerability_findings (
+    report_name, report_namespace, result_index, vulnerability_index, cve,
+    package_name, package_path, purl, installed_version, fixed_versions,
+    diff_id, severity, suppressed, vex_status
+)
+SELECT
+    report_name,
+    report_namespace,
+    result_index,
+    vulnerability_index,
+    COALESCE(vulnerability->>'cve', ''),
+    COALESCE(vulnerability->>'packageName', ''),
+    COALESCE(vulnerability->>'packagePath', ''),
+    COALESCE(vulnerability->>'purl', ''),
+    COALESCE(vulnerability->>'installedVersion', ''),
+    CASE WHEN jsonb_typeof(vulnerability->'fixedVersions') = 'array'
+        THEN ARRAY(SELECT jsonb_array_elements_text(vulnerability->'fixedVersions')) END,
+    COALESCE(vulnerability->>'diffID', ''),
+    COALESCE(vulnerability->>'severity', ''),
+    COALESCE((vulnerability->>'suppressed')::BOOLEAN, false),
+    NULLIF(vulnerability->'vexStatus', 'null'::JSONB)
+FROM existing_findings;
+
+UPDATE vulnerabilityreports r
+SET object = jsonb_set(
+    r.object,
+    '{report,results}',
+    (
+        SELECT jsonb_agg(
+            CASE WHEN jsonb_typeof(result.value->'vulnerabilities') = 'array'
+                THEN jsonb_set(result.value, '{vulnerabilities}', '[]'::JSONB)
+                ELSE result.value END
+            ORDER BY result.ordinality
+        )
+        FROM jsonb_array_elements(r.object->'report'->'results') WITH ORDINALITY AS result (value, ordinality)
+    )
+)
+WHERE jsonb_typeof(r.object->'report'->'results') = 'array'
+    AND jsonb_array_length(r.object->'report'->'results') > 0;
@@ -0,0 +1,38 @@
+-- The packages listed in the SPDX document of the SBOMs, indexed to search the images shipping a package.
+CREATE TABLE IF NOT EXISTS sbom_packages (
+    sbom_name VARCHAR(253) NOT NULL,
+    sbom_namespace VARCHAR(253) NOT NULL,
+    package_index INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    version TEXT NOT NULL DEFAULT '',
+    purl TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (sbom_namespace, sbom_name, package_index),
+    FOREIGN KEY (sbom_name, sbom_namespace) REFERENCES sboms (name, namespace) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS sbom_packages_name_idx ON sbom_packages (name);
+CREATE INDEX IF NOT EXISTS sbom_packages_purl_idx ON sbom_packages (purl text_pattern_ops);
+
+-- Index the packages of the existing SBOMs.
+INSERT INTO sbom_packages (sbom_name, sbom_namespace, package_index, name, version, purl)
+SELECT
+    s.name,
+    s.namespace,
+    (package.ordinality - 1)::INTEGER,
+    package.value->>'name',
+    COALESCE(package.value->>'versionInfo', ''),
+    COALESCE((
+        SELECT ref->>'referenceLocator'
+        FROM jsonb_array_elements(
+            CASE WHEN jsonb_typeof(package.value->'externalRefs') = 'array'
+                THEN package.value->'externalRefs' ELSE '[]'::JSONB END
+        ) AS ref
+        WHERE ref->>'referenceType' = 'purl'
+        LIMIT 1
+    ), '')
+FROM sboms s
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(s.object->'spdx'->'packages') = 'array'
+        THEN s.object->'spdx'->'packages' ELSE '[]'::JSONB END
+) WITH ORDINALITY AS package (value, ordinality)
+WHERE package.value->>'name' IS NOT NULL;
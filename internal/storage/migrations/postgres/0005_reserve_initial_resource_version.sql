@@ -0,0 +1,4 @@
+-- An empty store is at resourceVersion 1, as the apiserver rejects lists at resourceVersion 0,
+-- so the first write must be assigned resourceVersion 2.
+-- Sequences that have already been used are left untouched.
+SELECT setval('resource_version_seq', GREATEST(last_value, 1)) FROM resource_version_seq;
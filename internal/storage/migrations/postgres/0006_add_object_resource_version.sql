@@ -0,0 +1,9 @@
+-- The resourceVersion of the last write of each object.
+-- The updates and the deletions only apply to the row they have read, so that concurrent writes are detected.
+ALTER TABLE images ADD COLUMN IF NOT EXISTS resource_version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE sboms ADD COLUMN IF NOT EXISTS resource_version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE vulnerabilityreports ADD COLUMN IF NOT EXISTS resource_version BIGINT NOT NULL DEFAULT 0;
+
+UPDATE images SET resource_version = (object #>> '{metadata,resourceVersion}')::BIGINT;
+UPDATE sboms SET resource_version = (object #>> '{metadata,resourceVersion}')::BIGINT;
+UPDATE vulnerabilityreports SET resource_version = (object #>> '{metadata,resourceVersion}')::BIGINT;
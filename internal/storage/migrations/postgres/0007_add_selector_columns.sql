@@ -0,0 +1,56 @@
+-- The selectable fields and the scanjob-uid label of the objects, generated from the object column so that they can be indexed.
+-- The other label selectors are served by the GIN index on the labels.
+
+ALTER TABLE images
+    ADD COLUMN IF NOT EXISTS registry TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,registry}') STORED,
+    ADD COLUMN IF NOT EXISTS registry_uri TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,registryURI}') STORED,
+    ADD COLUMN IF NOT EXISTS repository TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,repository}') STORED,
+    ADD COLUMN IF NOT EXISTS tag TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,tag}') STORED,
+    ADD COLUMN IF NOT EXISTS platform TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,platform}') STORED,
+    ADD COLUMN IF NOT EXISTS digest TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,digest}') STORED,
+    ADD COLUMN IF NOT EXISTS scan_job_uid TEXT GENERATED ALWAYS AS (object -> 'metadata' -> 'labels' ->> 'sbomscanner.kubewarden.io/scanjob-uid') STORED;
+
+CREATE INDEX IF NOT EXISTS images_registry_idx ON images (registry);
+CREATE INDEX IF NOT EXISTS images_registry_uri_idx ON images (registry_uri);
+CREATE INDEX IF NOT EXISTS images_repository_idx ON images (repository);
+CREATE INDEX IF NOT EXISTS images_tag_idx ON images (tag);
+CREATE INDEX IF NOT EXISTS images_platform_idx ON images (platform);
+CREATE INDEX IF NOT EXISTS images_digest_idx ON images (digest);
+CREATE INDEX IF NOT EXISTS images_scan_job_uid_idx ON images (scan_job_uid);
+CREATE INDEX IF NOT EXISTS images_labels_idx ON images USING GIN ((object -> 'metadata' -> 'labels'));
+
+ALTER TABLE sboms
+    ADD COLUMN IF NOT EXISTS registry TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,registry}') STORED,
+    ADD COLUMN IF NOT EXISTS registry_uri TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,registryURI}') STORED,
+    ADD COLUMN IF NOT EXISTS repository TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,repository}') STORED,
+    ADD COLUMN IF NOT EXISTS tag TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,tag}') STORED,
+    ADD COLUMN IF NOT EXISTS platform TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,platform}') STORED,
+    ADD COLUMN IF NOT EXISTS digest TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,digest}') STORED,
+    ADD COLUMN IF NOT EXISTS scan_job_uid TEXT GENERATED ALWAYS AS (object -> 'metadata' -> 'labels' ->> 'sbomscanner.kubewarden.io/scanjob-uid') STORED;
+
+CREATE INDEX IF NOT EXISTS sboms_registry_idx ON sboms (registry);
+CREATE INDEX IF NOT EXISTS sboms_registry_uri_idx ON sboms (registry_uri);
+CREATE INDEX IF NOT EXISTS sboms_repository_idx ON sboms (repository);
+CREATE INDEX IF NOT EXISTS sboms_tag_idx ON sboms (tag);
+CREATE INDEX IF NOT EXISTS sboms_platform_idx ON sboms (platform);
+CREATE INDEX IF NOT EXISTS sboms_digest_idx ON sboms (digest);
+CREATE INDEX IF NOT EXISTS sboms_scan_job_uid_idx ON sboms (scan_job_uid);
+CREATE INDEX IF NOT EXISTS sboms_labels_idx ON sboms USING GIN ((object -> 'metadata' -> 'labels'));
+
+ALTER TABLE vulnerabilityreports
+    ADD COLUMN IF NOT EXISTS registry TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,registry}') STORED,
+    ADD COLUMN IF NOT EXISTS registry_uri TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,registryURI}') STORED,
+    ADD COLUMN IF NOT EXISTS repository TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,repository}') STORED,
+    ADD COLUMN IF NOT EXISTS tag TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,tag}') STORED,
+    ADD COLUMN IF NOT EXISTS platform TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,platform}') STORED,
+    ADD COLUMN IF NOT EXISTS digest TEXT GENERATED ALWAYS AS (object #>> '{imageMetadata,digest}') STORED,
+    ADD COLUMN IF NOT EXISTS scan_job_uid TEXT GENERATED ALWAYS AS (object -> 'metadata' -> 'labels' ->> 'sbomscanner.kubewarden.io/scanjob-uid') STORED;
+
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_registry_idx ON vulnerabilityreports (registry);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_registry_uri_idx ON vulnerabilityreports (registry_uri);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_repository_idx ON vulnerabilityreports (repository);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_tag_idx ON vulnerabilityreports (tag);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_platform_idx ON vulnerabilityreports (platform);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_digest_idx ON vulnerabilityreports (digest);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_scan_job_uid_idx ON vulnerabilityreports (scan_job_uid);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_labels_idx ON vulnerabilityreports USING GIN ((object -> 'metadata' -> 'labels'));
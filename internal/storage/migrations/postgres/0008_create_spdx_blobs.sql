@@ -0,0 +1,42 @@
+-- The SPDX documents of the SBOMs, compressed with zstd and keyed by the sha256 of the uncompressed document,
+-- so that the SBOMs of identical images share a single copy of their document.
+-- The sboms object column keeps a null spdx field, the document is restored when the SBOMs are read.
+CREATE TABLE IF NOT EXISTS spdx_blobs (
+    hash CHAR(64) PRIMARY KEY,
+    content BYTEA NOT NULL,
+    size BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0 CHECK (ref_count >= 0)
+);
+
+-- The document of the SBOM. It is NULL for the SBOMs written before, which keep their document in the object column
+-- until they are moved to the blobs by the storage server.
+ALTER TABLE sboms ADD COLUMN IF NOT EXISTS spdx_hash CHAR(64) REFERENCES spdx_blobs (hash);
+
+-- Also used to check that a deleted blob is no longer referenced.
+CREATE INDEX IF NOT EXISTS sboms_spdx_hash_idx ON sboms (spdx_hash);
+
+-- Count the SBOMs referencing each blob, and delete the blobs no longer referenced.
+-- Counting in a trigger covers every way the SBOMs are written and deleted, including the collection deletions.
+CREATE OR REPLACE FUNCTION count_spdx_blob_refs() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.spdx_hash IS NOT DISTINCT FROM NEW.spdx_hash THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.spdx_hash IS NOT NULL THEN
+        UPDATE spdx_blobs SET ref_count = ref_count + 1 WHERE hash = NEW.spdx_hash;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.spdx_hash IS NOT NULL THEN
+        UPDATE spdx_blobs SET ref_count = ref_count - 1 WHERE hash = OLD.spdx_hash;
+        DELETE FROM spdx_blobs WHERE hash = OLD.spdx_hash AND ref_count = 0;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS sboms_spdx_blob_refs ON sboms;
+CREATE TRIGGER sboms_spdx_blob_refs
+    AFTER INSERT OR DELETE OR UPDATE OF spdx_hash ON sboms
+    FOR EACH ROW EXECUTE FUNCTION count_spdx_blob_refs();
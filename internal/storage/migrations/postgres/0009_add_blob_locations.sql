@@ -0,0 +1,23 @@
+-- The SPDX blobs offloaded to the blob store keep only their location, instead of their content.
+ALTER TABLE spdx_blobs ALTER COLUMN content DROP NOT NULL;
+ALTER TABLE spdx_blobs ADD COLUMN IF NOT EXISTS location TEXT;
+ALTER TABLE spdx_blobs ADD CONSTRAINT spdx_blobs_content_or_location CHECK ((content IS NULL) <> (location IS NULL));
+
+-- The locations of the blobs no longer referenced, deleted from the blob store by the storage server.
+-- They are recorded in the transaction deleting the reference, as the blob store cannot take part in it.
+CREATE TABLE IF NOT EXISTS blob_deletions (
+    location TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE FUNCTION record_blob_deletion() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO blob_deletions (location) VALUES (OLD.location) ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS spdx_blobs_deletions ON spdx_blobs;
+CREATE TRIGGER spdx_blobs_deletions
+    AFTER DELETE ON spdx_blobs
+    FOR EACH ROW WHEN (OLD.location IS NOT NULL) EXECUTE FUNCTION record_blob_deletion();
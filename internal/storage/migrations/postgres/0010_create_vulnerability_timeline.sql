@@ -0,0 +1,53 @@
+-- The timeline of the vulnerabilities found in an image digest, kept across the rescans overwriting its reports.
+-- A vulnerability is first seen by the first report of the digest listing it, last seen by the latest one,
+-- and resolved once no report of the digest lists it anymore. It is seen again if a later report lists it.
+-- The times are truncated to the second, the precision of the API timestamps.
+CREATE TABLE IF NOT EXISTS vulnerability_timeline (
+    namespace VARCHAR(253) NOT NULL,
+    digest TEXT NOT NULL,
+    cve VARCHAR(253) NOT NULL,
+    package_name TEXT NOT NULL DEFAULT '',
+    purl TEXT NOT NULL DEFAULT '',
+    severity VARCHAR(32) NOT NULL DEFAULT '',
+    first_seen TIMESTAMPTZ NOT NULL,
+    last_seen TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ,
+    PRIMARY KEY (namespace, digest, cve, package_name, purl)
+);
+
+-- The timeline of a digest is deleted with the last report of the digest in the namespace,
+-- as the timeline is only served through the reports of its digest.
+-- The resolved vulnerabilities are then dropped too: resolved_at only reflects the rescans of the same digest.
+-- Deleting in a trigger covers every way the reports are deleted, including the collection deletions.
+CREATE OR REPLACE FUNCTION delete_vulnerability_timeline() RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM vulnerabilityreports WHERE namespace = OLD.namespace AND digest = OLD.digest) THEN
+        DELETE FROM vulnerability_timeline WHERE namespace = OLD.namespace AND digest = OLD.digest;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS vulnerabilityreports_timeline ON vulnerabilityreports;
+CREATE TRIGGER vulnerabilityreports_timeline
+    AFTER DELETE ON vulnerabilityreports
+    FOR EACH ROW WHEN (OLD.digest IS NOT NULL) EXECUTE FUNCTION delete_vulnerability_timeline();
+
+-- The vulnerabilities of the existing reports are first seen when their reports were created.
+-- As when the reports are written, the severity is the one of the first finding of the latest written report.
+INSERT INTO vulnerability_timeline (namespace, digest, cve, package_name, purl, severity, first_seen, last_seen)
+SELECT
+    r.namespace,
+    r.digest,
+    f.cve,
+    f.package_name,
+    f.purl,
+    (array_agg(f.severity ORDER BY r.resource_version DESC, r.name, f.result_index, f.vulnerability_index))[1],
+    date_trunc('second', COALESCE(MIN((r.object #>> '{metadata,creationTimestamp}')::TIMESTAMPTZ), now())),
+    date_trunc('second', now())
+FROM vulnerability_findings f
+JOIN vulnerabilityreports r ON r.name = f.report_name AND r.namespace = f.report_namespace
+WHERE r.digest IS NOT NULL
+GROUP BY r.namespace, r.digest, f.cve, f.package_name, f.purl
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+-- The blobs uploaded to the blob store and not yet referenced by the database.
+-- An upload is recorded before the blob is stored, and deleted by the transaction referencing the blob.
+-- The uploads left by a rolled back transaction or a crash are moved to blob_deletions
+-- by the storage server after a grace period.
+CREATE TABLE IF NOT EXISTS blob_uploads (
+    location TEXT PRIMARY KEY,
+    hash TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    uploaded_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS blob_uploads_hash_idx ON blob_uploads (hash);
+CREATE INDEX IF NOT EXISTS blob_uploads_started_at_idx ON blob_uploads (started_at);
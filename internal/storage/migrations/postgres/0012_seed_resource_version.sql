@@ -0,0 +1,10 @@
+-- The sequence was created at 1 on the databases upgraded with objects already stored,
+-- so that the next writes could be assigned a resourceVersion lower than the existing ones.
+-- The sequence is moved past the highest resourceVersion of the stored objects.
+SELECT setval('resource_version_seq', GREATEST(
+    (SELECT last_value FROM resource_version_seq),
+    (SELECT MAX((object #>> '{metadata,resourceVersion}')::BIGINT) FROM images),
+    (SELECT MAX((object #>> '{metadata,resourceVersion}')::BIGINT) FROM sboms),
+    (SELECT MAX((object #>> '{metadata,resourceVersion}')::BIGINT) FROM vulnerabilityreports),
+    1
+));
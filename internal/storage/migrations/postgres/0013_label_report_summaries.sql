@@ -0,0 +1,15 @@
+-- The summary labels of the reports written before the storage set them, to the counts of their summary.
+-- They are written like the storage writes them, replacing the values set by the clients.
+UPDATE vulnerabilityreports
+SET object = jsonb_set(
+    object,
+    '{metadata,labels}',
+    COALESCE(object -> 'metadata' -> 'labels', '{}'::JSONB) || jsonb_build_object(
+        'sbomscanner.kubewarden.io/summary-critical', COALESCE(object #>> '{report,summary,critical}', '0'),
+        'sbomscanner.kubewarden.io/summary-high', COALESCE(object #>> '{report,summary,high}', '0'),
+        'sbomscanner.kubewarden.io/summary-medium', COALESCE(object #>> '{report,summary,medium}', '0'),
+        'sbomscanner.kubewarden.io/summary-low', COALESCE(object #>> '{report,summary,low}', '0'),
+        'sbomscanner.kubewarden.io/summary-unknown', COALESCE(object #>> '{report,summary,unknown}', '0'),
+        'sbomscanner.kubewarden.io/summary-suppressed', COALESCE(object #>> '{report,summary,suppressed}', '0')
+    )
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS images (
+    name TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    object TEXT NOT NULL,
+    PRIMARY KEY (name, namespace)
+);
+
+CREATE TABLE IF NOT EXISTS sboms (
+    name TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    object TEXT NOT NULL,
+    PRIMARY KEY (name, namespace)
+);
+
+CREATE TABLE IF NOT EXISTS vulnerabilityreports (
+    name TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    object TEXT NOT NULL,
+    PRIMARY KEY (name, namespace)
+);
@@ -0,0 +1,22 @@
+-- The single row of the table holds the last cluster-wide resourceVersion, as SQLite has no sequences.
+-- An empty store is at resourceVersion 1, as the apiserver rejects lists at resourceVersion 0.
+CREATE TABLE IF NOT EXISTS resource_version_seq (
+    value INTEGER NOT NULL
+);
+
+INSERT INTO resource_version_seq (value) SELECT 1 WHERE NOT EXISTS (SELECT 1 FROM resource_version_seq);
+
+-- The log of the events produced by every write, shared by all the resources.
+-- It is used to replay the events to the watchers resuming from a given resourceVersion.
+CREATE TABLE IF NOT EXISTS watch_events (
+    resource_version INTEGER PRIMARY KEY,
+    resource TEXT NOT NULL,
+    type TEXT NOT NULL,
+    name TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    object TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS watch_events_resource_idx ON watch_events (resource, resource_version);
+CREATE INDEX IF NOT EXISTS watch_events_created_at_idx ON watch_events (created_at);
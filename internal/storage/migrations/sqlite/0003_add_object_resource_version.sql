@@ -0,0 +1,9 @@
+-- The resourceVersion of the last write of each object.
+-- The updates and the deletions only apply to the row they have read, so that concurrent writes are detected.
+ALTER TABLE images ADD COLUMN resource_version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sboms ADD COLUMN resource_version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE vulnerabilityreports ADD COLUMN resource_version INTEGER NOT NULL DEFAULT 0;
+
+UPDATE images SET resource_version = CAST(json_extract(object, '$.metadata.resourceVersion') AS INTEGER);
+UPDATE sboms SET resource_version = CAST(json_extract(object, '$.metadata.resourceVersion') AS INTEGER);
+UPDATE vulnerabilityreports SET resource_version = CAST(json_extract(object, '$.metadata.resourceVersion') AS INTEGER);
@@ -0,0 +1,49 @@
+-- The selectable fields and the scanjob-uid label of the objects, generated from the object column so that they can be indexed.
+
+ALTER TABLE images ADD COLUMN registry TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.registry')) VIRTUAL;
+ALTER TABLE images ADD COLUMN registry_uri TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.registryURI')) VIRTUAL;
+ALTER TABLE images ADD COLUMN repository TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.repository')) VIRTUAL;
+ALTER TABLE images ADD COLUMN tag TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.tag')) VIRTUAL;
+ALTER TABLE images ADD COLUMN platform TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.platform')) VIRTUAL;
+ALTER TABLE images ADD COLUMN digest TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.digest')) VIRTUAL;
+ALTER TABLE images ADD COLUMN scan_job_uid TEXT GENERATED ALWAYS AS (json_extract(object, '$.metadata.labels."sbomscanner.kubewarden.io/scanjob-uid"')) VIRTUAL;
+
+CREATE INDEX IF NOT EXISTS images_registry_idx ON images (registry);
+CREATE INDEX IF NOT EXISTS images_registry_uri_idx ON images (registry_uri);
+CREATE INDEX IF NOT EXISTS images_repository_idx ON images (repository);
+CREATE INDEX IF NOT EXISTS images_tag_idx ON images (tag);
+CREATE INDEX IF NOT EXISTS images_platform_idx ON images (platform);
+CREATE INDEX IF NOT EXISTS images_digest_idx ON images (digest);
+CREATE INDEX IF NOT EXISTS images_scan_job_uid_idx ON images (scan_job_uid);
+
+ALTER TABLE sboms ADD COLUMN registry TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.registry')) VIRTUAL;
+ALTER TABLE sboms ADD COLUMN registry_uri TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.registryURI')) VIRTUAL;
+ALTER TABLE sboms ADD COLUMN repository TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.repository')) VIRTUAL;
+ALTER TABLE sboms ADD COLUMN tag TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.tag')) VIRTUAL;
+ALTER TABLE sboms ADD COLUMN platform TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.platform')) VIRTUAL;
+ALTER TABLE sboms ADD COLUMN digest TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.digest')) VIRTUAL;
+ALTER TABLE sboms ADD COLUMN scan_job_uid TEXT GENERATED ALWAYS AS (json_extract(object, '$.metadata.labels."sbomscanner.kubewarden.io/scanjob-uid"')) VIRTUAL;
+
+CREATE INDEX IF NOT EXISTS sboms_registry_idx ON sboms (registry);
+CREATE INDEX IF NOT EXISTS sboms_registry_uri_idx ON sboms (registry_uri);
+CREATE INDEX IF NOT EXISTS sboms_repository_idx ON sboms (repository);
+CREATE INDEX IF NOT EXISTS sboms_tag_idx ON sboms (tag);
+CREATE INDEX IF NOT EXISTS sboms_platform_idx ON sboms (platform);
+CREATE INDEX IF NOT EXISTS sboms_digest_idx ON sboms (digest);
+CREATE INDEX IF NOT EXISTS sboms_scan_job_uid_idx ON sboms (scan_job_uid);
+
+ALTER TABLE vulnerabilityreports ADD COLUMN registry TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.registry')) VIRTUAL;
+ALTER TABLE vulnerabilityreports ADD COLUMN registry_uri TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.registryURI')) VIRTUAL;
+ALTER TABLE vulnerabilityreports ADD COLUMN repository TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.repository')) VIRTUAL;
+ALTER TABLE vulnerabilityreports ADD COLUMN tag TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.tag')) VIRTUAL;
+ALTER TABLE vulnerabilityreports ADD COLUMN platform TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.platform')) VIRTUAL;
+ALTER TABLE vulnerabilityreports ADD COLUMN digest TEXT GENERATED ALWAYS AS (json_extract(object, '$.imageMetadata.digest')) VIRTUAL;
+ALTER TABLE vulnerabilityreports ADD COLUMN scan_job_uid TEXT GENERATED ALWAYS AS (json_extract(object, '$.metadata.labels."sbomscanner.kubewarden.io/scanjob-uid"')) VIRTUAL;
+
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_registry_idx ON vulnerabilityreports (registry);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_registry_uri_idx ON vulnerabilityreports (registry_uri);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_repository_idx ON vulnerabilityreports (repository);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_tag_idx ON vulnerabilityreports (tag);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_platform_idx ON vulnerabilityreports (platform);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_digest_idx ON vulnerabilityreports (digest);
+CREATE INDEX IF NOT EXISTS vulnerabilityreports_scan_job_uid_idx ON vulnerabilityreports (scan_job_uid);
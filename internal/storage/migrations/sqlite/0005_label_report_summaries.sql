@@ -0,0 +1,18 @@
+-- The summary labels of the reports written before the storage set them, to the counts of their summary.
+-- They are written like the storage writes them, replacing the values set by the clients.
+UPDATE vulnerabilityreports
+SET object = json_set(
+    object,
+    '$.metadata.labels',
+    json_patch(
+        COALESCE(json_extract(object, '$.metadata.labels'), '{}'),
+        json_object(
+            'sbomscanner.kubewarden.io/summary-critical', CAST(COALESCE(json_extract(object, '$.report.summary.critical'), 0) AS TEXT),
+            'sbomscanner.kubewarden.io/summary-high', CAST(COALESCE(json_extract(object, '$.report.summary.high'), 0) AS TEXT),
+            'sbomscanner.kubewarden.io/summary-medium', CAST(COALESCE(json_extract(object, '$.report.summary.medium'), 0) AS TEXT),
+            'sbomscanner.kubewarden.io/summary-low', CAST(COALESCE(json_extract(object, '$.report.summary.low'), 0) AS TEXT),
+            'sbomscanner.kubewarden.io/summary-unknown', CAST(COALESCE(json_extract(object, '$.report.summary.unknown'), 0) AS TEXT),
+            'sbomscanner.kubewarden.io/summary-suppressed', CAST(COALESCE(json_extract(object, '$.report.summary.suppressed'), 0) AS TEXT)
+        )
+    )
+);
@@ -0,0 +1,384 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	for _, dialect := range []sqlDialect{postgresDialect{}, sqliteDialect{}} {
+		migrations, err := loadMigrations(dialect.migrationsDir())
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+
+		for i, m := range migrations {
+			require.NotEmpty(t, m.name)
+			require.Len(t, m.checksum, 64)
+			require.NotEmpty(t, m.sql)
+			if i > 0 {
+				require.Greater(t, m.version, migrations[i-1].version)
+			}
+		}
+	}
+}
+
+// migrationsTestSuite runs the migrations tests against an empty database of the given backend.
+type migrationsTestSuite struct {
+	suite.Suite
+	backend     Backend
+	db          Database
+	pgContainer *postgres.PostgresContainer
+}
+
+func (suite *migrationsTestSuite) SetupSuite() {
+	if suite.backend != PostgresBackend {
+		return
+	}
+
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("testdb"),
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpassword"),
+		postgres.BasicWaitStrategies(),
+	)
+	suite.Require().NoError(err, "failed to start postgres container")
+	suite.pgContainer = pgContainer
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	suite.Require().NoError(err, "failed to get connection string")
+
+	pool, err := pgxpool.New(ctx, connStr)
+	suite.Require().NoError(err, "failed to create connection pool")
+	suite.db = NewPostgresDatabase(pool)
+}
+
+func (suite *migrationsTestSuite) TearDownSuite() {
+	if suite.db != nil {
+		suite.Require().NoError(suite.db.Close(), "failed to close database")
+	}
+
+	if suite.pgContainer != nil {
+		err := suite.pgContainer.Terminate(context.Background())
+		suite.Require().NoError(err, "failed to terminate postgres container")
+	}
+}
+
+func (suite *migrationsTestSuite) SetupTest() {
+	switch suite.backend {
+	case PostgresBackend:
+		_, err := suite.db.Exec(context.Background(), "DROP SCHEMA public CASCADE; CREATE SCHEMA public")
+		suite.Require().NoError(err, "failed to reset schema")
+	case SQLiteBackend:
+		if suite.db != nil {
+			suite.Require().NoError(suite.db.Close(), "failed to close database")
+		}
+
+		db, err := OpenSQLiteDatabase(filepath.Join(suite.T().TempDir(), "storage.db"))
+		suite.Require().NoError(err, "failed to open sqlite database")
+		suite.db = db
+	}
+}
+
+func TestMigrationsTestSuite(t *testing.T) {
+	suite.Run(t, &migrationsTestSuite{backend: PostgresBackend})
+}
+
+func TestSQLiteMigrationsTestSuite(t *testing.T) {
+	suite.Run(t, &migrationsTestSuite{backend: SQLiteBackend})
+}
+
+func (suite *migrationsTestSuite) TestMigrate() {
+	ctx := context.Background()
+
+	migrations, err := loadMigrations(suite.db.dialect().migrationsDir())
+	suite.Require().NoError(err)
+
+	statuses, err := GetMigrationStatus(ctx, suite.db)
+	suite.Require().NoError(err)
+	suite.Require().Len(statuses, len(migrations))
+	for _, status := range statuses {
+		suite.Nil(status.AppliedAt)
+	}
+
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+	// Applying the migrations again is a no-op.
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+
+	statuses, err = GetMigrationStatus(ctx, suite.db)
+	suite.Require().NoError(err)
+	suite.Require().Len(statuses, len(migrations))
+	for i, status := range statuses {
+		suite.Equal(migrations[i].version, status.Version)
+		suite.Equal(migrations[i].name, status.Name)
+		suite.NotNil(status.AppliedAt)
+		suite.False(status.Unknown)
+	}
+
+	for _, table := range []string{"images", "sboms", "vulnerabilityreports", "watch_events"} {
+		exists, err := suite.db.dialect().tableExists(ctx, suite.db, table)
+		suite.Require().NoError(err)
+		suite.True(exists, "table %s should exist", table)
+	}
+}
+
+func (suite *migrationsTestSuite) TestMigrateConcurrently() {
+	ctx := context.Background()
+
+	errs := make(chan error)
+	for range 3 {
+		go func() {
+			errs <- Migrate(ctx, suite.db, slog.Default())
+		}()
+	}
+	for range 3 {
+		suite.Require().NoError(<-errs)
+	}
+
+	migrations, err := loadMigrations(suite.db.dialect().migrationsDir())
+	suite.Require().NoError(err)
+
+	var count int
+	err = suite.db.QueryRow(ctx, "SELECT COUNT(*) FROM schema_migrations").Scan(&count)
+	suite.Require().NoError(err)
+	suite.Equal(len(migrations), count)
+}
+
+func (suite *migrationsTestSuite) TestMigrateChecksumMismatch() {
+	ctx := context.Background()
+
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+
+	_, err := suite.db.Exec(ctx, "UPDATE schema_migrations SET checksum = 'tampered' WHERE version = 1")
+	suite.Require().NoError(err)
+
+	err = Migrate(ctx, suite.db, slog.Default())
+	suite.Require().ErrorContains(err, "checksum mismatch for migration 1_create_object_tables")
+
+	_, err = GetMigrationStatus(ctx, suite.db)
+	suite.Require().ErrorContains(err, "checksum mismatch for migration 1_create_object_tables")
+}
+
+func (suite *migrationsTestSuite) TestMigrationStatusUnknownMigration() {
+	ctx := context.Background()
+
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+
+	_, err := suite.db.Exec(
+		ctx,
+		"INSERT INTO schema_migrations (version, name, checksum) VALUES (99999, 'from_the_future', 'checksum')",
+	)
+	suite.Require().NoError(err)
+
+	// A migration applied by a newer version does not prevent an older version from starting.
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+
+	statuses, err := GetMigrationStatus(ctx, suite.db)
+	suite.Require().NoError(err)
+	lastStatus := statuses[len(statuses)-1]
+	suite.Equal(int64(99999), lastStatus.Version)
+	suite.True(lastStatus.Unknown)
+}
+
+func (suite *migrationsTestSuite) TestDatabaseHealthCheck() {
+	healthCheck := NewDatabaseHealthCheck(suite.db)
+	suite.Equal("database", healthCheck.Name())
+
+	request := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	suite.Require().ErrorContains(healthCheck.Check(request), "pending migrations: 1_create_object_tables")
+
+	suite.Require().NoError(Migrate(context.Background(), suite.db, slog.Default()))
+	suite.Require().NoError(healthCheck.Check(request))
+}
+
+func (suite *migrationsTestSuite) TestMigrateResourceVersionSeed() {
+	if suite.backend != PostgresBackend {
+		suite.T().Skip("the SQLite backend creates the resource versions with the object tables")
+	}
+
+	ctx := context.Background()
+
+	migrations, err := loadMigrations(suite.db.dialect().migrationsDir())
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(ctx, suite.db.dialect().createSchemaMigrationsTableSQL())
+	suite.Require().NoError(err)
+	// The objects are written before the sequence is created.
+	suite.Require().NoError(applyMigration(ctx, suite.db, migrations[0], slog.Default()))
+
+	object := func(resourceVersion string) string {
+		return `{"metadata": {"resourceVersion": "` + resourceVersion + `"}, "imageMetadata": {"digest": "sha256:digest"}}`
+	}
+	for table, resourceVersion := range map[string]string{"images": "12", "sboms": "42", "vulnerabilityreports": "7"} {
+		_, err = suite.db.Exec(
+			ctx,
+			"INSERT INTO "+table+" (name, namespace, object) VALUES ('test', 'default', $1)",
+			object(resourceVersion),
+		)
+		suite.Require().NoError(err)
+	}
+
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+
+	// The next write is assigned a resourceVersion higher than the ones of the stored objects.
+	var next int64
+	err = suite.db.QueryRow(ctx, "SELECT nextval('resource_version_seq')").Scan(&next)
+	suite.Require().NoError(err)
+	suite.Equal(int64(43), next)
+}
+
+func (suite *migrationsTestSuite) TestMigrateReportSummaryLabels() {
+	ctx := context.Background()
+
+	migrations, err := loadMigrations(suite.db.dialect().migrationsDir())
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(ctx, suite.db.dialect().createSchemaMigrationsTableSQL())
+	suite.Require().NoError(err)
+	// The reports are written before the labels are backfilled.
+	for _, m := range migrations {
+		if m.name == "label_report_summaries" {
+			break
+		}
+		suite.Require().NoError(applyMigration(ctx, suite.db, m, slog.Default()))
+	}
+
+	_, err = suite.db.Exec(
+		ctx,
+		"INSERT INTO vulnerabilityreports (name, namespace, object) VALUES ('labeled', 'default', $1), ('unlabeled', 'default', $2)",
+		`{
+			"metadata": {"name": "labeled", "namespace": "default", "resourceVersion": "2", "labels": {
+				"app": "test",
+				"sbomscanner.kubewarden.io/summary-critical": "100"
+			}},
+			"imageMetadata": {"digest": "sha256:digest"},
+			"report": {"summary": {"critical": 3, "high": 2, "medium": 0, "low": 1, "unknown": 0, "suppressed": 4}}
+		}`,
+		`{
+			"metadata": {"name": "unlabeled", "namespace": "default", "resourceVersion": "3"},
+			"imageMetadata": {"digest": "sha256:digest"},
+			"report": {}
+		}`,
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+
+	rows, err := suite.db.Query(ctx, "SELECT name, object FROM vulnerabilityreports")
+	suite.Require().NoError(err)
+	type reportRow struct {
+		name   string
+		object []byte
+	}
+	reports, err := collectRows(rows, func(row row) (reportRow, error) {
+		var report reportRow
+		err := row.Scan(&report.name, &report.object)
+
+		return report, err
+	})
+	suite.Require().NoError(err)
+
+	labels := map[string]map[string]string{}
+	for _, row := range reports {
+		report := &v1alpha1.VulnerabilityReport{}
+		suite.Require().NoError(json.Unmarshal(row.object, report))
+		labels[row.name] = report.Labels
+	}
+
+	// The labels are set to the counts of the summary, replacing the values set by the clients.
+	suite.Equal(map[string]map[string]string{
+		"labeled": {
+			"app":                              "test",
+			v1alpha1.LabelSummaryCriticalKey:   "3",
+			v1alpha1.LabelSummaryHighKey:       "2",
+			v1alpha1.LabelSummaryMediumKey:     "0",
+			v1alpha1.LabelSummaryLowKey:        "1",
+			v1alpha1.LabelSummaryUnknownKey:    "0",
+			v1alpha1.LabelSummarySuppressedKey: "4",
+		},
+		"unlabeled": {
+			v1alpha1.LabelSummaryCriticalKey:   "0",
+			v1alpha1.LabelSummaryHighKey:       "0",
+			v1alpha1.LabelSummaryMediumKey:     "0",
+			v1alpha1.LabelSummaryLowKey:        "0",
+			v1alpha1.LabelSummaryUnknownKey:    "0",
+			v1alpha1.LabelSummarySuppressedKey: "0",
+		},
+	}, labels)
+}
+
+func (suite *migrationsTestSuite) TestMigrateVulnerabilityTimeline() {
+	if suite.backend != PostgresBackend {
+		suite.T().Skip("the vulnerability timeline is only stored by the PostgreSQL backend")
+	}
+
+	ctx := context.Background()
+
+	migrations, err := loadMigrations(suite.db.dialect().migrationsDir())
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(ctx, suite.db.dialect().createSchemaMigrationsTableSQL())
+	suite.Require().NoError(err)
+	// The reports are written before their vulnerabilities are moved to the findings.
+	for _, m := range migrations[:2] {
+		suite.Require().NoError(applyMigration(ctx, suite.db, m, slog.Default()))
+	}
+
+	report := func(resourceVersion string, vulnerabilities ...string) string {
+		return `{
+			"metadata": {"resourceVersion": "` + resourceVersion + `", "creationTimestamp": "2025-01-01T00:00:00Z"},
+			"imageMetadata": {"digest": "sha256:digest"},
+			"report": {"results": [{"vulnerabilities": [` + strings.Join(vulnerabilities, ",") + `]}]}
+		}`
+	}
+	vulnerability := func(cve, severity string) string {
+		return `{"cve": "` + cve + `", "packageName": "openssl", "purl": "pkg:apk/alpine/openssl", "severity": "` + severity + `"}`
+	}
+	_, err = suite.db.Exec(
+		ctx,
+		"INSERT INTO vulnerabilityreports (name, namespace, object) VALUES ('old', 'default', $1), ('new', 'default', $2)",
+		report("1", vulnerability("CVE-1", "LOW"), vulnerability("CVE-2", "HIGH"), vulnerability("CVE-2", "LOW")),
+		report("2", vulnerability("CVE-1", "CRITICAL")),
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(Migrate(ctx, suite.db, slog.Default()))
+
+	rows, err := suite.db.Query(ctx, "SELECT cve, severity, first_seen FROM vulnerability_timeline ORDER BY cve")
+	suite.Require().NoError(err)
+	type timelineRow struct {
+		cve       string
+		severity  string
+		firstSeen time.Time
+	}
+	timeline, err := collectRows(rows, func(row row) (timelineRow, error) {
+		var entry timelineRow
+		err := row.Scan(&entry.cve, &entry.severity, &entry.firstSeen)
+
+		return entry, err
+	})
+	suite.Require().NoError(err)
+
+	// The severities are the ones of the first findings of the latest written reports, not the lexically highest ones.
+	firstSeen := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.Require().Len(timeline, 2)
+	suite.Equal("CVE-1", timeline[0].cve)
+	suite.Equal("CRITICAL", timeline[0].severity)
+	suite.True(firstSeen.Equal(timeline[0].firstSeen))
+	suite.Equal("CVE-2", timeline[1].cve)
+	suite.Equal("HIGH", timeline[1].severity)
+	suite.True(firstSeen.Equal(timeline[1].firstSeen))
+}
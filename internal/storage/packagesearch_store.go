@@ -0,0 +1,333 @@
+//nolint:wrapcheck // We want to return the errors from k8s.io/apiserver as they are.
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+var (
+	_ rest.Storage              = &packageSearchStore{}
+	_ rest.Scoper               = &packageSearchStore{}
+	_ rest.Creater              = &packageSearchStore{}
+	_ rest.SingularNameProvider = &packageSearchStore{}
+)
+
+// packageSearchPageSize is the number of packages read from the database at a time
+// until the limit of matches is reached.
+const packageSearchPageSize = 1000
+
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// packageSearchStore serves the create-only PackageSearch resource.
+// The packages are searched in the sbom_packages table, which indexes the packages of the stored SBOMs.
+type packageSearchStore struct {
+	db         Database
+	authorizer authorizer.Authorizer
+	logger     *slog.Logger
+}
+
+// NewPackageSearchStore returns a create-only store searching the packages of the SBOMs.
+// The resource is cluster-scoped, so the authorizer restricts the search to the namespaces
+// where the user creating the search is allowed to list the SBOMs.
+func NewPackageSearchStore(db Database, authz authorizer.Authorizer, logger *slog.Logger) rest.Storage {
+	return &packageSearchStore{
+		db:         db,
+		authorizer: authz,
+		logger:     logger.With("store", "packagesearch"),
+	}
+}
+
+// packageSearchContinue is the position of the last match returned by a search, encoded in its continue token.
+type packageSearchContinue struct {
+	Namespace string `json:"namespace"`
+	SBOM      string `json:"sbom"`
+	Index     int    `json:"index"`
+}
+
+// New returns an empty PackageSearch.
+func (s *packageSearchStore) New() runtime.Object {
+	return &v1alpha1.PackageSearch{}
+}
+
+// Destroy cleans up the resources of the store.
+func (s *packageSearchStore) Destroy() {
+	// Nothing to clean up, the database connection pool is shared with the other stores.
+}
+
+// NamespaceScoped returns false, as the packages of all the namespaces the user can list the SBOMs of can be searched.
+func (s *packageSearchStore) NamespaceScoped() bool {
+	return false
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *packageSearchStore) GetSingularName() string {
+	return "packagesearch"
+}
+
+// Create runs the search and returns the PackageSearch with the matching packages in its status.
+// The PackageSearch is not persisted.
+// The matches are ordered by namespace, SBOM and position of the package in the SBOM.
+func (s *packageSearchStore) Create(
+	ctx context.Context,
+	obj runtime.Object,
+	createValidation rest.ValidateObjectFunc,
+	_ *metav1.CreateOptions,
+) (runtime.Object, error) {
+	search, ok := obj.(*v1alpha1.PackageSearch)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unexpected object type: %T", obj))
+	}
+
+	s.logger.DebugContext(ctx, "Searching packages", "spec", search.Spec)
+
+	var constraints *semver.Constraints
+	var after *packageSearchContinue
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	if search.Spec.Name == "" && search.Spec.PURLPrefix == "" {
+		errs = append(errs, field.Required(specPath, "one of name or purlPrefix is required"))
+	}
+	if search.Spec.Limit < 0 {
+		errs = append(errs, field.Invalid(specPath.Child("limit"), search.Spec.Limit, "must be greater than or equal to 0"))
+	}
+	if search.Spec.Continue != "" {
+		var err error
+		after, err = decodePackageSearchContinue(search.Spec.Continue)
+		if err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("continue"), search.Spec.Continue, err.Error()))
+		}
+	}
+	if search.Spec.VersionConstraint != "" {
+		var err error
+		constraints, err = semver.NewConstraint(search.Spec.VersionConstraint)
+		if err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("versionConstraint"), search.Spec.VersionConstraint, err.Error()))
+		} else {
+			// Distribution versions, such as "5.6.0-0.2", are parsed as pre-releases.
+			constraints.IncludePrerelease = true
+		}
+	}
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.Kind("PackageSearch"), search.Name, errs)
+	}
+
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	// The SBOMs of the namespace, or of all the namespaces the user can list them in, are searched.
+	var namespaces []string
+	if search.Spec.Namespace != "" {
+		if err := authorizeNamespace(ctx, s.authorizer, "sboms", search.Spec.Namespace); err != nil {
+			return nil, err
+		}
+		namespaces = []string{search.Spec.Namespace}
+	} else {
+		authorized, all, err := authorizedNamespaces(ctx, s.authorizer, s.db, "sboms")
+		if err != nil {
+			return nil, err
+		}
+		if !all {
+			namespaces = authorized
+		}
+	}
+
+	result := search.DeepCopy()
+	result.Status.Matches = []v1alpha1.PackageMatch{}
+	if namespaces != nil && len(namespaces) == 0 {
+		return result, nil
+	}
+
+	matches, next, err := s.search(ctx, search.Spec, namespaces, constraints, after)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	result.Status.Matches = matches
+	if next != nil {
+		if result.Status.Continue, err = encodePackageSearchContinue(next); err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+	}
+
+	return result, nil
+}
+
+// search returns the packages matching the spec and the version constraints, in the namespaces if any is given,
+// following the position of the previous search if any.
+// The packages are read by pages, as the version constraints are checked once they are read,
+// until the limit of the spec is reached. The position of the last match is returned if there are more.
+func (s *packageSearchStore) search(
+	ctx context.Context,
+	spec v1alpha1.PackageSearchSpec,
+	namespaces []string,
+	constraints *semver.Constraints,
+	after *packageSearchContinue,
+) ([]v1alpha1.PackageMatch, *packageSearchContinue, error) {
+	matches := []v1alpha1.PackageMatch{}
+	var last *packageSearchContinue
+	for {
+		page, err := s.searchPage(ctx, spec, namespaces, after)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := range page {
+			match := &page[i]
+			after = &match.position
+			if constraints != nil {
+				version, err := semver.NewVersion(match.Version)
+				if err != nil || !constraints.Check(version) {
+					continue
+				}
+			}
+			// A match beyond the limit means there are more, the next search continues after the last returned one.
+			if spec.Limit > 0 && int64(len(matches)) == spec.Limit {
+				return matches, last, nil
+			}
+			matches = append(matches, match.PackageMatch)
+			last = &match.position
+		}
+
+		if len(page) < packageSearchPageSize {
+			return matches, nil, nil
+		}
+	}
+}
+
+// packageSearchRow is a package read by the search, with its position.
+type packageSearchRow struct {
+	v1alpha1.PackageMatch
+	position packageSearchContinue
+}
+
+// searchPage returns the next page of packages matching the spec, in the namespaces if any is given,
+// following the given position if any.
+func (s *packageSearchStore) searchPage(
+	ctx context.Context,
+	spec v1alpha1.PackageSearchSpec,
+	namespaces []string,
+	after *packageSearchContinue,
+) ([]packageSearchRow, error) {
+	queryBuilder := psql.Select(
+		sm.Columns(
+			psql.Quote("p", "sbom_namespace"),
+			psql.Quote("p", "sbom_name"),
+			psql.Quote("p", "package_index"),
+			psql.Raw("s.object->'imageMetadata'"),
+			psql.Quote("p", "name"),
+			psql.Quote("p", "version"),
+			psql.Quote("p", "purl"),
+		),
+		sm.From("sbom_packages").As("p"),
+		sm.InnerJoin("sboms").As("s").On(
+			psql.Quote("s", "name").EQ(psql.Quote("p", "sbom_name")),
+			psql.Quote("s", "namespace").EQ(psql.Quote("p", "sbom_namespace")),
+		),
+		sm.OrderBy(psql.Quote("p", "sbom_namespace")),
+		sm.OrderBy(psql.Quote("p", "sbom_name")),
+		sm.OrderBy(psql.Quote("p", "package_index")),
+		sm.Limit(packageSearchPageSize),
+	)
+	if namespaces != nil {
+		queryBuilder.Apply(sm.Where(psql.Raw("p.sbom_namespace = ANY(?)", namespaces)))
+	}
+	if spec.Name != "" {
+		queryBuilder.Apply(sm.Where(psql.Quote("p", "name").EQ(psql.Arg(spec.Name))))
+	}
+	if spec.PURLPrefix != "" {
+		queryBuilder.Apply(sm.Where(psql.Quote("p", "purl").Like(psql.Arg(likeEscaper.Replace(spec.PURLPrefix) + "%"))))
+	}
+	if after != nil {
+		queryBuilder.Apply(sm.Where(psql.Raw(
+			"(p.sbom_namespace, p.sbom_name, p.package_index) > (?, ?, ?)",
+			after.Namespace, after.SBOM, after.Index,
+		)))
+	}
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search packages: %w", err)
+	}
+	defer rows.Close()
+
+	var page []packageSearchRow
+	for rows.Next() {
+		var match packageSearchRow
+		var imageMetadata []byte
+		if err = rows.Scan(
+			&match.Namespace,
+			&match.Image,
+			&match.position.Index,
+			&imageMetadata,
+			&match.PackageName,
+			&match.Version,
+			&match.PURL,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan package: %w", err)
+		}
+		match.position.Namespace = match.Namespace
+		match.position.SBOM = match.Image
+
+		if err = unmarshalNullable(imageMetadata, &match.ImageMetadata); err != nil {
+			return nil, err
+		}
+
+		page = append(page, match)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search packages: %w", err)
+	}
+
+	return page, nil
+}
+
+// encodePackageSearchContinue returns the continue token of the search following the given position.
+func encodePackageSearchContinue(position *packageSearchContinue) (string, error) {
+	token, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode continue token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// decodePackageSearchContinue returns the position of the given continue token.
+func decodePackageSearchContinue(token string) (*packageSearchContinue, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid continue token: %w", err)
+	}
+
+	position := &packageSearchContinue{}
+	if err = json.Unmarshal(decoded, position); err != nil {
+		return nil, fmt.Errorf("invalid continue token: %w", err)
+	}
+
+	return position, nil
+}
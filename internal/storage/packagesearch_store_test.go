@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func (suite *storeTestSuite) TestPackageSearch() {
+	suite.requirePostgres()
+
+	sbomStore := newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		sbomHooks(suite.db, nil),
+		slog.Default(),
+	)
+	defer sbomStore.destroy()
+
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	suite.Require().NoError(err)
+
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		ImageMetadata: v1alpha1.ImageMetadata{
+			Registry:    "test-registry",
+			RegistryURI: "ghcr.io",
+			Repository:  "kubewarden/sbomscanner/test-assets/golang",
+			Tag:         "1.12-alpine",
+			Platform:    "linux/amd64",
+			Digest:      "sha256:1782cafde43390b032f960c0fad3def745fac18994ced169003cb56e9a93c028",
+		},
+		SPDX: runtime.RawExtension{Raw: spdx},
+	}
+	err = sbomStore.Create(context.Background(), keyPrefix+"/default/test", sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+	privateSBOM := sbom.DeepCopy()
+	privateSBOM.Namespace = "private"
+	err = sbomStore.Create(context.Background(), keyPrefix+"/private/test", privateSBOM, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	expectedMatch := v1alpha1.PackageMatch{
+		Namespace:     "default",
+		Image:         "test",
+		ImageMetadata: sbom.ImageMetadata,
+		PackageName:   "alpine-keys",
+		Version:       "2.1-r2",
+		PURL:          "pkg:apk/alpine/alpine-keys@2.1-r2?arch=x86_64&distro=3.11.3",
+	}
+
+	tests := []struct {
+		name            string
+		spec            v1alpha1.PackageSearchSpec
+		expectedMatches []v1alpha1.PackageMatch
+	}{
+		{
+			name:            "by name",
+			spec:            v1alpha1.PackageSearchSpec{Name: "alpine-keys"},
+			expectedMatches: []v1alpha1.PackageMatch{expectedMatch},
+		},
+		{
+			name:            "by PURL prefix",
+			spec:            v1alpha1.PackageSearchSpec{PURLPrefix: "pkg:apk/alpine/alpine-keys@"},
+			expectedMatches: []v1alpha1.PackageMatch{expectedMatch},
+		},
+		{
+			name:            "by matching version constraint",
+			spec:            v1alpha1.PackageSearchSpec{Name: "alpine-keys", VersionConstraint: ">= 2.0, < 2.2"},
+			expectedMatches: []v1alpha1.PackageMatch{expectedMatch},
+		},
+		{
+			name:            "by not matching version constraint",
+			spec:            v1alpha1.PackageSearchSpec{Name: "alpine-keys", VersionConstraint: "< 2.0"},
+			expectedMatches: []v1alpha1.PackageMatch{},
+		},
+		{
+			name:            "by other namespace",
+			spec:            v1alpha1.PackageSearchSpec{Name: "alpine-keys", Namespace: "other"},
+			expectedMatches: []v1alpha1.PackageMatch{},
+		},
+	}
+
+	// The admin user can list the SBOMs of every namespace, the other users only the ones of the default namespace.
+	authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if a.GetVerb() != "list" || a.GetResource() != "sboms" || a.GetAPIGroup() != v1alpha1.GroupName {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+		if a.GetUser().GetName() == "admin" || a.GetNamespace() == "default" {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "not allowed", nil
+	})
+	packageSearchStore, ok := NewPackageSearchStore(suite.db, authz, slog.Default()).(*packageSearchStore)
+	suite.Require().True(ok)
+	searchAs := func(userName string, spec v1alpha1.PackageSearchSpec) (*v1alpha1.PackageSearch, error) {
+		ctx := context.Background()
+		if userName != "" {
+			ctx = genericapirequest.WithUser(ctx, &user.DefaultInfo{Name: userName})
+		}
+		obj, err := packageSearchStore.Create(ctx, &v1alpha1.PackageSearch{Spec: spec}, nil, &metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		search, ok := obj.(*v1alpha1.PackageSearch)
+		suite.Require().True(ok)
+
+		return search, nil
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			search, err := searchAs("reader", test.spec)
+			suite.Require().NoError(err)
+			suite.Equal(test.expectedMatches, search.Status.Matches)
+			suite.Empty(search.Status.Continue)
+		})
+	}
+
+	// The packages of the namespaces the user cannot list the SBOMs of are not searched.
+	privateMatch := expectedMatch
+	privateMatch.Namespace = "private"
+	search, err := searchAs("admin", v1alpha1.PackageSearchSpec{Name: "alpine-keys"})
+	suite.Require().NoError(err)
+	suite.Equal([]v1alpha1.PackageMatch{expectedMatch, privateMatch}, search.Status.Matches)
+
+	_, err = searchAs("reader", v1alpha1.PackageSearchSpec{Name: "alpine-keys", Namespace: "private"})
+	suite.True(apierrors.IsForbidden(err), "unexpected error: %v", err)
+	_, err = searchAs("", v1alpha1.PackageSearchSpec{Name: "alpine-keys"})
+	suite.True(apierrors.IsForbidden(err), "unexpected error: %v", err)
+
+	// The matches are returned by pages of the limit.
+	var pages [][]v1alpha1.PackageMatch
+	spec := v1alpha1.PackageSearchSpec{PURLPrefix: "pkg:apk/alpine/", VersionConstraint: ">= 0", Limit: 3}
+	for {
+		search, err = searchAs("admin", spec)
+		suite.Require().NoError(err)
+		pages = append(pages, search.Status.Matches)
+		if search.Status.Continue == "" {
+			break
+		}
+		spec.Continue = search.Status.Continue
+	}
+	search, err = searchAs("admin", v1alpha1.PackageSearchSpec{PURLPrefix: "pkg:apk/alpine/", VersionConstraint: ">= 0"})
+	suite.Require().NoError(err)
+	suite.Require().Greater(len(search.Status.Matches), 3)
+	suite.Len(pages, (len(search.Status.Matches)+2)/3)
+	var paged []v1alpha1.PackageMatch
+	for _, page := range pages {
+		suite.LessOrEqual(len(page), 3)
+		paged = append(paged, page...)
+	}
+	suite.Equal(search.Status.Matches, paged)
+
+	for _, spec := range []v1alpha1.PackageSearchSpec{
+		{},
+		{Name: "alpine-keys", Limit: -1},
+		{Name: "alpine-keys", Continue: "invalid"},
+	} {
+		_, err = searchAs("admin", spec)
+		suite.True(apierrors.IsInvalid(err), "unexpected error: %v", err)
+	}
+}
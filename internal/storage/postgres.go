@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stephenafamo/bob/dialect/psql"
+)
+
+// watchEventsLockID is the key of the transaction-level advisory lock taken by writers
+// before allocating a new resourceVersion.
+// Holding the lock until commit guarantees that resourceVersions become visible in order,
+// so that tailing the watch_events table never skips an event.
+const watchEventsLockID = 0x73626f6d
+
+// watchEventsChannel is the channel notified with the name of the resource every time an event is recorded.
+// Notifications are delivered on commit to every storage replica listening on the channel.
+const watchEventsChannel = "watch_events"
+
+// migrationsLockID is the key of the session-level advisory lock held while migrating,
+// so that concurrent storage replicas do not race.
+const migrationsLockID = 0x73626f6e
+
+var _ Database = &postgresDatabase{}
+
+// postgresDatabase stores the objects in PostgreSQL.
+// Notifications are delivered to every storage replica with LISTEN/NOTIFY.
+type postgresDatabase struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresDatabase returns a Database backed by the given connection pool.
+// The pool is closed with the database.
+func NewPostgresDatabase(pool *pgxpool.Pool) Database {
+	return &postgresDatabase{pool: pool}
+}
+
+func (d *postgresDatabase) Backend() Backend {
+	return PostgresBackend
+}
+
+func (d *postgresDatabase) Close() error {
+	d.pool.Close()
+
+	return nil
+}
+
+func (d *postgresDatabase) Ping(ctx context.Context) error {
+	return d.pool.Ping(ctx)
+}
+
+func (d *postgresDatabase) Exec(ctx context.Context, sql string, args ...any) (int64, error) {
+	result, err := d.pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (d *postgresDatabase) Query(ctx context.Context, sql string, args ...any) (rows, error) {
+	return d.pool.Query(ctx, sql, args...)
+}
+
+func (d *postgresDatabase) QueryRow(ctx context.Context, sql string, args ...any) row {
+	return d.pool.QueryRow(ctx, sql, args...)
+}
+
+func (d *postgresDatabase) begin(ctx context.Context) (transaction, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &postgresTransaction{tx: tx}, nil
+}
+
+func (d *postgresDatabase) beginSnapshot(ctx context.Context) (transaction, error) {
+	tx, err := d.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+
+	return &postgresTransaction{tx: tx}, nil
+}
+
+func (d *postgresDatabase) listen(ctx context.Context, listening func(), notify func(resource string)) error {
+	poolConn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// The connection is taken out of the pool, as it is held for the listener lifetime.
+	conn := poolConn.Hijack()
+	defer func() {
+		// The error is irrelevant, the connection is dropped anyway.
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+watchEventsChannel); err != nil {
+		return fmt.Errorf("failed to listen on %s channel: %w", watchEventsChannel, err)
+	}
+
+	listening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+
+		notify(notification.Payload)
+	}
+}
+
+func (d *postgresDatabase) withMigrationsLock(ctx context.Context, fn func() error) (err error) {
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID); err != nil {
+		return fmt.Errorf("failed to acquire migrations lock: %w", err)
+	}
+	defer func() {
+		// Use a new context, the lock must be released even if the migration was canceled.
+		if _, unlockErr := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockID); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to release migrations lock: %w", unlockErr))
+		}
+	}()
+
+	return fn()
+}
+
+func (d *postgresDatabase) dialect() sqlDialect {
+	return postgresDialect{}
+}
+
+// postgresTransaction is a PostgreSQL transaction.
+type postgresTransaction struct {
+	tx pgx.Tx
+}
+
+func (t *postgresTransaction) Exec(ctx context.Context, sql string, args ...any) (int64, error) {
+	result, err := t.tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (t *postgresTransaction) Query(ctx context.Context, sql string, args ...any) (rows, error) {
+	return t.tx.Query(ctx, sql, args...)
+}
+
+func (t *postgresTransaction) QueryRow(ctx context.Context, sql string, args ...any) row {
+	return t.tx.QueryRow(ctx, sql, args...)
+}
+
+func (t *postgresTransaction) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *postgresTransaction) Rollback(ctx context.Context) error {
+	if err := t.tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (t *postgresTransaction) notify(ctx context.Context, resource string) error {
+	if _, err := t.tx.Exec(ctx, "SELECT pg_notify($1, $2)", watchEventsChannel, resource); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *postgresTransaction) copyFrom(ctx context.Context, table string, columns []string, rows [][]any) error {
+	if _, err := t.tx.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// postgresDialect builds SQL using the PostgreSQL JSONB operators.
+type postgresDialect struct{}
+
+func (postgresDialect) lockWrites(ctx context.Context, tx transaction) error {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", watchEventsLockID); err != nil {
+		return fmt.Errorf("failed to acquire watch events lock: %w", err)
+	}
+
+	return nil
+}
+
+func (d postgresDialect) nextResourceVersions(ctx context.Context, tx transaction, count int) (uint64, error) {
+	if err := d.lockWrites(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	// The lock is held, so no other writer advances the sequence between nextval and setval.
+	var lastResourceVersion int64
+	if err := tx.QueryRow(
+		ctx,
+		"SELECT setval('resource_version_seq', nextval('resource_version_seq') + $1 - 1)",
+		count,
+	).Scan(&lastResourceVersion); err != nil {
+		return 0, fmt.Errorf("failed to allocate resource versions: %w", err)
+	}
+
+	return uint64(lastResourceVersion - int64(count) + 1), nil //nolint:gosec // sequences are always positive
+}
+
+func (postgresDialect) jsonText(path ...string) psql.Expression {
+	return psql.Raw("object #>> ?", path)
+}
+
+// The label expressions match the expression of the GIN index on the labels, so that the index can be used.
+// The ? operator is escaped, as it would otherwise be read as a placeholder.
+func (postgresDialect) labelEquals(key, value string) psql.Expression {
+	return psql.Raw("object -> 'metadata' -> 'labels' @> jsonb_build_object(?::TEXT, ?::TEXT)", key, value)
+}
+
+func (postgresDialect) labelExists(key string) psql.Expression {
+	return psql.Raw(`object -> 'metadata' -> 'labels' \? ?::TEXT`, key)
+}
+
+func (postgresDialect) in(expression psql.Expression, values []string) psql.Expression {
+	return psql.Raw("? = ANY(?)", expression, values)
+}
+
+func (postgresDialect) notIn(expression psql.Expression, values []string) psql.Expression {
+	return psql.Raw("? != ALL(?)", expression, values)
+}
+
+func (postgresDialect) integer(expression psql.Expression) psql.Expression {
+	// The ? of the regular expression is escaped, as it would otherwise be read as a placeholder.
+	return psql.Raw(`CASE WHEN ? ~ '^[+-]\?[0-9]+$' THEN (?)::NUMERIC END`, expression, expression)
+}
+
+func (postgresDialect) rowLocks() bool {
+	return true
+}
+
+func (postgresDialect) ago(duration time.Duration) psql.Expression {
+	return psql.Raw("now() - make_interval(secs => ?)", duration.Seconds())
+}
+
+func (postgresDialect) objectSize(expression psql.Expression) psql.Expression {
+	return psql.Raw("pg_column_size(?)", expression)
+}
+
+func (postgresDialect) migrationsDir() string {
+	return "migrations/postgres"
+}
+
+func (postgresDialect) createSchemaMigrationsTableSQL() string {
+	return `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(253) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`
+}
+
+func (postgresDialect) tableExists(ctx context.Context, q querier, table string) (bool, error) {
+	var exists bool
+	if err := q.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
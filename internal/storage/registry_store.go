@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage"
+	storeerr "k8s.io/apiserver/pkg/storage/errors"
+	storagemetrics "k8s.io/apiserver/pkg/storage/etcd3/metrics"
+)
+
+// statsPollJitter is the jitter factor of the period the stats of the resources are polled with.
+const statsPollJitter = 0.25
+
+// RegistryStore is the registry store of a resource persisted in the database.
+// It deletes the collections of objects in a single transaction,
+// instead of deleting the objects one at a time as the registry does.
+type RegistryStore struct {
+	*registry.Store
+
+	// objects is the store persisting the objects, behind the watch cache if any.
+	objects *store
+}
+
+// DeleteCollection deletes the objects matching the list options in a single transaction.
+// The deletions the transaction does not support are delegated to the registry, which deletes
+// the objects one at a time: the dry runs, the deletions with preconditions, orphaning the dependents
+// or waiting for them, and the lists from a resourceVersion or paginated.
+// The objects with finalizers are also deleted by the registry, which sets their deletionTimestamp.
+func (e *RegistryStore) DeleteCollection(
+	ctx context.Context,
+	deleteValidation rest.ValidateObjectFunc,
+	options *metav1.DeleteOptions,
+	listOptions *metainternalversion.ListOptions,
+) (runtime.Object, error) {
+	if options == nil {
+		options = &metav1.DeleteOptions{}
+	}
+	if listOptions == nil {
+		listOptions = &metainternalversion.ListOptions{}
+	}
+	if !deletesInTransaction(options, listOptions) {
+		return e.Store.DeleteCollection(ctx, deleteValidation, options, listOptions)
+	}
+	if deleteValidation == nil {
+		deleteValidation = rest.ValidateAllObjectFunc
+	}
+
+	label := labels.Everything()
+	if listOptions.LabelSelector != nil {
+		label = listOptions.LabelSelector
+	}
+	field := fields.Everything()
+	if listOptions.FieldSelector != nil {
+		field = listOptions.FieldSelector
+	}
+
+	deleted, finalized, err := e.objects.deleteCollection(
+		ctx,
+		e.KeyRootFunc(ctx),
+		e.PredicateFunc(label, field),
+		storage.ValidateObjectFunc(deleteValidation),
+	)
+	if err != nil {
+		return nil, storeerr.InterpretListError(err, e.DefaultQualifiedResource)
+	}
+
+	for _, obj := range finalized {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+
+		objCtx := genericapirequest.WithNamespace(ctx, accessor.GetNamespace())
+		if _, _, err = e.Delete(objCtx, accessor.GetName(), deleteValidation, options.DeepCopy()); err != nil &&
+			!apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	listObj := e.NewListFunc()
+	if err = meta.SetList(listObj, append(deleted, finalized...)); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	return listObj, nil
+}
+
+// deletesInTransaction reports whether a collection can be deleted in a single transaction.
+func deletesInTransaction(options *metav1.DeleteOptions, listOptions *metainternalversion.ListOptions) bool {
+	if len(options.DryRun) > 0 || options.Preconditions != nil {
+		return false
+	}
+	if options.OrphanDependents != nil && *options.OrphanDependents {
+		return false
+	}
+	if options.PropagationPolicy != nil && *options.PropagationPolicy != metav1.DeletePropagationBackground {
+		return false
+	}
+
+	return listOptions.ResourceVersion == "" && listOptions.Limit == 0 && listOptions.Continue == ""
+}
+
+// completeStore completes the registry store with the REST options of its resource,
+// then serves its reads from the watch cache, if configured.
+// As the registry only observes the stats of the storage it creates, they are observed here
+// every CountMetricPollPeriod.
+func completeStore(registryStore *registry.Store, optsGetter generic.RESTOptionsGetter, watchCache *WatchCacheConfig) error {
+	options := &generic.StoreOptions{RESTOptions: optsGetter, AttrFunc: getAttrs}
+	if err := registryStore.CompleteWithOptions(options); err != nil {
+		return fmt.Errorf("unable to complete store with options: %w", err)
+	}
+
+	restOptions, err := optsGetter.GetRESTOptions(registryStore.DefaultQualifiedResource, nil)
+	if err != nil {
+		return fmt.Errorf("unable to get REST options: %w", err)
+	}
+	if restOptions.CountMetricPollPeriod > 0 {
+		observeStats(registryStore, restOptions.CountMetricPollPeriod)
+	}
+
+	return enableWatchCache(registryStore, watchCache)
+}
+
+// observeStats publishes the object count and the size estimate of the registry store resource
+// in the apiserver_storage_objects and apiserver_resource_size_estimate_bytes metrics,
+// until the store is destroyed.
+func observeStats(registryStore *registry.Store, period time.Duration) {
+	objectStore := registryStore.Storage.Storage
+	resource := registryStore.DefaultQualifiedResource
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		stats, err := objectStore.Stats(ctx)
+		storagemetrics.UpdateStoreStats(resource, stats, err)
+	}, period, statsPollJitter, true)
+
+	destroyStore := registryStore.DestroyFunc
+	var once sync.Once
+	registryStore.DestroyFunc = func() {
+		once.Do(func() {
+			cancel()
+			storagemetrics.DeleteStoreStats(resource)
+			destroyStore()
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func (suite *storeTestSuite) TestRegistryStoreDeleteCollection() {
+	imageStore := suite.newImageStore()
+	defer imageStore.DestroyFunc()
+
+	ctx := genericapirequest.WithNamespace(genericapirequest.NewContext(), "default")
+	createImages := func(images ...v1alpha1.Image) {
+		for _, image := range images {
+			_, err := imageStore.Create(ctx, &image, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+			suite.Require().NoError(err)
+		}
+	}
+	createImages(
+		newValidImage("test1", "repository"),
+		newValidImage("test2", "repository"),
+		newValidImage("other", "other"),
+	)
+
+	storedImages := func() map[string]*v1alpha1.Image {
+		obj, err := imageStore.List(ctx, &metainternalversion.ListOptions{})
+		suite.Require().NoError(err)
+		list, ok := obj.(*v1alpha1.ImageList)
+		suite.Require().True(ok)
+
+		images := make(map[string]*v1alpha1.Image, len(list.Items))
+		for i := range list.Items {
+			images[list.Items[i].Name] = &list.Items[i]
+		}
+
+		return images
+	}
+
+	listOptions := &metainternalversion.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(v1alpha1.IndexImageMetadataRepository, "repository"),
+	}
+
+	// A dry run is delegated to the registry, and deletes nothing.
+	_, err := imageStore.DeleteCollection(
+		ctx,
+		rest.ValidateAllObjectFunc,
+		&metav1.DeleteOptions{DryRun: []string{metav1.DryRunAll}},
+		listOptions,
+	)
+	suite.Require().NoError(err)
+	suite.Len(storedImages(), 3)
+
+	finalizedImage := newValidImage("finalized", "repository")
+	finalizedImage.Finalizers = []string{"test/finalizer"}
+	createImages(finalizedImage)
+
+	obj, err := imageStore.DeleteCollection(ctx, rest.ValidateAllObjectFunc, &metav1.DeleteOptions{}, listOptions)
+	suite.Require().NoError(err)
+	deleted, ok := obj.(*v1alpha1.ImageList)
+	suite.Require().True(ok)
+	suite.Len(deleted.Items, 3)
+
+	// The image with a finalizer is kept until the finalizer is removed.
+	images := storedImages()
+	suite.Len(images, 2)
+	suite.Contains(images, "other")
+	suite.Require().Contains(images, "finalized")
+	suite.NotNil(images["finalized"].DeletionTimestamp)
+}
@@ -0,0 +1,565 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// spdxBlobsBatchSize is the number of SBOMs whose SPDX document is moved to the blobs in each transaction.
+const spdxBlobsBatchSize = 100
+
+var (
+	spdxEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	spdxDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil)
+	})
+)
+
+var _ objectHooks = spdxBlobsHooks{}
+
+// spdxBlobsHooks store the SPDX documents of the SBOMs in the spdx_blobs table,
+// compressed and shared by the SBOMs with the same document.
+// The blobs are reference counted by a trigger of the sboms table, which deletes them once unreferenced.
+// With a blob store, the compressed documents are uploaded to the blob store before the write transaction,
+// and the table keeps their location.
+type spdxBlobsHooks struct {
+	db    Database
+	blobs BlobStore
+}
+
+func (spdxBlobsHooks) strip(obj runtime.Object) (runtime.Object, error) {
+	sbom, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	stripped := sbom.DeepCopy()
+	stripped.SPDX = runtime.RawExtension{}
+
+	return stripped, nil
+}
+
+// prepare uploads the SPDX document to the blob store, unless a blob has the same content,
+// so that the write transaction only references the uploaded document.
+func (h spdxBlobsHooks) prepare(ctx context.Context, q querier, obj runtime.Object) error {
+	sbom, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	if h.blobs == nil || len(sbom.SPDX.Raw) == 0 {
+		return nil
+	}
+
+	return prepareSPDXBlob(ctx, q, h.blobs, sbom.SPDX.Raw)
+}
+
+func (h spdxBlobsHooks) persist(ctx context.Context, tx transaction, name, namespace string, obj runtime.Object) error {
+	sbom, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	var hash *string
+	if len(sbom.SPDX.Raw) > 0 {
+		sum, err := storeSPDXBlob(ctx, tx, h.db, h.blobs, sbom.SPDX.Raw)
+		if err != nil {
+			return err
+		}
+		hash = &sum
+	}
+
+	if _, err := tx.Exec(
+		ctx,
+		"UPDATE sboms SET spdx_hash = $1 WHERE name = $2 AND namespace = $3",
+		hash, name, namespace,
+	); err != nil {
+		return fmt.Errorf("failed to reference SPDX blob: %w", err)
+	}
+
+	return nil
+}
+
+func (h spdxBlobsHooks) hydrate(ctx context.Context, q querier, objs []runtime.Object) error {
+	if len(objs) == 0 {
+		return nil
+	}
+
+	sboms := make(map[hookKey]*v1alpha1.SBOM, len(objs))
+	names := make([]string, 0, len(objs))
+	namespaces := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		sbom, ok := obj.(*v1alpha1.SBOM)
+		if !ok {
+			return fmt.Errorf("unexpected object type: %T", obj)
+		}
+		sboms[hookKey{name: sbom.Name, namespace: sbom.Namespace}] = sbom
+		names = append(names, sbom.Name)
+		namespaces = append(namespaces, sbom.Namespace)
+	}
+
+	decoder, err := spdxDecoder()
+	if err != nil {
+		return fmt.Errorf("failed to create SPDX decoder: %w", err)
+	}
+
+	// The SBOMs written before the blobs keep their document in the object column, and are not joined.
+	rows, err := q.Query(ctx, `
+SELECT s.name, s.namespace, b.content, b.location
+FROM sboms s
+JOIN spdx_blobs b ON b.hash = s.spdx_hash
+WHERE (s.name, s.namespace) IN (SELECT * FROM unnest($1::TEXT[], $2::TEXT[]))`,
+		names, namespaces,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query SPDX blobs: %w", err)
+	}
+
+	type blob struct {
+		key      hookKey
+		content  []byte
+		location *string
+	}
+	stored, err := collectRows(rows, func(row row) (blob, error) {
+		var b blob
+		err := row.Scan(&b.key.name, &b.key.namespace, &b.content, &b.location)
+		return b, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan SPDX blobs: %w", err)
+	}
+
+	// The SBOMs sharing a document share the decompressed bytes too.
+	documents := make(map[string][]byte)
+	for _, b := range stored {
+		if b.location == nil {
+			spdx, err := decoder.DecodeAll(b.content, nil)
+			if err != nil {
+				return fmt.Errorf("failed to decompress SPDX document of %s/%s: %w", b.key.namespace, b.key.name, err)
+			}
+			sboms[b.key].SPDX = runtime.RawExtension{Raw: spdx}
+			continue
+		}
+
+		spdx, ok := documents[*b.location]
+		if !ok {
+			if h.blobs == nil {
+				return fmt.Errorf("the SPDX document of %s/%s is in the blob store, which is not configured",
+					b.key.namespace, b.key.name)
+			}
+			content, err := h.blobs.Get(ctx, *b.location)
+			if err != nil {
+				return fmt.Errorf("failed to get SPDX document of %s/%s: %w", b.key.namespace, b.key.name, err)
+			}
+			if spdx, err = decoder.DecodeAll(content, nil); err != nil {
+				return fmt.Errorf("failed to decompress SPDX document of %s/%s: %w", b.key.namespace, b.key.name, err)
+			}
+			documents[*b.location] = spdx
+		}
+		sboms[b.key].SPDX = runtime.RawExtension{Raw: spdx}
+	}
+
+	return nil
+}
+
+// storeSPDXBlob stores the compressed SPDX document, if no blob has the same content, and returns its hash.
+// The blob is unreferenced until an SBOM is updated with its hash.
+// With a blob store, the blob references the document uploaded before the transaction.
+// If there is none, such as when the document changed since it was prepared, the document is uploaded
+// in the transaction, and is recorded like the other uploads so that it is deleted if the transaction is rolled back.
+func storeSPDXBlob(ctx context.Context, tx transaction, db querier, blobs BlobStore, spdx []byte) (string, error) {
+	hash := spdxHash(spdx)
+
+	// The content of an existing blob is the same, it is not compressed again.
+	exists, err := spdxBlobExists(ctx, tx, hash)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return hash, nil
+	}
+
+	var content []byte
+	var location *string
+	if blobs == nil {
+		encoder, err := spdxEncoder()
+		if err != nil {
+			return "", fmt.Errorf("failed to create SPDX encoder: %w", err)
+		}
+		content = encoder.EncodeAll(spdx, nil)
+	} else {
+		uploaded, err := claimBlobUpload(ctx, tx, hash)
+		if err != nil {
+			return "", err
+		}
+		if uploaded == "" {
+			if err = prepareSPDXBlob(ctx, db, blobs, spdx); err != nil {
+				return "", err
+			}
+			if uploaded, err = claimBlobUpload(ctx, tx, hash); err != nil {
+				return "", err
+			}
+			if uploaded == "" {
+				return "", fmt.Errorf("the upload of SPDX blob %s expired before it was referenced", hash)
+			}
+		}
+		location = &uploaded
+	}
+
+	inserted, err := tx.Exec(
+		ctx,
+		"INSERT INTO spdx_blobs (hash, content, size, location) VALUES ($1, $2, $3, $4) ON CONFLICT (hash) DO NOTHING",
+		hash, content, len(spdx), location,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert SPDX blob: %w", err)
+	}
+	// A blob with the same content was stored concurrently, the claimed upload is not referenced.
+	if inserted == 0 && location != nil {
+		if _, err = tx.Exec(
+			ctx, "INSERT INTO blob_deletions (location) VALUES ($1) ON CONFLICT DO NOTHING", *location,
+		); err != nil {
+			return "", fmt.Errorf("failed to record the deletion of SPDX blob %s: %w", hash, err)
+		}
+	}
+
+	return hash, nil
+}
+
+// prepareSPDXBlob uploads the compressed SPDX document to the blob store, unless a blob has the same content.
+// The upload is referenced by the next transaction storing a blob with the same content.
+func prepareSPDXBlob(ctx context.Context, q querier, blobs BlobStore, spdx []byte) error {
+	hash := spdxHash(spdx)
+
+	exists, err := spdxBlobExists(ctx, q, hash)
+	if err != nil || exists {
+		return err
+	}
+
+	encoder, err := spdxEncoder()
+	if err != nil {
+		return fmt.Errorf("failed to create SPDX encoder: %w", err)
+	}
+
+	return uploadBlob(ctx, q, blobs, hash, spdxBlobLocation(hash), encoder.EncodeAll(spdx, nil))
+}
+
+// uploadBlob stores the content in the blob store at the location.
+// The upload is recorded in the blob_uploads table first, outside of any transaction,
+// so that the sweeper deletes it if no transaction claims it before the grace period.
+func uploadBlob(ctx context.Context, q querier, blobs BlobStore, hash, location string, content []byte) error {
+	if _, err := q.Exec(ctx, "INSERT INTO blob_uploads (location, hash) VALUES ($1, $2)", location, hash); err != nil {
+		return fmt.Errorf("failed to record the upload of blob %s: %w", hash, err)
+	}
+
+	if err := blobs.Put(ctx, location, content); err != nil {
+		return fmt.Errorf("failed to store blob %s: %w", hash, err)
+	}
+
+	if _, err := q.Exec(ctx, "UPDATE blob_uploads SET uploaded_at = now() WHERE location = $1", location); err != nil {
+		return fmt.Errorf("failed to record the upload of blob %s: %w", hash, err)
+	}
+
+	return nil
+}
+
+// claimBlobUpload removes the latest upload of the content with the given hash from the pending uploads
+// and returns its location, or an empty location if there is none.
+// The upload is then referenced by the transaction, or left to the sweeper if the transaction is rolled back.
+func claimBlobUpload(ctx context.Context, tx transaction, hash string) (string, error) {
+	var location string
+	err := tx.QueryRow(ctx, `
+DELETE FROM blob_uploads
+WHERE location = (
+	SELECT location
+	FROM blob_uploads
+	WHERE hash = $1 AND uploaded_at IS NOT NULL
+	ORDER BY uploaded_at DESC
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING location`,
+		hash,
+	).Scan(&location)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to claim the upload of blob %s: %w", hash, err)
+	}
+
+	return location, nil
+}
+
+// spdxHash returns the hash identifying the SPDX document in the spdx_blobs table.
+// The generator sets a random document namespace and the generation time in every document,
+// so the hash is computed on the document without them: the documents generated for the same image
+// share a blob, and the SBOMs sharing it read the document stored first.
+// A document that cannot be decoded is hashed as is.
+func spdxHash(spdx []byte) string {
+	content := spdx
+	if canonical, err := canonicalSPDX(spdx); err == nil {
+		content = canonical
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalSPDX returns the SPDX document without the fields that differ between two generations
+// of the same document: the document namespace, the creation time and the annotation dates.
+// The keys are sorted, so the result does not depend on the order of the fields either.
+func canonicalSPDX(spdx []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(spdx))
+	decoder.UseNumber()
+
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode SPDX document: %w", err)
+	}
+
+	delete(document, "documentNamespace")
+	if creationInfo, ok := document["creationInfo"].(map[string]any); ok {
+		delete(creationInfo, "created")
+	}
+	dropAnnotationDates(document)
+
+	return json.Marshal(document)
+}
+
+// dropAnnotationDates removes the date of the annotations in the decoded JSON value.
+func dropAnnotationDates(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		delete(v, "annotationDate")
+		for _, item := range v {
+			dropAnnotationDates(item)
+		}
+	case []any:
+		for _, item := range v {
+			dropAnnotationDates(item)
+		}
+	}
+}
+
+// spdxBlobExists reports whether a blob stores the SPDX document with the given hash.
+func spdxBlobExists(ctx context.Context, q querier, hash string) (bool, error) {
+	var exists bool
+	if err := q.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM spdx_blobs WHERE hash = $1)", hash).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to look up SPDX blob: %w", err)
+	}
+
+	return exists, nil
+}
+
+// spdxBlobLocation returns a new location in the blob store for the SPDX document with the given hash.
+// The location is unique, so that a document stored again is not deleted with a previous copy.
+func spdxBlobLocation(hash string) string {
+	return path.Join("spdx", hash, uuid.NewString())
+}
+
+// MoveSPDXDocumentsToBlobs moves the SPDX documents of the SBOMs written before the spdx_blobs table
+// from the object column to the blobs, and returns the number of moved documents.
+// The SBOMs are not changed, so no watch event is recorded and their resourceVersion is kept.
+// It is a no-op on the backends storing the documents in the object column.
+func MoveSPDXDocumentsToBlobs(ctx context.Context, db Database, blobs BlobStore, logger *slog.Logger) (int, error) {
+	if db.Backend() != PostgresBackend {
+		return 0, nil
+	}
+
+	return runBatches(spdxBlobsBatchSize, func() (int, error) {
+		return moveSPDXDocumentsBatch(ctx, db, blobs, logger)
+	})
+}
+
+// OffloadSPDXBlobs moves the SPDX blobs stored in the database to the blob store,
+// and returns the number of moved blobs.
+func OffloadSPDXBlobs(ctx context.Context, db Database, blobs BlobStore, logger *slog.Logger) (int, error) {
+	if db.Backend() != PostgresBackend {
+		return 0, nil
+	}
+
+	return runBatches(spdxBlobsBatchSize, func() (int, error) {
+		return offloadSPDXBlobsBatch(ctx, db, blobs, logger)
+	})
+}
+
+// unmovedSPDXDocumentsSQL selects a batch of the SPDX documents still kept in the object column.
+const unmovedSPDXDocumentsSQL = `
+SELECT name, namespace, object->'spdx'
+FROM sboms
+WHERE spdx_hash IS NULL AND jsonb_typeof(object->'spdx') = 'object'
+ORDER BY namespace, name
+LIMIT $1`
+
+// moveSPDXDocumentsBatch moves the SPDX documents of a batch of SBOMs in a single transaction.
+// With a blob store, the documents are uploaded before the transaction, which holds the writers lock.
+func moveSPDXDocumentsBatch(ctx context.Context, db Database, blobs BlobStore, logger *slog.Logger) (int, error) {
+	if blobs != nil {
+		documents, err := queryUnmovedSPDXDocuments(ctx, db, unmovedSPDXDocumentsSQL)
+		if err != nil {
+			return 0, err
+		}
+		for _, doc := range documents {
+			if err = prepareSPDXBlob(ctx, db, blobs, doc.spdx); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// Serialize with the writers, so that a blob found by storeSPDXBlob is not deleted before it is referenced.
+	if err = db.dialect().lockWrites(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	// The rows written concurrently are skipped, they are moved by the next batch or by their writer.
+	documents, err := queryUnmovedSPDXDocuments(ctx, tx, unmovedSPDXDocumentsSQL+"\nFOR UPDATE SKIP LOCKED")
+	if err != nil {
+		return 0, err
+	}
+
+	for _, doc := range documents {
+		hash, err := storeSPDXBlob(ctx, tx, db, blobs, doc.spdx)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = tx.Exec(
+			ctx,
+			`UPDATE sboms SET object = jsonb_set(object, '{spdx}', 'null'::JSONB), spdx_hash = $1
+WHERE name = $2 AND namespace = $3`,
+			hash, doc.name, doc.namespace,
+		); err != nil {
+			return 0, fmt.Errorf("failed to move SPDX document of %s/%s: %w", doc.namespace, doc.name, err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(documents), nil
+}
+
+// unmovedSPDXDocument is an SPDX document kept in the object column of an SBOM.
+type unmovedSPDXDocument struct {
+	name, namespace string
+	spdx            []byte
+}
+
+// queryUnmovedSPDXDocuments returns the SPDX documents selected by the query, a variant of unmovedSPDXDocumentsSQL.
+func queryUnmovedSPDXDocuments(ctx context.Context, q querier, query string) ([]unmovedSPDXDocument, error) {
+	rows, err := q.Query(ctx, query, spdxBlobsBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SPDX documents: %w", err)
+	}
+
+	documents, err := collectRows(rows, func(row row) (unmovedSPDXDocument, error) {
+		var doc unmovedSPDXDocument
+		err := row.Scan(&doc.name, &doc.namespace, &doc.spdx)
+		return doc, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan SPDX documents: %w", err)
+	}
+
+	return documents, nil
+}
+
+// offloadSPDXBlobsBatch moves a batch of SPDX blobs to the blob store.
+// The blobs are uploaded before the transaction, which only references them.
+// The blobs deleted or offloaded concurrently leave their uploads unreferenced, and the sweeper deletes them.
+func offloadSPDXBlobsBatch(ctx context.Context, db Database, blobs BlobStore, logger *slog.Logger) (int, error) {
+	rows, err := db.Query(ctx, `
+SELECT hash, content
+FROM spdx_blobs
+WHERE content IS NOT NULL
+ORDER BY hash
+LIMIT $1`,
+		spdxBlobsBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query SPDX blobs: %w", err)
+	}
+
+	type blob struct {
+		hash, location string
+		content        []byte
+	}
+	stored, err := collectRows(rows, func(row row) (blob, error) {
+		var b blob
+		err := row.Scan(&b.hash, &b.content)
+		return b, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan SPDX blobs: %w", err)
+	}
+
+	for i, b := range stored {
+		stored[i].location = spdxBlobLocation(b.hash)
+		if err = uploadBlob(ctx, db, blobs, b.hash, stored[i].location, b.content); err != nil {
+			return 0, err
+		}
+	}
+
+	tx, err := db.begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	for _, b := range stored {
+		updated, err := tx.Exec(
+			ctx,
+			"UPDATE spdx_blobs SET content = NULL, location = $1 WHERE hash = $2 AND content IS NOT NULL",
+			b.location, b.hash,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to offload SPDX blob %s: %w", b.hash, err)
+		}
+		if updated == 0 {
+			continue
+		}
+
+		if _, err = tx.Exec(ctx, "DELETE FROM blob_uploads WHERE location = $1", b.location); err != nil {
+			return 0, fmt.Errorf("failed to claim the upload of SPDX blob %s: %w", b.hash, err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(stored), nil
+}
@@ -0,0 +1,461 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func (suite *storeTestSuite) TestSPDXBlobs() {
+	suite.requirePostgres()
+
+	sbomStore := newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		sbomHooks(suite.db, nil),
+		slog.Default(),
+	)
+	defer sbomStore.destroy()
+
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	suite.Require().NoError(err)
+
+	newSBOM := func(name string) *v1alpha1.SBOM {
+		return &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			SPDX: runtime.RawExtension{Raw: spdx},
+		}
+	}
+	getSBOM := func(name string) *v1alpha1.SBOM {
+		sbom := &v1alpha1.SBOM{}
+		err := sbomStore.Get(context.Background(), keyPrefix+"/default/"+name, storage.GetOptions{}, sbom)
+		suite.Require().NoError(err)
+
+		return sbom
+	}
+	refCount := func() int {
+		var count int
+		err := suite.db.QueryRow(context.Background(), "SELECT COALESCE(SUM(ref_count), 0) FROM spdx_blobs").Scan(&count)
+		suite.Require().NoError(err)
+
+		return count
+	}
+
+	sbom1 := newSBOM("test1")
+	err = sbomStore.Create(context.Background(), keyPrefix+"/default/test1", sbom1, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+	sbom2 := newSBOM("test2")
+	err = sbomStore.Create(context.Background(), keyPrefix+"/default/test2", sbom2, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	// The SBOMs share the compressed document, which is not kept in the object column.
+	suite.Equal(1, suite.countRows("spdx_blobs"))
+	suite.Equal(2, refCount())
+
+	var storedSize, compressedSize int
+	err = suite.db.QueryRow(
+		context.Background(),
+		"SELECT size, octet_length(content) FROM spdx_blobs",
+	).Scan(&storedSize, &compressedSize)
+	suite.Require().NoError(err)
+	suite.Equal(len(spdx), storedSize)
+	suite.Less(compressedSize, storedSize)
+
+	var storedDocuments int
+	err = suite.db.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM sboms WHERE jsonb_typeof(object->'spdx') = 'object'",
+	).Scan(&storedDocuments)
+	suite.Require().NoError(err)
+	suite.Equal(0, storedDocuments)
+
+	suite.Equal(sbom1, getSBOM("test1"))
+
+	sbomList := &v1alpha1.SBOMList{}
+	err = sbomStore.GetList(context.Background(), keyPrefix+"/default", storage.ListOptions{Recursive: true}, sbomList)
+	suite.Require().NoError(err)
+	suite.Require().Len(sbomList.Items, 2)
+	suite.Equal(*sbom1, sbomList.Items[0])
+	suite.Equal(*sbom2, sbomList.Items[1])
+
+	// The blob is deleted with the last SBOM referencing it.
+	deleteSBOM := func(name string) {
+		err := sbomStore.Delete(
+			context.Background(),
+			keyPrefix+"/default/"+name,
+			&v1alpha1.SBOM{},
+			&storage.Preconditions{},
+			func(_ context.Context, _ runtime.Object) error { return nil },
+			nil,
+			storage.DeleteOptions{},
+		)
+		suite.Require().NoError(err)
+	}
+	deleteSBOM("test1")
+	suite.Equal(1, suite.countRows("spdx_blobs"))
+	suite.Equal(1, refCount())
+
+	deleteSBOM("test2")
+	suite.Equal(0, suite.countRows("spdx_blobs"))
+}
+
+func (suite *storeTestSuite) TestSPDXBlobsRegeneratedDocument() {
+	suite.requirePostgres()
+
+	sbomStore := newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		sbomHooks(suite.db, nil),
+		slog.Default(),
+	)
+	defer sbomStore.destroy()
+
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	suite.Require().NoError(err)
+	otherSPDX, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-arm64-v8.spdx.json"))
+	suite.Require().NoError(err)
+
+	createSBOM := func(name string, spdx []byte) {
+		sbom := &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			SPDX: runtime.RawExtension{Raw: spdx},
+		}
+		err := sbomStore.Create(context.Background(), keyPrefix+"/default/"+name, sbom, &v1alpha1.SBOM{}, 0)
+		suite.Require().NoError(err)
+	}
+
+	// The document of the same image generated again shares the blob of the first one.
+	createSBOM("test1", spdx)
+	createSBOM("test2", regenerateSPDX(suite.T(), spdx))
+	suite.Equal(1, suite.countRows("spdx_blobs"))
+
+	sbom := &v1alpha1.SBOM{}
+	err = sbomStore.Get(context.Background(), keyPrefix+"/default/test2", storage.GetOptions{}, sbom)
+	suite.Require().NoError(err)
+	suite.Equal(spdx, sbom.SPDX.Raw)
+
+	// The document of another image does not.
+	createSBOM("test3", otherSPDX)
+	suite.Equal(2, suite.countRows("spdx_blobs"))
+}
+
+func TestSPDXHash(t *testing.T) {
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	require.NoError(t, err)
+	otherSPDX, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-arm64-v8.spdx.json"))
+	require.NoError(t, err)
+
+	compacted := &bytes.Buffer{}
+	require.NoError(t, json.Compact(compacted, spdx))
+
+	assert.Equal(t, spdxHash(spdx), spdxHash(regenerateSPDX(t, spdx)))
+	assert.Equal(t, spdxHash(spdx), spdxHash(compacted.Bytes()))
+	assert.NotEqual(t, spdxHash(spdx), spdxHash(otherSPDX))
+	assert.NotEqual(t, spdxHash([]byte("not a document")), spdxHash([]byte("not a document either")))
+}
+
+// regenerateSPDX returns the SPDX document as the generator would produce it again for the same image,
+// with a new document namespace and a new generation time.
+func regenerateSPDX(t *testing.T, spdx []byte) []byte {
+	t.Helper()
+
+	var document struct {
+		DocumentNamespace string `json:"documentNamespace"`
+		CreationInfo      struct {
+			Created string `json:"created"`
+		} `json:"creationInfo"`
+	}
+	require.NoError(t, json.Unmarshal(spdx, &document))
+	require.NotEmpty(t, document.DocumentNamespace)
+	require.NotEmpty(t, document.CreationInfo.Created)
+
+	// The namespace ends with a random UUID.
+	namespace := document.DocumentNamespace[:len(document.DocumentNamespace)-len(uuid.NewString())] + uuid.NewString()
+	created, err := time.Parse(time.RFC3339, document.CreationInfo.Created)
+	require.NoError(t, err)
+
+	regenerated := bytes.ReplaceAll(spdx, []byte(document.DocumentNamespace), []byte(namespace))
+	regenerated = bytes.ReplaceAll(
+		regenerated,
+		[]byte(document.CreationInfo.Created),
+		[]byte(created.Add(24*time.Hour).Format(time.RFC3339)),
+	)
+	require.NotEqual(t, spdx, regenerated)
+
+	return regenerated
+}
+
+func (suite *storeTestSuite) TestMoveSPDXDocumentsToBlobs() {
+	suite.requirePostgres()
+
+	sbomStore := newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		sbomHooks(suite.db, nil),
+		slog.Default(),
+	)
+	defer sbomStore.destroy()
+
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	suite.Require().NoError(err)
+
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		SPDX: runtime.RawExtension{Raw: spdx},
+	}
+	err = sbomStore.Create(context.Background(), keyPrefix+"/default/test", sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	// Keep the document in the object column, like the SBOMs written before the blobs.
+	_, err = suite.db.Exec(
+		context.Background(),
+		"UPDATE sboms SET object = jsonb_set(object, '{spdx}', $1::JSONB), spdx_hash = NULL",
+		spdx,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(0, suite.countRows("spdx_blobs"))
+
+	getSBOM := func() *v1alpha1.SBOM {
+		stored := &v1alpha1.SBOM{}
+		err := sbomStore.Get(context.Background(), keyPrefix+"/default/test", storage.GetOptions{}, stored)
+		suite.Require().NoError(err)
+
+		return stored
+	}
+	suite.JSONEq(string(spdx), string(getSBOM().SPDX.Raw))
+
+	moved, err := MoveSPDXDocumentsToBlobs(context.Background(), suite.db, nil, slog.Default())
+	suite.Require().NoError(err)
+	suite.Equal(1, moved)
+	suite.Equal(1, suite.countRows("spdx_blobs"))
+
+	// The SBOM is unchanged.
+	stored := getSBOM()
+	suite.Equal(sbom.ResourceVersion, stored.ResourceVersion)
+	suite.JSONEq(string(spdx), string(stored.SPDX.Raw))
+
+	moved, err = MoveSPDXDocumentsToBlobs(context.Background(), suite.db, nil, slog.Default())
+	suite.Require().NoError(err)
+	suite.Equal(0, moved)
+}
+
+func (suite *storeTestSuite) TestSPDXBlobStore() {
+	suite.requirePostgres()
+
+	server := newFakeS3Server(suite.T())
+	blobs := server.newBlobStore(suite.T(), "")
+
+	sbomStore := newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		sbomHooks(suite.db, blobs),
+		slog.Default(),
+	)
+	defer sbomStore.destroy()
+
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	suite.Require().NoError(err)
+
+	for _, name := range []string{"test1", "test2"} {
+		sbom := &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			SPDX: runtime.RawExtension{Raw: spdx},
+		}
+		err = sbomStore.Create(context.Background(), keyPrefix+"/default/"+name, sbom, &v1alpha1.SBOM{}, 0)
+		suite.Require().NoError(err)
+	}
+
+	// The SBOMs share a single object in the bucket, and the database keeps its location.
+	suite.Len(server.keys(), 1)
+	suite.Equal(0, suite.countRows("blob_uploads"))
+	var storedContents int
+	err = suite.db.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM spdx_blobs WHERE content IS NOT NULL",
+	).Scan(&storedContents)
+	suite.Require().NoError(err)
+	suite.Equal(0, storedContents)
+
+	sbomList := &v1alpha1.SBOMList{}
+	err = sbomStore.GetList(context.Background(), keyPrefix+"/default", storage.ListOptions{Recursive: true}, sbomList)
+	suite.Require().NoError(err)
+	suite.Require().Len(sbomList.Items, 2)
+	for _, sbom := range sbomList.Items {
+		suite.Equal(spdx, sbom.SPDX.Raw)
+	}
+
+	// The object is deleted from the bucket once no SBOM references it.
+	for _, name := range []string{"test1", "test2"} {
+		err = sbomStore.Delete(
+			context.Background(),
+			keyPrefix+"/default/"+name,
+			&v1alpha1.SBOM{},
+			&storage.Preconditions{},
+			func(_ context.Context, _ runtime.Object) error { return nil },
+			nil,
+			storage.DeleteOptions{},
+		)
+		suite.Require().NoError(err)
+	}
+	suite.Equal(0, suite.countRows("spdx_blobs"))
+	suite.Equal(1, suite.countRows("blob_deletions"))
+	suite.Len(server.keys(), 1)
+
+	deleted, err := NewBlobSweeper(suite.db, blobs, DefaultBlobSweepInterval, slog.Default()).Sweep(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(1, deleted)
+	suite.Equal(0, suite.countRows("blob_deletions"))
+	suite.Empty(server.keys())
+}
+
+func (suite *storeTestSuite) TestSPDXBlobUploadsSweep() {
+	suite.requirePostgres()
+
+	server := newFakeS3Server(suite.T())
+	blobs := server.newBlobStore(suite.T(), "")
+
+	sbomStore := newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		sbomHooks(suite.db, blobs),
+		slog.Default(),
+	)
+	defer sbomStore.destroy()
+
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	suite.Require().NoError(err)
+
+	key := keyPrefix + "/default/test"
+	err = sbomStore.Create(context.Background(), key, &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	// The SPDX document is uploaded before the write, which fails, so the upload is never referenced.
+	err = sbomStore.Create(context.Background(), key, &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		SPDX: runtime.RawExtension{Raw: spdx},
+	}, &v1alpha1.SBOM{}, 0)
+	suite.True(storage.IsExist(err))
+	suite.Equal(0, suite.countRows("spdx_blobs"))
+	suite.Equal(1, suite.countRows("blob_uploads"))
+	suite.Len(server.keys(), 1)
+
+	// The upload is kept during the grace period, as a transaction could still reference it.
+	sweeper := NewBlobSweeper(suite.db, blobs, DefaultBlobSweepInterval, slog.Default())
+	deleted, err := sweeper.Sweep(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(0, deleted)
+	suite.Len(server.keys(), 1)
+
+	_, err = suite.db.Exec(
+		context.Background(),
+		"UPDATE blob_uploads SET started_at = now() - make_interval(secs => $1)",
+		(2 * blobUploadsGracePeriod).Seconds(),
+	)
+	suite.Require().NoError(err)
+
+	deleted, err = sweeper.Sweep(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(1, deleted)
+	suite.Equal(0, suite.countRows("blob_uploads"))
+	suite.Equal(0, suite.countRows("blob_deletions"))
+	suite.Empty(server.keys())
+}
+
+func (suite *storeTestSuite) TestOffloadSPDXBlobs() {
+	suite.requirePostgres()
+
+	server := newFakeS3Server(suite.T())
+	blobs := server.newBlobStore(suite.T(), "")
+
+	newSBOMStore := func(blobs BlobStore) *store {
+		return newStore(
+			suite.db,
+			"sboms",
+			func() runtime.Object { return &v1alpha1.SBOM{} },
+			func() runtime.Object { return &v1alpha1.SBOMList{} },
+			sbomHooks(suite.db, blobs),
+			slog.Default(),
+		)
+	}
+
+	spdx, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "golang-1.12-alpine-amd64.spdx.json"))
+	suite.Require().NoError(err)
+
+	// The SBOM is written before the blob store is configured.
+	databaseStore := newSBOMStore(nil)
+	defer databaseStore.destroy()
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		SPDX: runtime.RawExtension{Raw: spdx},
+	}
+	err = databaseStore.Create(context.Background(), keyPrefix+"/default/test", sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+	suite.Empty(server.keys())
+
+	offloaded, err := OffloadSPDXBlobs(context.Background(), suite.db, blobs, slog.Default())
+	suite.Require().NoError(err)
+	suite.Equal(1, offloaded)
+	suite.Len(server.keys(), 1)
+
+	blobStore := newSBOMStore(blobs)
+	defer blobStore.destroy()
+	stored := &v1alpha1.SBOM{}
+	err = blobStore.Get(context.Background(), keyPrefix+"/default/test", storage.GetOptions{}, stored)
+	suite.Require().NoError(err)
+	suite.Equal(sbom, stored)
+
+	// The offloaded documents cannot be read without the blob store.
+	err = databaseStore.Get(context.Background(), keyPrefix+"/default/test", storage.GetOptions{}, &v1alpha1.SBOM{})
+	suite.Require().ErrorContains(err, "not configured")
+
+	offloaded, err = OffloadSPDXBlobs(context.Background(), suite.db, blobs, slog.Default())
+	suite.Require().NoError(err)
+	suite.Equal(0, offloaded)
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// indexSBOMPackagesSQL indexes the packages of the SPDX document of an SBOM in the sbom_packages table.
+// The document is passed as a parameter, as it is not kept in the object column.
+const indexSBOMPackagesSQL = `
+INSERT INTO sbom_packages (sbom_name, sbom_namespace, package_index, name, version, purl)
+SELECT
+    $1::TEXT,
+    $2::TEXT,
+    (package.ordinality - 1)::INTEGER,
+    package.value->>'name',
+    COALESCE(package.value->>'versionInfo', ''),
+    COALESCE((
+        SELECT ref->>'referenceLocator'
+        FROM jsonb_array_elements(
+            CASE WHEN jsonb_typeof(package.value->'externalRefs') = 'array'
+                THEN package.value->'externalRefs' ELSE '[]'::JSONB END
+        ) AS ref
+        WHERE ref->>'referenceType' = 'purl'
+        LIMIT 1
+    ), '')
+FROM jsonb_array_elements(
+    CASE WHEN jsonb_typeof($3::JSONB->'packages') = 'array'
+        THEN $3::JSONB->'packages' ELSE '[]'::JSONB END
+) WITH ORDINALITY AS package (value, ordinality)
+WHERE package.value->>'name' IS NOT NULL`
+
+var _ objectHooks = sbomPackagesHooks{}
+
+// sbomHooks returns the hooks of the SBOMs stored in the database.
+// The SPDX blobs and the packages index only exist on PostgreSQL,
+// the other backends keep the SPDX document in the object column.
+// The SPDX documents are stored in the blob store, if any.
+func sbomHooks(db Database, blobs BlobStore) objectHooks {
+	if db.Backend() != PostgresBackend {
+		return nil
+	}
+
+	return chainedHooks{spdxBlobsHooks{db: db, blobs: blobs}, sbomPackagesHooks{}}
+}
+
+// sbomPackagesHooks index the packages of the SBOMs in the sbom_packages table.
+// The index is only used to search the packages, the SPDX document is restored by the spdxBlobsHooks.
+type sbomPackagesHooks struct{}
+
+func (sbomPackagesHooks) strip(obj runtime.Object) (runtime.Object, error) {
+	return obj, nil
+}
+
+func (sbomPackagesHooks) prepare(_ context.Context, _ querier, _ runtime.Object) error {
+	return nil
+}
+
+func (sbomPackagesHooks) persist(ctx context.Context, tx transaction, name, namespace string, obj runtime.Object) error {
+	sbom, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	if _, err := tx.Exec(
+		ctx,
+		"DELETE FROM sbom_packages WHERE sbom_name = $1 AND sbom_namespace = $2",
+		name, namespace,
+	); err != nil {
+		return fmt.Errorf("failed to delete SBOM packages: %w", err)
+	}
+
+	if len(sbom.SPDX.Raw) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, indexSBOMPackagesSQL, name, namespace, sbom.SPDX.Raw); err != nil {
+		return fmt.Errorf("failed to index SBOM packages: %w", err)
+	}
+
+	return nil
+}
+
+func (sbomPackagesHooks) hydrate(_ context.Context, _ querier, _ []runtime.Object) error {
+	return nil
+}
@@ -5,36 +5,32 @@ import (
 	"fmt"
 	"log/slog"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/generic/registry"
 )
 
-const CreateSBOMTableSQL = `
-CREATE TABLE IF NOT EXISTS sboms (
-    name VARCHAR(253) NOT NULL,
-    namespace VARCHAR(253) NOT NULL,
-    object JSONB NOT NULL,
-    PRIMARY KEY (name, namespace)
-);
-`
-
 // NewSBOMStore returns a store registry that will work against API services.
+// The SPDX documents of the SBOMs are limited to maxSPDXSize bytes,
+// and are stored in the blob store if it is not nil.
 func NewSBOMStore(
 	scheme *runtime.Scheme,
 	optsGetter generic.RESTOptionsGetter,
-	db *pgxpool.Pool,
+	db Database,
+	blobs BlobStore,
+	watchCache *WatchCacheConfig,
+	maxSPDXSize int,
 	logger *slog.Logger,
-) (*registry.Store, error) {
-	strategy := newSBOMStrategy(scheme)
+) (*RegistryStore, error) {
+	strategy := newSBOMStrategy(scheme, maxSPDXSize)
 
 	newFunc := func() runtime.Object { return &v1alpha1.SBOM{} }
 	newListFunc := func() runtime.Object { return &v1alpha1.SBOMList{} }
 
+	objectStore := newStore(db, "sboms", newFunc, newListFunc, sbomHooks(db, blobs), logger.With("store", "sbom"))
+
 	store := &registry.Store{
 		NewFunc:                   newFunc,
 		NewListFunc:               newListFunc,
@@ -42,27 +38,20 @@ func NewSBOMStore(
 		DefaultQualifiedResource:  v1alpha1.Resource("sboms"),
 		SingularQualifiedResource: v1alpha1.Resource("sbom"),
 		Storage: registry.DryRunnableStorage{
-			Storage: &store{
-				db:          db,
-				broadcaster: watch.NewBroadcaster(1000, watch.WaitIfChannelFull),
-				table:       "sboms",
-				newFunc:     newFunc,
-				newListFunc: newListFunc,
-				logger:      logger.With("store", "sbom"),
-			},
+			Storage: objectStore,
 		},
+		DestroyFunc:    objectStore.destroy,
 		CreateStrategy: strategy,
 		UpdateStrategy: strategy,
 		DeleteStrategy: strategy,
 		TableConvertor: &sbomTableConvertor{},
 	}
 
-	options := &generic.StoreOptions{RESTOptions: optsGetter, AttrFunc: getAttrs}
-	if err := store.CompleteWithOptions(options); err != nil {
-		return nil, fmt.Errorf("unable to complete store with options: %w", err)
+	if err := completeStore(store, optsGetter, watchCache); err != nil {
+		return nil, err
 	}
 
-	return store, nil
+	return &RegistryStore{Store: store, objects: objectStore}, nil
 }
 
 type sbomTableConvertor struct{}
@@ -6,16 +6,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/storage/names"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 )
 
 // newSBOMStrategy creates and returns a sbomStrategy instance
-func newSBOMStrategy(typer runtime.ObjectTyper) sbomStrategy {
-	return sbomStrategy{typer, names.SimpleNameGenerator}
+// accepting SPDX documents up to maxSPDXSize bytes.
+func newSBOMStrategy(typer runtime.ObjectTyper, maxSPDXSize int) sbomStrategy {
+	return sbomStrategy{typer, names.SimpleNameGenerator, maxSPDXSize}
 }
 
 type sbomStrategy struct {
 	runtime.ObjectTyper
 	names.NameGenerator
+
+	maxSPDXSize int
 }
 
 func (sbomStrategy) NamespaceScoped() bool {
@@ -28,13 +33,23 @@ func (sbomStrategy) PrepareForCreate(_ context.Context, _ runtime.Object) {
 func (sbomStrategy) PrepareForUpdate(_ context.Context, _, _ runtime.Object) {
 }
 
-func (sbomStrategy) Validate(_ context.Context, _ runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+func (s sbomStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	sbom, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return unexpectedTypeError(obj)
+	}
+
+	return s.validate(sbom)
 }
 
 // WarningsOnCreate returns warnings for the creation of the given object.
-func (sbomStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
-	return nil
+func (sbomStrategy) WarningsOnCreate(_ context.Context, obj runtime.Object) []string {
+	sbom, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return nil
+	}
+
+	return spdxWarnings(sbom.SPDX, field.NewPath("spdx"))
 }
 
 func (sbomStrategy) AllowCreateOnUpdate() bool {
@@ -48,11 +63,33 @@ func (sbomStrategy) AllowUnconditionalUpdate() bool {
 func (sbomStrategy) Canonicalize(_ runtime.Object) {
 }
 
-func (sbomStrategy) ValidateUpdate(_ context.Context, _, _ runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+func (s sbomStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newSBOM, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return unexpectedTypeError(obj)
+	}
+	oldSBOM, ok := old.(*v1alpha1.SBOM)
+	if !ok {
+		return unexpectedTypeError(old)
+	}
+
+	allErrs := s.validate(newSBOM)
+	allErrs = append(allErrs, validateImageMetadataUpdate(
+		newSBOM.ImageMetadata, oldSBOM.ImageMetadata, field.NewPath("imageMetadata"),
+	)...)
+
+	return allErrs
 }
 
 // WarningsOnUpdate returns warnings for the given update.
 func (sbomStrategy) WarningsOnUpdate(_ context.Context, _, _ runtime.Object) []string {
 	return nil
 }
+
+// validate validates the image metadata and the SPDX document of the SBOM.
+func (s sbomStrategy) validate(sbom *v1alpha1.SBOM) field.ErrorList {
+	allErrs := validateImageMetadata(sbom.ImageMetadata, field.NewPath("imageMetadata"))
+	allErrs = append(allErrs, validateSPDX(sbom.SPDX, s.maxSPDXSize, field.NewPath("spdx"))...)
+
+	return allErrs
+}
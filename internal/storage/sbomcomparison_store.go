@@ -0,0 +1,417 @@
+//nolint:wrapcheck // We want to return the errors from k8s.io/apiserver as they are.
+package storage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	gemversion "github.com/aquasecurity/go-gem-version"
+	pep440 "github.com/aquasecurity/go-pep440-version"
+	apkversion "github.com/knqyf263/go-apk-version"
+	debversion "github.com/knqyf263/go-deb-version"
+	rpmversion "github.com/knqyf263/go-rpm-version"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+var (
+	_ rest.Storage              = &sbomComparisonStore{}
+	_ rest.Scoper               = &sbomComparisonStore{}
+	_ rest.Creater              = &sbomComparisonStore{}
+	_ rest.SingularNameProvider = &sbomComparisonStore{}
+)
+
+// semverPURLTypes are the PURL types of the ecosystems whose versions follow semantic versioning.
+var semverPURLTypes = sets.New("cargo", "composer", "golang", "npm", "nuget")
+
+// sbomComparisonStore serves the create-only SBOMComparison resource.
+// The SBOMs are read from the SBOM store, so that the comparison works on every database backend.
+type sbomComparisonStore struct {
+	sboms      rest.Getter
+	authorizer authorizer.Authorizer
+	logger     *slog.Logger
+}
+
+// NewSBOMComparisonStore returns a create-only store comparing the packages of two SBOMs of the SBOM store.
+// The SBOMs are read from the store directly, so the authorizer checks that the user creating
+// the comparison is allowed to get both SBOMs.
+func NewSBOMComparisonStore(sboms rest.Getter, authz authorizer.Authorizer, logger *slog.Logger) rest.Storage {
+	return &sbomComparisonStore{
+		sboms:      sboms,
+		authorizer: authz,
+		logger:     logger.With("store", "sbomcomparison"),
+	}
+}
+
+// New returns an empty SBOMComparison.
+func (s *sbomComparisonStore) New() runtime.Object {
+	return &v1alpha1.SBOMComparison{}
+}
+
+// Destroy cleans up the resources of the store.
+func (s *sbomComparisonStore) Destroy() {
+	// Nothing to clean up, the SBOMs are read from the SBOM store.
+}
+
+// NamespaceScoped returns true, as the compared SBOMs are in the namespace of the comparison.
+func (s *sbomComparisonStore) NamespaceScoped() bool {
+	return true
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *sbomComparisonStore) GetSingularName() string {
+	return "sbomcomparison"
+}
+
+// Create compares the packages of the SBOMs of the spec and returns the SBOMComparison with the changes in its status.
+// The SBOMComparison is not persisted.
+func (s *sbomComparisonStore) Create(
+	ctx context.Context,
+	obj runtime.Object,
+	createValidation rest.ValidateObjectFunc,
+	_ *metav1.CreateOptions,
+) (runtime.Object, error) {
+	comparison, ok := obj.(*v1alpha1.SBOMComparison)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unexpected object type: %T", obj))
+	}
+
+	namespace, ok := genericapirequest.NamespaceFrom(ctx)
+	if !ok || namespace == "" {
+		return nil, apierrors.NewBadRequest("the namespace of the SBOMComparison is required")
+	}
+
+	s.logger.DebugContext(ctx, "Comparing SBOMs",
+		"namespace", namespace,
+		"base", comparison.Spec.Base,
+		"target", comparison.Spec.Target,
+	)
+
+	if errs := validateSBOMComparison(comparison); len(errs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.Kind("SBOMComparison"), comparison.Name, errs)
+	}
+
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	// Both SBOMs are authorized before being read, so that a forbidden comparison does not reveal whether they exist.
+	for _, name := range []string{comparison.Spec.Base, comparison.Spec.Target} {
+		if err := s.authorizeSBOM(ctx, namespace, name); err != nil {
+			return nil, err
+		}
+	}
+
+	base, basePackages, err := s.getPackages(ctx, comparison.Spec.Base)
+	if err != nil {
+		return nil, err
+	}
+	target, targetPackages, err := s.getPackages(ctx, comparison.Spec.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := comparison.DeepCopy()
+	result.Namespace = namespace
+	result.Status = compareSBOMPackages(basePackages, targetPackages)
+	result.Status.Base = base.ImageMetadata
+	result.Status.Target = target.ImageMetadata
+
+	return result, nil
+}
+
+// validateSBOMComparison validates that the SBOMs to compare are set.
+func validateSBOMComparison(comparison *v1alpha1.SBOMComparison) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if comparison.Spec.Base == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("base"), ""))
+	}
+	if comparison.Spec.Target == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("target"), ""))
+	}
+
+	return allErrs
+}
+
+// authorizeSBOM checks that the user of the request is allowed to get the SBOM.
+func (s *sbomComparisonStore) authorizeSBOM(ctx context.Context, namespace, name string) error {
+	requestUser, ok := genericapirequest.UserFrom(ctx)
+	if !ok {
+		return apierrors.NewForbidden(v1alpha1.Resource("sboms"), name, errors.New("the user of the request is unknown"))
+	}
+
+	decision, reason, err := s.authorizer.Authorize(ctx, authorizer.AttributesRecord{
+		User:            requestUser,
+		Verb:            "get",
+		Namespace:       namespace,
+		APIGroup:        v1alpha1.GroupName,
+		APIVersion:      v1alpha1.SchemeGroupVersion.Version,
+		Resource:        "sboms",
+		Name:            name,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to authorize the SBOM %q: %w", name, err))
+	}
+	if decision != authorizer.DecisionAllow {
+		message := fmt.Sprintf("user %q cannot get the SBOM to compare", requestUser.GetName())
+		if reason != "" {
+			message += ": " + reason
+		}
+		return apierrors.NewForbidden(v1alpha1.Resource("sboms"), name, errors.New(message))
+	}
+
+	return nil
+}
+
+// getPackages returns the SBOM with the given name and the packages of its SPDX document.
+func (s *sbomComparisonStore) getPackages(ctx context.Context, name string) (*v1alpha1.SBOM, []spdxPackage, error) {
+	obj, err := s.sboms.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	sbom, ok := obj.(*v1alpha1.SBOM)
+	if !ok {
+		return nil, nil, apierrors.NewInternalError(fmt.Errorf("unexpected object type: %T", obj))
+	}
+
+	packages, err := parseSPDXPackages(sbom.SPDX.Raw)
+	if err != nil {
+		return nil, nil, apierrors.NewInternalError(fmt.Errorf("failed to parse the SPDX document of SBOM %q: %w", name, err))
+	}
+
+	return sbom, packages, nil
+}
+
+// spdxPackage is a package of an SPDX document, with the fields indexed in the sbom_packages table.
+type spdxPackage struct {
+	name    string
+	version string
+	purl    string
+}
+
+// parseSPDXPackages returns the packages of an SPDX document, skipping the container image itself.
+// The PURL of a package is its first external reference of the purl type.
+func parseSPDXPackages(raw []byte) ([]spdxPackage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var document struct {
+		Packages []struct {
+			Name                  string `json:"name"`
+			VersionInfo           string `json:"versionInfo"`
+			PrimaryPackagePurpose string `json:"primaryPackagePurpose"`
+			ExternalRefs          []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SPDX document: %w", err)
+	}
+
+	packages := make([]spdxPackage, 0, len(document.Packages))
+	for _, p := range document.Packages {
+		if p.Name == "" || p.PrimaryPackagePurpose == "CONTAINER" {
+			continue
+		}
+
+		pkg := spdxPackage{name: p.Name, version: p.VersionInfo}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				pkg.purl = ref.ReferenceLocator
+				break
+			}
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// packageKey identifies a package across SBOMs:
+// its name and its PURL without the version, the qualifiers and the subpath.
+type packageKey struct {
+	name string
+	purl string
+}
+
+func newPackageKey(pkg spdxPackage) packageKey {
+	purl, _, _ := strings.Cut(pkg.purl, "#")
+	purl, _, _ = strings.Cut(purl, "?")
+	if i := strings.LastIndex(purl, "@"); i >= 0 {
+		purl = purl[:i]
+	}
+
+	return packageKey{name: pkg.name, purl: purl}
+}
+
+// compareSBOMPackages returns the packages added, removed, upgraded and downgraded
+// from the base packages to the target ones.
+// A package with a single version missing from each side is upgraded or downgraded,
+// otherwise its versions missing from the other side are removed or added,
+// as are the versions that cannot be compared.
+func compareSBOMPackages(base, target []spdxPackage) v1alpha1.SBOMComparisonStatus {
+	baseVersions := indexPackageVersions(base)
+	targetVersions := indexPackageVersions(target)
+
+	var status v1alpha1.SBOMComparisonStatus
+	for key, targetPackages := range targetVersions {
+		basePackages := baseVersions[key]
+		removed := missingVersions(basePackages, targetPackages)
+		added := missingVersions(targetPackages, basePackages)
+
+		if len(removed) == 1 && len(added) == 1 {
+			change := v1alpha1.PackageChange{
+				Name:            key.name,
+				PURL:            added[0].purl,
+				Version:         added[0].version,
+				PreviousVersion: removed[0].version,
+			}
+			c, ok := compareVersions(newPackageKey(added[0]).purl, added[0].version, removed[0].version)
+			switch {
+			case ok && c > 0:
+				status.Upgraded = append(status.Upgraded, change)
+				continue
+			case ok && c < 0:
+				status.Downgraded = append(status.Downgraded, change)
+				continue
+			}
+		}
+
+		for _, pkg := range added {
+			status.Added = append(status.Added, newAddedPackage(pkg))
+		}
+		for _, pkg := range removed {
+			status.Removed = append(status.Removed, newRemovedPackage(pkg))
+		}
+	}
+	for key, basePackages := range baseVersions {
+		if _, found := targetVersions[key]; found {
+			continue
+		}
+		for _, pkg := range basePackages {
+			status.Removed = append(status.Removed, newRemovedPackage(pkg))
+		}
+	}
+
+	for _, changes := range [][]v1alpha1.PackageChange{status.Added, status.Removed, status.Upgraded, status.Downgraded} {
+		slices.SortFunc(changes, func(a, b v1alpha1.PackageChange) int {
+			return cmp.Or(
+				cmp.Compare(a.Name, b.Name),
+				cmp.Compare(a.PURL, b.PURL),
+				cmp.Compare(a.PreviousVersion, b.PreviousVersion),
+				cmp.Compare(a.Version, b.Version),
+			)
+		})
+	}
+
+	return status
+}
+
+func newAddedPackage(pkg spdxPackage) v1alpha1.PackageChange {
+	return v1alpha1.PackageChange{Name: pkg.name, PURL: pkg.purl, Version: pkg.version}
+}
+
+func newRemovedPackage(pkg spdxPackage) v1alpha1.PackageChange {
+	return v1alpha1.PackageChange{Name: pkg.name, PURL: pkg.purl, PreviousVersion: pkg.version}
+}
+
+// indexPackageVersions returns the first package of each version of each package.
+func indexPackageVersions(packages []spdxPackage) map[packageKey][]spdxPackage {
+	versions := map[packageKey][]spdxPackage{}
+	for _, pkg := range packages {
+		key := newPackageKey(pkg)
+		if !slices.ContainsFunc(versions[key], func(p spdxPackage) bool { return p.version == pkg.version }) {
+			versions[key] = append(versions[key], pkg)
+		}
+	}
+
+	return versions
+}
+
+// missingVersions returns the packages whose version is not found in the other packages.
+func missingVersions(packages, others []spdxPackage) []spdxPackage {
+	var missing []spdxPackage
+	for _, pkg := range packages {
+		if !slices.ContainsFunc(others, func(p spdxPackage) bool { return p.version == pkg.version }) {
+			missing = append(missing, pkg)
+		}
+	}
+
+	return missing
+}
+
+// compareVersions compares two versions of the package with the given PURL, following the ordering of its ecosystem.
+// It returns false if the ordering of the ecosystem is unknown or if a version is invalid,
+// as the versions cannot be compared.
+func compareVersions(purl, a, b string) (int, bool) {
+	purlType, _, _ := strings.Cut(strings.TrimPrefix(purl, "pkg:"), "/")
+	switch {
+	case semverPURLTypes.Has(purlType):
+		return compareParsedVersions(semver.NewVersion, a, b)
+	case purlType == "deb":
+		return compareDebianVersions(a, b)
+	case purlType == "rpm":
+		return rpmversion.NewVersion(a).Compare(rpmversion.NewVersion(b)), true
+	case purlType == "apk":
+		return compareParsedVersions(apkversion.NewVersion, a, b)
+	case purlType == "pypi":
+		return compareParsedVersions(pep440.Parse, a, b)
+	case purlType == "gem":
+		return compareParsedVersions(gemversion.NewVersion, a, b)
+	default:
+		return 0, false
+	}
+}
+
+// compareDebianVersions compares two versions of a Debian package like dpkg does.
+// The library returns the difference between the first parts that differ, which is reduced to its sign
+// like the comparisons of the other ecosystems.
+func compareDebianVersions(a, b string) (int, bool) {
+	aVersion, err := debversion.NewVersion(a)
+	if err != nil {
+		return 0, false
+	}
+	bVersion, err := debversion.NewVersion(b)
+	if err != nil {
+		return 0, false
+	}
+
+	return cmp.Compare(aVersion.Compare(bVersion), 0), true
+}
+
+// compareParsedVersions compares the versions parsed by the version library of an ecosystem.
+func compareParsedVersions[V interface{ Compare(other V) int }](parse func(string) (V, error), a, b string) (int, bool) {
+	aVersion, err := parse(a)
+	if err != nil {
+		return 0, false
+	}
+	bVersion, err := parse(b)
+	if err != nil {
+		return 0, false
+	}
+
+	return aVersion.Compare(bVersion), true
+}
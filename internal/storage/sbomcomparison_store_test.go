@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// newTestSPDX returns an SPDX document of a container image with the packages of the given PURLs.
+func newTestSPDX(purls ...string) []byte {
+	packages := []string{
+		`{"name": "test-image", "primaryPackagePurpose": "CONTAINER", ` +
+			`"externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:oci/test-image@sha256%3Atest"}]}`,
+	}
+	for _, purl := range purls {
+		path, _, _ := strings.Cut(strings.TrimPrefix(purl, "pkg:"), "?")
+		path, version, _ := strings.Cut(path, "@")
+		name := path[strings.LastIndex(path, "/")+1:]
+		packages = append(packages, fmt.Sprintf(
+			`{"name": %q, "versionInfo": %q, "externalRefs": [{"referenceType": "purl", "referenceLocator": %q}]}`,
+			name, version, purl,
+		))
+	}
+
+	return []byte(`{"spdxVersion": "SPDX-2.3", "packages": [` + strings.Join(packages, ",") + `]}`)
+}
+
+func (suite *storeTestSuite) TestSBOMComparisonStore() {
+	scheme := runtime.NewScheme()
+	suite.Require().NoError(v1alpha1.AddToScheme(scheme))
+
+	sbomStore, err := NewSBOMStore(
+		scheme,
+		generic.RESTOptions{ResourcePrefix: keyPrefix},
+		suite.db,
+		nil,
+		nil,
+		0,
+		slog.Default(),
+	)
+	suite.Require().NoError(err)
+	defer sbomStore.DestroyFunc()
+
+	createSBOM := func(name, tag string, spdx []byte) {
+		sbom := &v1alpha1.SBOM{
+			ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: "default"},
+			ImageMetadata: newValidImageMetadata(),
+			SPDX:          runtime.RawExtension{Raw: spdx},
+		}
+		sbom.ImageMetadata.Tag = tag
+		err := sbomStore.objects.Create(context.Background(), keyPrefix+"/default/"+name, sbom, &v1alpha1.SBOM{}, 0)
+		suite.Require().NoError(err)
+	}
+	createSBOM("base", "1.0", newTestSPDX(
+		"pkg:apk/alpine/busybox@1.31.1-r9?arch=x86_64&distro=3.11.3",
+		"pkg:apk/alpine/musl@1.1.24-r2?arch=x86_64&distro=3.11.3",
+		"pkg:apk/alpine/zlib@1.2.11-r3?arch=x86_64&distro=3.11.3",
+		"pkg:golang/golang.org/x/net@v0.30.0",
+		"pkg:golang/golang.org/x/text@v0.3.0",
+		"pkg:golang/golang.org/x/text@v0.4.0",
+	))
+	createSBOM("target", "1.1", newTestSPDX(
+		"pkg:apk/alpine/busybox@1.31.1-r10?arch=x86_64&distro=3.12.0",
+		"pkg:apk/alpine/musl@1.1.24-r2?arch=x86_64&distro=3.12.0",
+		"pkg:apk/alpine/openssl@1.1.1g-r0?arch=x86_64&distro=3.12.0",
+		"pkg:golang/golang.org/x/net@v0.29.0",
+		"pkg:golang/golang.org/x/text@v0.4.0",
+		"pkg:golang/golang.org/x/text@v0.5.0",
+		"pkg:golang/golang.org/x/text@v0.6.0",
+	))
+
+	// The reader user can get every SBOM, the other users only the base SBOM.
+	authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if a.GetVerb() != "get" || a.GetResource() != "sboms" || a.GetAPIGroup() != v1alpha1.GroupName {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+		if a.GetUser().GetName() == "reader" || a.GetName() == "base" {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "not allowed", nil
+	})
+	comparisonStore, ok := NewSBOMComparisonStore(sbomStore, authz, slog.Default()).(*sbomComparisonStore)
+	suite.Require().True(ok)
+
+	compareAs := func(userName, base, target string) (*v1alpha1.SBOMComparison, error) {
+		ctx := genericapirequest.WithNamespace(genericapirequest.NewContext(), "default")
+		if userName != "" {
+			ctx = genericapirequest.WithUser(ctx, &user.DefaultInfo{Name: userName})
+		}
+		result, err := comparisonStore.Create(
+			ctx,
+			&v1alpha1.SBOMComparison{Spec: v1alpha1.SBOMComparisonSpec{Base: base, Target: target}},
+			rest.ValidateAllObjectFunc,
+			&metav1.CreateOptions{},
+		)
+		if err != nil {
+			return nil, err
+		}
+		comparison, ok := result.(*v1alpha1.SBOMComparison)
+		suite.Require().True(ok)
+
+		return comparison, nil
+	}
+	compare := func(base, target string) (*v1alpha1.SBOMComparison, error) {
+		return compareAs("reader", base, target)
+	}
+
+	comparison, err := compare("base", "target")
+	suite.Require().NoError(err)
+	suite.Equal("default", comparison.Namespace)
+	suite.Equal("1.0", comparison.Status.Base.Tag)
+	suite.Equal("1.1", comparison.Status.Target.Tag)
+	suite.Equal([]v1alpha1.PackageChange{
+		{
+			Name:    "openssl",
+			PURL:    "pkg:apk/alpine/openssl@1.1.1g-r0?arch=x86_64&distro=3.12.0",
+			Version: "1.1.1g-r0",
+		},
+		{Name: "text", PURL: "pkg:golang/golang.org/x/text@v0.5.0", Version: "v0.5.0"},
+		{Name: "text", PURL: "pkg:golang/golang.org/x/text@v0.6.0", Version: "v0.6.0"},
+	}, comparison.Status.Added)
+	suite.Equal([]v1alpha1.PackageChange{
+		{Name: "text", PURL: "pkg:golang/golang.org/x/text@v0.3.0", PreviousVersion: "v0.3.0"},
+		{
+			Name:            "zlib",
+			PURL:            "pkg:apk/alpine/zlib@1.2.11-r3?arch=x86_64&distro=3.11.3",
+			PreviousVersion: "1.2.11-r3",
+		},
+	}, comparison.Status.Removed)
+	suite.Equal([]v1alpha1.PackageChange{
+		{
+			Name:            "busybox",
+			PURL:            "pkg:apk/alpine/busybox@1.31.1-r10?arch=x86_64&distro=3.12.0",
+			Version:         "1.31.1-r10",
+			PreviousVersion: "1.31.1-r9",
+		},
+	}, comparison.Status.Upgraded)
+	suite.Equal([]v1alpha1.PackageChange{
+		{Name: "net", PURL: "pkg:golang/golang.org/x/net@v0.29.0", Version: "v0.29.0", PreviousVersion: "v0.30.0"},
+	}, comparison.Status.Downgraded)
+
+	comparison, err = compare("base", "base")
+	suite.Require().NoError(err)
+	suite.Empty(comparison.Status.Added)
+	suite.Empty(comparison.Status.Removed)
+	suite.Empty(comparison.Status.Upgraded)
+	suite.Empty(comparison.Status.Downgraded)
+
+	_, err = compare("base", "missing")
+	suite.True(apierrors.IsNotFound(err), err)
+
+	// The SBOMs are only compared if the user can get both of them, whether they exist or not.
+	_, err = compareAs("other", "base", "target")
+	suite.True(apierrors.IsForbidden(err), err)
+	_, err = compareAs("other", "base", "missing")
+	suite.True(apierrors.IsForbidden(err), err)
+	_, err = compareAs("", "base", "base")
+	suite.True(apierrors.IsForbidden(err), err)
+	comparison, err = compareAs("other", "base", "base")
+	suite.Require().NoError(err)
+	suite.Empty(comparison.Status.Added)
+
+	_, err = compare("", "")
+	suite.Require().True(apierrors.IsInvalid(err), err)
+	var statusErr *apierrors.StatusError
+	suite.Require().ErrorAs(err, &statusErr)
+	fields := []string{}
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	suite.Equal([]string{"spec.base", "spec.target"}, fields)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		purl       string
+		a          string
+		b          string
+		expected   int
+		comparable bool
+	}{
+		{purl: "pkg:apk/alpine/busybox", a: "1.31.1-r10", b: "1.31.1-r9", expected: 1, comparable: true},
+		{purl: "pkg:apk/alpine/busybox", a: "1.31.1_rc1-r0", b: "1.31.1-r0", expected: -1, comparable: true},
+		{purl: "pkg:deb/debian/libc6", a: "2.36-9+deb12u3", b: "2.36-9+deb12u10", expected: -1, comparable: true},
+		{purl: "pkg:deb/debian/libc6", a: "2.36-9", b: "2.36-9+deb12u1", expected: -1, comparable: true},
+		{purl: "pkg:deb/debian/curl", a: "1.0~rc1", b: "1.0", expected: -1, comparable: true},
+		{purl: "pkg:deb/debian/curl", a: "1.0~rc1", b: "1.0~rc2", expected: -1, comparable: true},
+		{purl: "pkg:deb/debian/curl", a: "1.0~~", b: "1.0~", expected: -1, comparable: true},
+		{purl: "pkg:deb/debian/curl", a: "1.0a", b: "1.0+", expected: -1, comparable: true},
+		{purl: "pkg:deb/debian/curl", a: "1:1.0", b: "2.0", expected: 1, comparable: true},
+		{purl: "pkg:deb/debian/curl", a: "0:2.0-1", b: "2.0-01", expected: 0, comparable: true},
+		{purl: "pkg:deb/debian/curl", a: "a1.0", b: "1.0", comparable: false},
+		{purl: "pkg:rpm/redhat/openssl", a: "1:3.0.7-27.el9", b: "1:3.0.7-27.el9", expected: 0, comparable: true},
+		{purl: "pkg:rpm/redhat/openssl", a: "3.0.07", b: "3.0.7", expected: 0, comparable: true},
+		{purl: "pkg:rpm/redhat/openssl", a: "1:1.0", b: "2.0", expected: 1, comparable: true},
+		{purl: "pkg:rpm/redhat/openssl", a: "1.0~rc1", b: "1.0", expected: -1, comparable: true},
+		{purl: "pkg:pypi/requests", a: "2.32.0rc1", b: "2.32.0", expected: -1, comparable: true},
+		{purl: "pkg:gem/rails", a: "7.1.0.beta1", b: "7.1.0", expected: -1, comparable: true},
+		{purl: "pkg:golang/golang.org/x/net", a: "v0.30.0", b: "v0.29.0", expected: 1, comparable: true},
+		{purl: "pkg:npm/lodash", a: "4.17.21", b: "4.17.21-rc.1", expected: 1, comparable: true},
+		{purl: "pkg:npm/lodash", a: "not-semver-10", b: "not-semver-9", comparable: false},
+		{purl: "pkg:maven/org.apache/commons", a: "1.2", b: "1.2.1", comparable: false},
+		{purl: "", a: "1.2", b: "1.2.1", comparable: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.purl+" "+test.a+" "+test.b, func(t *testing.T) {
+			c, ok := compareVersions(test.purl, test.a, test.b)
+			assert.Equal(t, test.comparable, ok)
+			assert.Equal(t, test.expected, c)
+		})
+	}
+}
+
+func TestCompareSBOMPackagesNotComparable(t *testing.T) {
+	base := []spdxPackage{
+		{name: "commons", version: "1.2", purl: "pkg:maven/org.apache/commons@1.2"},
+		{name: "curl", version: "1.0", purl: "pkg:deb/debian/curl@1.0"},
+	}
+	target := []spdxPackage{
+		{name: "commons", version: "1.3", purl: "pkg:maven/org.apache/commons@1.3"},
+		{name: "curl", version: "1:0.9", purl: "pkg:deb/debian/curl@1:0.9"},
+	}
+
+	// The versions of the ecosystems without a known ordering are reported as added and removed.
+	assert.Equal(t, v1alpha1.SBOMComparisonStatus{
+		Added: []v1alpha1.PackageChange{
+			{Name: "commons", PURL: "pkg:maven/org.apache/commons@1.3", Version: "1.3"},
+		},
+		Removed: []v1alpha1.PackageChange{
+			{Name: "commons", PURL: "pkg:maven/org.apache/commons@1.2", PreviousVersion: "1.2"},
+		},
+		Upgraded: []v1alpha1.PackageChange{
+			{Name: "curl", PURL: "pkg:deb/debian/curl@1:0.9", Version: "1:0.9", PreviousVersion: "1.0"},
+		},
+	}, compareSBOMPackages(base, target))
+}
+
+func TestNewPackageKey(t *testing.T) {
+	assert.Equal(t,
+		packageKey{name: "busybox", purl: "pkg:apk/alpine/busybox"},
+		newPackageKey(spdxPackage{name: "busybox", purl: "pkg:apk/alpine/busybox@1.31.1-r9?arch=x86_64&distro=3.11.3"}),
+	)
+	assert.Equal(t,
+		packageKey{name: "core", purl: "pkg:npm/%40angular/core"},
+		newPackageKey(spdxPackage{name: "core", purl: "pkg:npm/%40angular/core@16.0.0#packages/core"}),
+	)
+	assert.Equal(t, packageKey{name: "local"}, newPackageKey(spdxPackage{name: "local"}))
+}
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stephenafamo/bob/dialect/psql"
+	_ "modernc.org/sqlite" // Registers the sqlite driver.
+)
+
+// sqliteOptions are the connection parameters of the SQLite database.
+// The WAL journal lets the readers run concurrently with the writer,
+// and the write transactions take the database lock when they begin, waiting for it up to the busy timeout.
+const sqliteOptions = "_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=foreign_keys(1)&_txlock=immediate"
+
+var _ Database = &sqliteDatabase{}
+
+// sqliteDatabase stores the objects in a SQLite file.
+// Notifications are delivered within the process only, so the file must be used by a single storage replica.
+type sqliteDatabase struct {
+	db *sql.DB
+
+	mu        sync.Mutex
+	listeners map[int64]func(resource string)
+	nextID    int64
+
+	migrationsMu sync.Mutex
+}
+
+// OpenSQLiteDatabase opens the SQLite database stored in the given file, creating it if it does not exist.
+func OpenSQLiteDatabase(path string) (Database, error) {
+	db, err := sql.Open("sqlite", path+"?"+sqliteOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to open SQLite database %s: %w", path, err), db.Close())
+	}
+
+	return &sqliteDatabase{
+		db:        db,
+		listeners: map[int64]func(resource string){},
+	}, nil
+}
+
+func (d *sqliteDatabase) Backend() Backend {
+	return SQLiteBackend
+}
+
+func (d *sqliteDatabase) Close() error {
+	return d.db.Close()
+}
+
+func (d *sqliteDatabase) Ping(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+func (d *sqliteDatabase) Exec(ctx context.Context, sql string, args ...any) (int64, error) {
+	return sqliteExec(ctx, d.db, sql, args)
+}
+
+func (d *sqliteDatabase) Query(ctx context.Context, sql string, args ...any) (rows, error) {
+	return sqliteQuery(ctx, d.db, sql, args)
+}
+
+func (d *sqliteDatabase) QueryRow(ctx context.Context, sql string, args ...any) row {
+	return d.db.QueryRowContext(ctx, sql, sqliteArgs(args)...)
+}
+
+func (d *sqliteDatabase) begin(ctx context.Context) (transaction, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqliteTransaction{tx: tx, db: d}, nil
+}
+
+func (d *sqliteDatabase) beginSnapshot(ctx context.Context) (transaction, error) {
+	// Read-only transactions do not take the database lock,
+	// they read the snapshot of the database taken by their first query.
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqliteTransaction{tx: tx, db: d}, nil
+}
+
+func (d *sqliteDatabase) listen(ctx context.Context, listening func(), notify func(resource string)) error {
+	d.mu.Lock()
+	id := d.nextID
+	d.nextID++
+	d.listeners[id] = notify
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		delete(d.listeners, id)
+		d.mu.Unlock()
+	}()
+
+	listening()
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+// broadcast notifies the listeners of the events committed for the given resources.
+func (d *sqliteDatabase) broadcast(resources []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, resource := range resources {
+		for _, notify := range d.listeners {
+			notify(resource)
+		}
+	}
+}
+
+func (d *sqliteDatabase) withMigrationsLock(_ context.Context, fn func() error) error {
+	d.migrationsMu.Lock()
+	defer d.migrationsMu.Unlock()
+
+	return fn()
+}
+
+func (d *sqliteDatabase) dialect() sqlDialect {
+	return sqliteDialect{}
+}
+
+// sqliteTransaction is a SQLite transaction.
+type sqliteTransaction struct {
+	tx *sql.Tx
+	db *sqliteDatabase
+	// resources are the resources to notify on commit.
+	resources []string
+}
+
+func (t *sqliteTransaction) Exec(ctx context.Context, sql string, args ...any) (int64, error) {
+	return sqliteExec(ctx, t.tx, sql, args)
+}
+
+func (t *sqliteTransaction) Query(ctx context.Context, sql string, args ...any) (rows, error) {
+	return sqliteQuery(ctx, t.tx, sql, args)
+}
+
+func (t *sqliteTransaction) QueryRow(ctx context.Context, sql string, args ...any) row {
+	return t.tx.QueryRowContext(ctx, sql, sqliteArgs(args)...)
+}
+
+func (t *sqliteTransaction) Commit(_ context.Context) error {
+	if err := t.tx.Commit(); err != nil {
+		return err
+	}
+
+	t.db.broadcast(t.resources)
+
+	return nil
+}
+
+func (t *sqliteTransaction) Rollback(_ context.Context) error {
+	if err := t.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return err
+	}
+
+	return nil
+}
+
+func (t *sqliteTransaction) notify(_ context.Context, resource string) error {
+	t.resources = append(t.resources, resource)
+
+	return nil
+}
+
+func (t *sqliteTransaction) copyFrom(ctx context.Context, table string, columns []string, rows [][]any) error {
+	quotedColumns := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = `"` + column + `"`
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	stmt, err := t.tx.PrepareContext(ctx, fmt.Sprintf(
+		`INSERT INTO "%s" (%s) VALUES (%s)`,
+		table, strings.Join(quotedColumns, ", "), strings.Join(placeholders, ", "),
+	))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err = stmt.ExecContext(ctx, sqliteArgs(row)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sqliteExecutor is implemented by both sql.DB and sql.Tx.
+type sqliteExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func sqliteExec(ctx context.Context, e sqliteExecutor, sql string, args []any) (int64, error) {
+	result, err := e.ExecContext(ctx, sql, sqliteArgs(args)...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func sqliteQuery(ctx context.Context, e sqliteExecutor, sql string, args []any) (rows, error) {
+	r, err := e.QueryContext(ctx, sql, sqliteArgs(args)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqliteRows{Rows: r}, nil
+}
+
+// sqliteArgs passes the JSON documents as text, as SQLite reads blobs as its binary JSONB format.
+func sqliteArgs(args []any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		if bytes, ok := arg.([]byte); ok {
+			if bytes == nil {
+				converted[i] = nil
+			} else {
+				converted[i] = string(bytes)
+			}
+			continue
+		}
+		converted[i] = arg
+	}
+
+	return converted
+}
+
+// sqliteRows adapts sql.Rows to the rows interface.
+type sqliteRows struct {
+	*sql.Rows
+}
+
+func (r sqliteRows) Close() {
+	// The errors encountered while iterating are reported by Err.
+	_ = r.Rows.Close()
+}
+
+// sqliteDialect builds SQL using the SQLite JSON functions.
+type sqliteDialect struct{}
+
+func (sqliteDialect) lockWrites(_ context.Context, _ transaction) error {
+	// The write transactions are serialized by the database lock taken when they begin.
+	return nil
+}
+
+func (sqliteDialect) nextResourceVersions(ctx context.Context, tx transaction, count int) (uint64, error) {
+	// The write transactions are serialized by the database lock,
+	// so resourceVersions become visible in order.
+	var lastResourceVersion int64
+	if err := tx.QueryRow(
+		ctx,
+		"UPDATE resource_version_seq SET value = value + $1 RETURNING value",
+		count,
+	).Scan(&lastResourceVersion); err != nil {
+		return 0, fmt.Errorf("failed to allocate resource versions: %w", err)
+	}
+
+	return uint64(lastResourceVersion - int64(count) + 1), nil //nolint:gosec // resource versions are always positive
+}
+
+func (sqliteDialect) jsonText(path ...string) psql.Expression {
+	return psql.Raw("json_extract(object, ?)", sqliteJSONPath(path))
+}
+
+func (d sqliteDialect) labelEquals(key, value string) psql.Expression {
+	return d.jsonText("metadata", "labels", key).EQ(psql.Arg(value))
+}
+
+func (sqliteDialect) labelExists(key string) psql.Expression {
+	return psql.Raw("json_type(object, ?)", sqliteJSONPath([]string{"metadata", "labels", key})).IsNotNull()
+}
+
+func (sqliteDialect) in(expression psql.Expression, values []string) psql.Expression {
+	return expression.In(psql.Arg(sqliteValues(values)...))
+}
+
+func (sqliteDialect) notIn(expression psql.Expression, values []string) psql.Expression {
+	return expression.NotIn(psql.Arg(sqliteValues(values)...))
+}
+
+func (sqliteDialect) integer(expression psql.Expression) psql.Expression {
+	// The sign is trimmed before checking that the rest of the text is made of digits.
+	return psql.Raw(
+		`CASE WHEN ltrim(?, '+-') GLOB '[0-9]*' AND ltrim(?, '+-') NOT GLOB '*[^0-9]*'
+    AND length(?) - length(ltrim(?, '+-')) <= 1 THEN CAST(? AS INTEGER) END`,
+		expression, expression, expression, expression, expression,
+	)
+}
+
+func (sqliteDialect) rowLocks() bool {
+	return false
+}
+
+func (sqliteDialect) ago(duration time.Duration) psql.Expression {
+	return psql.Raw("strftime('%Y-%m-%d %H:%M:%f', 'now', ?)", fmt.Sprintf("-%f seconds", duration.Seconds()))
+}
+
+func (sqliteDialect) objectSize(expression psql.Expression) psql.Expression {
+	return psql.Raw("octet_length(?)", expression)
+}
+
+func (sqliteDialect) migrationsDir() string {
+	return "migrations/sqlite"
+}
+
+func (sqliteDialect) createSchemaMigrationsTableSQL() string {
+	return `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+}
+
+func (sqliteDialect) tableExists(ctx context.Context, q querier, table string) (bool, error) {
+	var exists bool
+	if err := q.QueryRow(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = $1)",
+		table,
+	).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// sqliteJSONPath returns the JSON path of the given keys.
+// The keys are quoted, as label keys can contain dots and slashes.
+func sqliteJSONPath(keys []string) string {
+	var path strings.Builder
+	path.WriteString("$")
+	for _, key := range keys {
+		path.WriteString(`."`)
+		path.WriteString(key)
+		path.WriteString(`"`)
+	}
+
+	return path.String()
+}
+
+func sqliteValues(values []string) []any {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+
+	return args
+}
@@ -3,16 +3,18 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
+	"strconv"
 	"strings"
 
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
 	"github.com/stephenafamo/bob/dialect/psql/dm"
 	"github.com/stephenafamo/bob/dialect/psql/im"
 	"github.com/stephenafamo/bob/dialect/psql/sm"
@@ -25,9 +27,11 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/storage"
+
+	sbombasticv1alpha1 "github.com/kubewarden/sbomscanner/api/v1alpha1"
 )
 
 // objectSchema is the schema of an object in the database.
@@ -36,17 +40,83 @@ type objectSchema struct {
 	Name      string `db:"name"`
 	Namespace string `db:"namespace"`
 	Object    []byte `db:"object"`
+	// ResourceVersion is the resourceVersion of the last write of the object.
+	ResourceVersion int64 `db:"resource_version"`
 }
 
 var _ storage.Interface = &store{}
 
 type store struct {
-	db          *pgxpool.Pool
-	broadcaster *watch.Broadcaster
+	db          Database
+	broadcaster *eventBroadcaster
 	table       string
 	newFunc     func() runtime.Object
 	newListFunc func() runtime.Object
-	logger      *slog.Logger
+	// hooks is nil if the objects are entirely stored in the object column.
+	hooks objectHooks
+	// events is nil if the objects of the watch events are stored as they are served.
+	events eventHooks
+	// projection is nil if the stored objects are served as they are.
+	projection *projection
+	logger     *slog.Logger
+}
+
+// projection derives the served objects from the stored ones with SQL expressions,
+// so that only the projected fields are read from the database.
+// A store with a projection is read-only.
+type projection struct {
+	// object projects the object column of the table.
+	object string
+	// event projects the object column of the watch_events table.
+	event string
+}
+
+// newStore returns a store persisting the objects in the given table.
+func newStore(
+	db Database,
+	table string,
+	newFunc func() runtime.Object,
+	newListFunc func() runtime.Object,
+	hooks objectHooks,
+	logger *slog.Logger,
+) *store {
+	events, _ := hooks.(eventHooks)
+
+	return &store{
+		db:          db,
+		broadcaster: newEventBroadcaster(db, table, "object", newFunc, events, logger),
+		table:       table,
+		newFunc:     newFunc,
+		newListFunc: newListFunc,
+		hooks:       hooks,
+		events:      events,
+		logger:      logger,
+	}
+}
+
+// newProjectionStore returns a read-only store serving a projection of the objects persisted in the given table.
+func newProjectionStore(
+	db Database,
+	table string,
+	projection *projection,
+	newFunc func() runtime.Object,
+	newListFunc func() runtime.Object,
+	logger *slog.Logger,
+) *store {
+	return &store{
+		db:          db,
+		broadcaster: newEventBroadcaster(db, table, psql.Raw(projection.event), newFunc, nil, logger),
+		table:       table,
+		newFunc:     newFunc,
+		newListFunc: newListFunc,
+		projection:  projection,
+		logger:      logger,
+	}
+}
+
+// destroy stops the watchers of the store.
+func (s *store) destroy() {
+	s.broadcaster.shutdown()
 }
 
 // Returns Versioner associated with this interface.
@@ -65,37 +135,79 @@ func (s *store) Create(ctx context.Context, key string, obj, out runtime.Object,
 		return storage.NewInternalError(fmt.Errorf("invalid key: %s", key))
 	}
 
-	if err := s.Versioner().UpdateObject(obj, 1); err != nil {
+	// An object carrying a resourceVersion was read from the storage,
+	// creating it again would overwrite a newer write.
+	if version, err := s.Versioner().ObjectResourceVersion(obj); err == nil && version != 0 {
+		return storage.ErrResourceVersionSetOnCreate
+	}
+
+	// The parts of the object uploaded by the hooks are prepared before taking the writers lock.
+	if err := s.prepare(ctx, obj); err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	tx, err := s.db.begin(ctx)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	resourceVersion, err := s.db.dialect().nextResourceVersions(ctx, tx, 1)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	if err = s.Versioner().UpdateObject(obj, resourceVersion); err != nil {
 		return storage.NewInternalError(err)
 	}
 
-	bytes, err := json.Marshal(obj)
+	storedBytes, err := s.marshalStored(obj)
 	if err != nil {
 		return storage.NewInternalError(err)
 	}
 
 	query, args, err := psql.Insert(
-		im.Into(psql.Quote(s.table)),
-		im.Values(psql.Arg(name), psql.Arg(namespace), psql.Arg(bytes)),
+		im.Into(psql.Quote(s.table), "name", "namespace", "object", "resource_version"),
+		im.Values(psql.Arg(name), psql.Arg(namespace), psql.Arg(storedBytes), psql.Arg(resourceVersion)),
 		im.OnConflict().DoNothing(),
 	).Build(ctx)
 	if err != nil {
 		return storage.NewInternalError(err)
 	}
 
-	result, err := s.db.Exec(ctx, query, args...)
+	inserted, err := tx.Exec(ctx, query, args...)
 	if err != nil {
 		return storage.NewInternalError(err)
 	}
 
-	if result.RowsAffected() == 0 {
+	if inserted == 0 {
 		return storage.NewKeyExistsError(key, 0)
 	}
 
-	if err = s.broadcaster.Action(watch.Added, obj); err != nil {
+	if err = s.persist(ctx, tx, name, namespace, obj); err != nil {
 		return storage.NewInternalError(err)
 	}
 
+	// The event is marshaled after the hooks completed the object.
+	bytes, err := s.marshalEvent(obj)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	if err = recordEvent(ctx, tx, s.table, watch.Added, resourceVersion, name, namespace, bytes); err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	s.broadcaster.notify()
+
 	if out != nil {
 		if err = setValue(obj, out); err != nil {
 			return err
@@ -121,31 +233,24 @@ func (s *store) Delete(
 		return storage.NewInternalError(fmt.Errorf("invalid key: %s", key))
 	}
 
-	tx, err := s.db.Begin(ctx)
+	tx, err := s.db.begin(ctx)
 	if err != nil {
 		return storage.NewInternalError(err)
 	}
 	defer func() {
-		if err = tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		if err = tx.Rollback(ctx); err != nil {
 			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
 		}
 	}()
 
-	query, args, err := psql.Delete(
-		dm.From(psql.Quote(s.table)),
-		dm.Where(psql.Quote("name").EQ(psql.Arg(name))),
-		dm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
-		dm.Returning("name", "namespace", "object"),
-	).Build(ctx)
+	resourceVersion, err := s.db.dialect().nextResourceVersions(ctx, tx, 1)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
 
-	var objectRecord objectSchema
-	err = tx.QueryRow(ctx, query, args...).Scan(
-		&objectRecord.Name,
-		&objectRecord.Namespace,
-		&objectRecord.Object,
-	)
+	objectRecord, err := s.readRecord(ctx, tx, name, namespace, true)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, sql.ErrNoRows) {
 			return storage.NewKeyNotFoundError(key, 0)
 		}
 		return storage.NewInternalError(err)
@@ -155,6 +260,10 @@ func (s *store) Delete(
 		return storage.NewInternalError(err)
 	}
 
+	if err = s.hydrate(ctx, tx, out); err != nil {
+		return storage.NewInternalError(err)
+	}
+
 	if err = preconditions.Check(key, out); err != nil {
 		return err
 	}
@@ -163,14 +272,47 @@ func (s *store) Delete(
 		return err
 	}
 
-	if err = tx.Commit(ctx); err != nil {
+	deleteQuery, deleteArgs, err := psql.Delete(
+		dm.From(psql.Quote(s.table)),
+		dm.Where(psql.Quote("name").EQ(psql.Arg(name))),
+		dm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
+		dm.Where(psql.Quote("resource_version").EQ(psql.Arg(objectRecord.ResourceVersion))),
+	).Build(ctx)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	deleted, err := tx.Exec(ctx, deleteQuery, deleteArgs...)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	if deleted == 0 {
+		return storage.NewResourceVersionConflictsError(key, objectRecord.ResourceVersion)
+	}
+
+	// The object sent in the DELETED event carries the resourceVersion of the deletion,
+	// so that watchers can resume after it.
+	deletedObj := out.DeepCopyObject()
+	if err = s.Versioner().UpdateObject(deletedObj, resourceVersion); err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	bytes, err := s.marshalEvent(deletedObj)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	if err = recordEvent(ctx, tx, s.table, watch.Deleted, resourceVersion, name, namespace, bytes); err != nil {
 		return storage.NewInternalError(err)
 	}
 
-	if err = s.broadcaster.Action(watch.Deleted, out); err != nil {
+	if err = tx.Commit(ctx); err != nil {
 		return storage.NewInternalError(err)
 	}
 
+	s.broadcaster.notify()
+
 	return nil
 }
 
@@ -181,6 +323,9 @@ func (s *store) Delete(
 // (e.g. reconnecting without missing any updates).
 // If resource version is "0", this interface will get current object at given key
 // and send it in an "ADDED" event, before watch starts.
+//
+// Watches starting from a resourceVersion are served by replaying the watch_events table.
+// If the events following the resourceVersion have been compacted, a ResourceExpired error is returned.
 func (s *store) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
 	s.logger.DebugContext(
 		ctx,
@@ -193,35 +338,99 @@ func (s *store) Watch(ctx context.Context, key string, opts storage.ListOptions)
 		opts.ProgressNotify,
 	)
 
-	if opts.ResourceVersion == "" {
-		return s.broadcaster.Watch()
+	resourceVersion, err := s.Versioner().ParseResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return nil, err
 	}
 
-	if opts.ResourceVersion == "0" {
-		obj := s.newFunc()
-		if err := s.Get(ctx, key, storage.GetOptions{}, obj); err != nil {
+	name, namespace := extractNameAndNamespace(key)
+	if name == "" {
+		namespace = extractNamespace(key)
+	}
+
+	if opts.Predicate.Label == nil {
+		opts.Predicate.Label = labels.Everything()
+	}
+	if opts.Predicate.Field == nil {
+		opts.Predicate.Field = fields.Everything()
+	}
+
+	sendInitialEvents := opts.SendInitialEvents != nil && *opts.SendInitialEvents
+
+	var initialEvents []watch.Event
+	switch {
+	case sendInitialEvents || (resourceVersion == 0 && opts.SendInitialEvents == nil):
+		// Send the current state of the objects, then the changes that happened after it.
+		// The state is read from a snapshot at least as recent as the requested resourceVersion.
+		initialEvents, resourceVersion, err = s.initialEvents(ctx, key, opts)
+		if err != nil {
+			return nil, err
+		}
+		if sendInitialEvents && opts.Predicate.AllowWatchBookmarks {
+			// Streaming lists complete when the bookmark marking the end of the initial events is received.
+			var bookmark watch.Event
+			bookmark, err = s.initialEventsEndBookmark(resourceVersion)
+			if err != nil {
+				return nil, err
+			}
+			initialEvents = append(initialEvents, bookmark)
+		}
+	case resourceVersion == 0:
+		resourceVersion, err = s.GetCurrentResourceVersion(ctx)
+		if err != nil {
 			return nil, err
 		}
+	default:
+		var compacted uint64
+		compacted, err = compactedResourceVersion(ctx, s.db)
+		if err != nil {
+			return nil, storage.NewInternalError(err)
+		}
+		if resourceVersion < compacted {
+			return nil, apierrors.NewResourceExpired(
+				fmt.Sprintf("too old resource version: %d (%d)", resourceVersion, compacted))
+		}
+	}
 
-		return s.broadcaster.WatchWithPrefix([]watch.Event{{Type: watch.Added, Object: obj}})
+	return s.broadcaster.watch(
+		ctx,
+		resourceVersion,
+		initialEvents,
+		namespace,
+		name,
+		opts.Predicate,
+		opts.Predicate.AllowWatchBookmarks || opts.ProgressNotify,
+	)
+}
+
+// initialEvents returns an "ADDED" event for every object matching the options,
+// together with the resourceVersion of the snapshot they were read from.
+func (s *store) initialEvents(ctx context.Context, key string, opts storage.ListOptions) ([]watch.Event, uint64, error) {
+	listOpts := storage.ListOptions{
+		ResourceVersion: opts.ResourceVersion,
+		Recursive:       opts.Recursive,
+		Predicate:       opts.Predicate,
 	}
+	listOpts.Predicate.Limit = 0
+	listOpts.Predicate.Continue = ""
 
 	listObj := s.newListFunc()
-	if err := s.GetList(ctx, key, opts, listObj); err != nil {
-		return nil, err
+	resourceVersion, err := s.list(ctx, key, listOpts, listObj)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	itemsValue, err := getItems(listObj)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	var events []watch.Event
+	events := make([]watch.Event, 0, itemsValue.Len())
 	for i := range itemsValue.Len() {
 		// Cast the item address to a runtime.Object
 		item, ok := itemsValue.Index(i).Addr().Interface().(runtime.Object)
 		if !ok {
-			return nil, storage.NewInternalError(
+			return nil, 0, storage.NewInternalError(
 				fmt.Errorf("unexpected item type: %T", itemsValue.Index(i).Addr().Interface()),
 			)
 		}
@@ -232,7 +441,21 @@ func (s *store) Watch(ctx context.Context, key string, opts storage.ListOptions)
 		})
 	}
 
-	return s.broadcaster.WatchWithPrefix(events)
+	return events, resourceVersion, nil
+}
+
+// initialEventsEndBookmark returns the bookmark event marking the end of the initial events,
+// read from the snapshot at the given resourceVersion.
+func (s *store) initialEventsEndBookmark(resourceVersion uint64) (watch.Event, error) {
+	obj := s.newFunc()
+	if err := s.Versioner().UpdateObject(obj, resourceVersion); err != nil {
+		return watch.Event{}, storage.NewInternalError(err)
+	}
+	if err := storage.AnnotateInitialEventsEndBookmark(obj); err != nil {
+		return watch.Event{}, storage.NewInternalError(err)
+	}
+
+	return watch.Event{Type: watch.Bookmark, Object: obj}, nil
 }
 
 // Get unmarshals object found at key into objPtr. On a not found error, will either
@@ -262,7 +485,7 @@ func (s *store) Get(ctx context.Context, key string, opts storage.GetOptions, ob
 	}
 
 	query, args, err := psql.Select(
-		sm.Columns("name", "namespace", "object"),
+		sm.Columns("name", "namespace", s.objectColumn()),
 		sm.From(psql.Quote(s.table)),
 		sm.Where(psql.Quote("name").EQ(psql.Arg(name))),
 		sm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
@@ -271,14 +494,25 @@ func (s *store) Get(ctx context.Context, key string, opts storage.GetOptions, ob
 		return storage.NewInternalError(err)
 	}
 
+	// Read the object and the parts kept outside of the object column from the same snapshot.
+	tx, err := s.db.beginSnapshot(ctx)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
 	var objectRecord objectSchema
-	err = s.db.QueryRow(ctx, query, args...).Scan(
+	err = tx.QueryRow(ctx, query, args...).Scan(
 		&objectRecord.Name,
 		&objectRecord.Namespace,
 		&objectRecord.Object,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, sql.ErrNoRows) {
 			if opts.IgnoreNotFound {
 				return nil
 			}
@@ -292,6 +526,10 @@ func (s *store) Get(ctx context.Context, key string, opts storage.GetOptions, ob
 		return storage.NewInternalError(err)
 	}
 
+	if err = s.hydrate(ctx, tx, objPtr); err != nil {
+		return storage.NewInternalError(err)
+	}
+
 	return nil
 }
 
@@ -299,8 +537,6 @@ func (s *store) Get(ctx context.Context, key string, opts storage.GetOptions, ob
 // that satisfies runtime.IsList definition).
 // The returned contents may be delayed, but it is guaranteed that they will
 // match 'opts.ResourceVersion' according 'opts.ResourceVersionMatch'.
-//
-//nolint:gocognit // This function can't be easily split into smaller parts.
 func (s *store) GetList(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
 	s.logger.DebugContext(ctx, "Getting list",
 		"key", key,
@@ -311,55 +547,147 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 		"continue", opts.Predicate.Continue,
 	)
 
+	if _, err := s.list(ctx, key, opts, listObj); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// list reads the objects found at key into listObj, setting the resourceVersion and the continue token
+// of the list, and returns the resourceVersion of the snapshot the objects were read from.
+//
+// Lists are paginated with a keyset on (namespace, name).
+// The continue token carries the resourceVersion of the first page, which is returned for all the
+// following pages, so that a watch started from it receives every change that happened while paging.
+// The following pages are read from the current state of the table: once the events following the
+// token resourceVersion are compacted, the token is rejected with a ResourceExpired error carrying
+// a new token to continue the list inconsistently, as etcd does.
+//
+//nolint:gocognit,funlen // This function can't be easily split into smaller parts.
+func (s *store) list(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) (uint64, error) {
+	if opts.Predicate.Label == nil {
+		opts.Predicate.Label = labels.Everything()
+	}
+	if opts.Predicate.Field == nil {
+		opts.Predicate.Field = fields.Everything()
+	}
+
+	keyPrefix := key
+	if !strings.HasSuffix(keyPrefix, "/") {
+		keyPrefix += "/"
+	}
+
+	continueResourceVersion, continueKey, err := storage.ValidateListOptions(keyPrefix, s.Versioner(), opts)
+	if err != nil {
+		return 0, err
+	}
+
+	name, namespace := extractNameAndNamespace(key)
+	if name == "" {
+		namespace = extractNamespace(key)
+	}
+
+	selectorExpressions, err := buildSelectorExpressions(s.db.dialect(), name, namespace, opts.Predicate)
+	if err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	filters := make([]bob.Mod[*dialect.SelectQuery], 0, len(selectorExpressions)+1)
+	for _, expression := range selectorExpressions {
+		filters = append(filters, sm.Where(expression))
+	}
+
+	if continueKey != "" {
+		continueName, continueNamespace := extractNameAndNamespace(continueKey)
+		if continueName == "" {
+			return 0, apierrors.NewBadRequest(fmt.Sprintf("invalid continue token: unexpected key %q", continueKey))
+		}
+		// The continue key points right after the last returned object.
+		continueName = strings.TrimSuffix(continueName, "\x00")
+		filters = append(filters, sm.Where(
+			psql.Group(psql.Quote("namespace"), psql.Quote("name")).
+				GT(psql.ArgGroup(continueNamespace, continueName)),
+		))
+	}
+
 	queryBuilder := psql.Select(
 		sm.From(psql.Quote(s.table)),
-		sm.Columns("name", "namespace", "object"),
+		sm.Columns("name", "namespace", s.objectColumn()),
+		sm.OrderBy("namespace"),
+		sm.OrderBy("name"),
 	)
+	queryBuilder.Apply(filters...)
 
-	namespace := extractNamespace(key)
-	if namespace != "" {
-		queryBuilder.Apply(
-			sm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
-		)
+	paging := opts.Predicate.Limit > 0
+	if paging {
+		// Fetch one more object to know whether there are more results.
+		queryBuilder.Apply(sm.Limit(opts.Predicate.Limit + 1))
 	}
 
-	if opts.Predicate.Label != nil {
-		labelSelectorExpressions, err := buildLabelSelectorExpressions(opts.Predicate.Label)
-		if err != nil {
-			return storage.NewInternalError(err)
-		}
-		for _, expression := range labelSelectorExpressions {
-			queryBuilder.Apply(sm.Where(expression))
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	minimumResourceVersion, err := s.Versioner().ParseResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	// Read the objects and the current resourceVersion from the same snapshot,
+	// so that a watch started from the returned resourceVersion does not miss any event.
+	tx, err := s.db.beginSnapshot(ctx)
+	if err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
 		}
+	}()
+
+	resourceVersion, err := currentResourceVersion(ctx, tx)
+	if err != nil {
+		return 0, storage.NewInternalError(err)
 	}
 
-	if opts.Predicate.Field != nil {
-		fieldSelectorExpressions, err := buildFieldSelectorExpressions(opts.Predicate.Field)
+	if minimumResourceVersion > resourceVersion {
+		return 0, storage.NewTooLargeResourceVersionError(minimumResourceVersion, resourceVersion, 1)
+	}
+
+	if continueResourceVersion > 0 {
+		var compacted uint64
+		compacted, err = compactedResourceVersion(ctx, tx)
 		if err != nil {
-			return storage.NewInternalError(err)
+			return 0, storage.NewInternalError(err)
 		}
-		for _, expression := range fieldSelectorExpressions {
-			queryBuilder.Apply(sm.Where(expression))
+		if uint64(continueResourceVersion) < compacted {
+			return 0, newInconsistentContinueError(continueKey, keyPrefix)
 		}
+		resourceVersion = uint64(continueResourceVersion)
 	}
 
-	query, args, err := queryBuilder.Build(ctx)
-	if err != nil {
-		return storage.NewInternalError(err)
-	}
-
-	rows, err := s.db.Query(ctx, query, args...)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
-		return storage.NewInternalError(err)
+		return 0, storage.NewInternalError(err)
 	}
 	defer rows.Close()
 
 	itemsValue, err := getItems(listObj)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	var objs []runtime.Object
+	var hasMore bool
+	var lastKey string
 	for rows.Next() {
+		if paging && int64(len(objs)) >= opts.Predicate.Limit {
+			hasMore = true
+			break
+		}
+
 		var objectRecord objectSchema
 		err = rows.Scan(
 			&objectRecord.Name,
@@ -367,28 +695,110 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 			&objectRecord.Object,
 		)
 		if err != nil {
-			return storage.NewInternalError(err)
+			return 0, storage.NewInternalError(err)
 		}
 
 		obj := s.newFunc()
 		if err = json.Unmarshal(objectRecord.Object, obj); err != nil {
-			return storage.NewInternalError(err)
+			return 0, storage.NewInternalError(err)
 		}
 
+		objs = append(objs, obj)
+		lastKey = objectKey(keyPrefix, namespace, objectRecord.Namespace, objectRecord.Name)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	if err = s.hydrate(ctx, tx, objs...); err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	for _, obj := range objs {
 		// Append the object to the items slice
 		itemsValue.Set(reflect.Append(itemsValue, reflect.ValueOf(obj).Elem()))
 	}
 
-	if err = rows.Err(); err != nil {
-		return storage.NewInternalError(err)
+	var itemsCount int64
+	if hasMore && opts.Predicate.Empty() {
+		// The remaining item count is only set for unfiltered lists, as etcd does.
+		itemsCount, err = s.count(ctx, tx, filters)
+		if err != nil {
+			return 0, err
+		}
 	}
 
-	// TODO: Implement pagination and use a proper resourceVersion
-	if err = s.Versioner().UpdateList(listObj, 1, "", nil); err != nil {
+	continueValue, remainingItemCount, err := storage.PrepareContinueToken(
+		lastKey,
+		keyPrefix,
+		int64(resourceVersion), //nolint:gosec // resource versions are stored as BIGINT
+		itemsCount,
+		hasMore,
+		opts,
+	)
+	if err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	if err = s.Versioner().UpdateList(listObj, resourceVersion, continueValue, remainingItemCount); err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	return resourceVersion, nil
+}
+
+// count returns the number of objects matching the filters.
+func (s *store) count(ctx context.Context, q querier, filters []bob.Mod[*dialect.SelectQuery]) (int64, error) {
+	queryBuilder := psql.Select(
+		sm.Columns("COUNT(*)"),
+		sm.From(psql.Quote(s.table)),
+	)
+	queryBuilder.Apply(filters...)
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	var count int64
+	if err = q.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	return count, nil
+}
+
+// objectKey returns the key of an object relative to the given list key prefix.
+// The namespace is part of the prefix when listing a single namespace.
+func objectKey(keyPrefix, listNamespace, namespace, name string) string {
+	if listNamespace != "" {
+		return keyPrefix + name
+	}
+
+	return keyPrefix + namespace + "/" + name
+}
+
+// newInconsistentContinueError returns the error sent when the continue token is too old,
+// with a new token to continue the list from the current resourceVersion.
+func newInconsistentContinueError(continueKey, keyPrefix string) error {
+	// A resourceVersion of -1 means that the list continues at the latest resourceVersion.
+	continueValue, err := storage.EncodeContinue(continueKey, keyPrefix, -1)
+	if err != nil {
 		return storage.NewInternalError(err)
 	}
 
-	return nil
+	statusErr := apierrors.NewResourceExpired(
+		"The provided continue parameter is too old to display a consistent list result. " +
+			"You can start a new list without the continue parameter, or use the continue token in this " +
+			"response to retrieve the remainder of the results. Continuing with the provided token results " +
+			"in an inconsistent list - objects that were created, modified, or deleted between the time " +
+			"the first chunk was returned and now may show up in the list.",
+	)
+	statusErr.ErrStatus.ListMeta.Continue = continueValue
+
+	return statusErr
 }
 
 // GuaranteedUpdate keeps calling 'tryUpdate()' to update key 'key' (of type 'destination')
@@ -405,6 +815,10 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 // current version of the object to avoid read operation from storage to get it.
 // However, the implementations have to retry in case suggestion is stale.
 //
+// The row is read without a lock and written with a compare and swap on its resourceVersion,
+// so that tryUpdate and the uploads of the hooks run without holding the writers lock.
+// A concurrent write makes the compare and swap fail, and the update is retried on the current object.
+//
 // Example:
 //
 // s := /* implementation of Interface */
@@ -426,7 +840,7 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 //
 // )
 //
-//nolint:gocognit,funlen // This functions can't be easily split into smaller parts.
+//nolint:funlen // This functions can't be easily split into smaller parts.
 func (s *store) GuaranteedUpdate(
 	ctx context.Context,
 	key string,
@@ -443,119 +857,263 @@ func (s *store) GuaranteedUpdate(
 		return storage.NewInternalError(fmt.Errorf("invalid key: %s", key))
 	}
 
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		return err
+	if err := runtime.SetZeroValue(destination); err != nil {
+		return storage.NewInternalError(fmt.Errorf("unable to set destination to zero value: %w", err))
 	}
 
-	defer func() {
-		if err = tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
-		}
-	}()
-
 	for {
-		query, args, err := psql.Select(
-			sm.Columns("name", "namespace", "object"),
-			sm.From(psql.Quote(s.table)),
-			sm.Where(psql.Quote("name").EQ(psql.Arg(name))),
-			sm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
-		).Build(ctx)
-		if err != nil {
-			return storage.NewInternalError(err)
-		}
-
-		if err = runtime.SetZeroValue(destination); err != nil {
-			return storage.NewInternalError(fmt.Errorf("unable to set destination to zero value: %w", err))
-		}
-
-		var objectRecord objectSchema
-		err = tx.QueryRow(ctx, query, args...).Scan(
-			&objectRecord.Name,
-			&objectRecord.Namespace,
-			&objectRecord.Object,
-		)
+		objectRecord, err := s.readRecord(ctx, s.db, name, namespace, false)
 		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
+			if errors.Is(err, sql.ErrNoRows) {
 				if !ignoreNotFound {
 					return storage.NewKeyNotFoundError(key, 0)
 				}
 				return nil
 			}
-			return err
+			return storage.NewInternalError(err)
 		}
 
 		obj := s.newFunc()
-		err = json.Unmarshal(objectRecord.Object, obj)
-		if err != nil {
+		if err = json.Unmarshal(objectRecord.Object, obj); err != nil {
 			return storage.NewInternalError(err)
 		}
 
-		err = preconditions.Check(key, obj)
-		if err != nil {
-			return err
+		if err = s.hydrate(ctx, s.db, obj); err != nil {
+			return storage.NewInternalError(err)
 		}
 
-		var updatedObj runtime.Object
-		updatedObj, _, err = tryUpdate(obj, storage.ResponseMeta{})
-		if err != nil {
-			if apierrors.IsConflict(err) && strings.Contains(err.Error(), registry.OptimisticLockErrorMsg) {
-				s.logger.DebugContext(ctx, "Optimistic lock conflict", "key", key, "error", err)
-
-				// retry update on optimistic lock conflict
-				continue
-			}
+		if err = preconditions.Check(key, obj); err != nil {
 			return err
 		}
 
-		var version uint64
-		version, err = s.Versioner().ObjectResourceVersion(obj)
+		version, err := s.Versioner().ObjectResourceVersion(obj)
 		if err != nil {
 			return storage.NewInternalError(err)
 		}
-		if err = s.Versioner().UpdateObject(updatedObj, version+1); err != nil {
-			return storage.NewInternalError(err)
-		}
 
-		var bytes []byte
-		bytes, err = json.Marshal(updatedObj)
+		currentBytes, err := json.Marshal(obj)
 		if err != nil {
 			return storage.NewInternalError(err)
 		}
 
-		updateQuery, updateArgs, err := psql.Update(
-			um.Table(psql.Quote(s.table)),
-			um.SetCol("object").To(psql.Arg(bytes)),
-			um.Where(psql.Quote("name").EQ(psql.Arg(name))),
-			um.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
-		).Build(ctx)
+		// The object passed to tryUpdate is the current one, so an optimistic lock conflict
+		// reported by tryUpdate cannot be solved by retrying and is returned to the caller.
+		updatedObj, _, err := tryUpdate(obj, storage.ResponseMeta{ResourceVersion: version})
 		if err != nil {
+			return err
+		}
+
+		if err = s.Versioner().UpdateObject(updatedObj, version); err != nil {
 			return storage.NewInternalError(err)
 		}
 
-		_, err = tx.Exec(ctx, updateQuery, updateArgs...)
+		bytes, err := json.Marshal(updatedObj)
 		if err != nil {
 			return storage.NewInternalError(err)
 		}
 
-		if err = tx.Commit(ctx); err != nil {
-			return storage.NewInternalError(err)
+		// If the object didn't change, skip the write and keep the current resourceVersion.
+		if string(bytes) == string(currentBytes) {
+			return setValue(updatedObj, destination)
 		}
 
-		if err = s.broadcaster.Action(watch.Modified, updatedObj); err != nil {
+		err = s.update(ctx, name, namespace, objectRecord.ResourceVersion, updatedObj)
+		if errors.Is(err, errObjectChanged) {
+			s.logger.DebugContext(ctx, "Object changed during the update, retrying", "key", key)
+			continue
+		}
+		if err != nil {
 			return storage.NewInternalError(err)
 		}
 
-		if err = setValue(updatedObj, destination); err != nil {
-			return err
+		return setValue(updatedObj, destination)
+	}
+}
+
+// errObjectChanged is returned by update when the object was written since it was read.
+var errObjectChanged = errors.New("object changed since it was read")
+
+// update writes the updated object, if the object was not written since it was read at readResourceVersion.
+// It returns errObjectChanged otherwise, including when the object was deleted.
+func (s *store) update(
+	ctx context.Context, name, namespace string, readResourceVersion int64, obj runtime.Object,
+) error {
+	// The parts of the object uploaded by the hooks are prepared before taking the writers lock.
+	if err := s.prepare(ctx, obj); err != nil {
+		return err
+	}
+
+	tx, err := s.db.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
 		}
+	}()
+
+	resourceVersion, err := s.db.dialect().nextResourceVersions(ctx, tx, 1)
+	if err != nil {
+		return err
+	}
 
-		break
+	if err = s.Versioner().UpdateObject(obj, resourceVersion); err != nil {
+		return err
 	}
 
+	storedBytes, err := s.marshalStored(obj)
+	if err != nil {
+		return err
+	}
+
+	// Compare and swap: the row is only updated if it was not written since it was read.
+	query, args, err := psql.Update(
+		um.Table(psql.Quote(s.table)),
+		um.SetCol("object").To(psql.Arg(storedBytes)),
+		um.SetCol("resource_version").To(psql.Arg(resourceVersion)),
+		um.Where(psql.Quote("name").EQ(psql.Arg(name))),
+		um.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
+		um.Where(psql.Quote("resource_version").EQ(psql.Arg(readResourceVersion))),
+	).Build(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return errObjectChanged
+	}
+
+	if err = s.persist(ctx, tx, name, namespace, obj); err != nil {
+		return err
+	}
+
+	// The event is marshaled after the hooks completed the object.
+	bytes, err := s.marshalEvent(obj)
+	if err != nil {
+		return err
+	}
+
+	if err = recordEvent(ctx, tx, s.table, watch.Modified, resourceVersion, name, namespace, bytes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	s.broadcaster.notify()
+
 	return nil
 }
 
+// readRecord reads the stored object.
+// When read for update, the row is locked until the end of the transaction on the databases supporting row locks,
+// while the writes of SQLite are serialized by the database lock taken when the transaction begins.
+// The writes must be locked first, so that the row locks are always taken after the writers lock.
+func (s *store) readRecord(
+	ctx context.Context, q querier, name, namespace string, forUpdate bool,
+) (objectSchema, error) {
+	queryBuilder := psql.Select(
+		sm.Columns("name", "namespace", "object", "resource_version"),
+		sm.From(psql.Quote(s.table)),
+		sm.Where(psql.Quote("name").EQ(psql.Arg(name))),
+		sm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
+	)
+	if forUpdate && s.db.dialect().rowLocks() {
+		queryBuilder.Apply(sm.ForUpdate())
+	}
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return objectSchema{}, err
+	}
+
+	var objectRecord objectSchema
+	err = q.QueryRow(ctx, query, args...).Scan(
+		&objectRecord.Name,
+		&objectRecord.Namespace,
+		&objectRecord.Object,
+		&objectRecord.ResourceVersion,
+	)
+	if err != nil {
+		return objectSchema{}, err
+	}
+
+	return objectRecord, nil
+}
+
+// objectColumn returns the column, or the projection, selecting the served objects.
+func (s *store) objectColumn() any {
+	if s.projection == nil {
+		return "object"
+	}
+
+	return psql.Raw(s.projection.object)
+}
+
+// marshalStored returns the JSON stored in the object column,
+// without the parts of the object persisted by the hooks.
+func (s *store) marshalStored(obj runtime.Object) ([]byte, error) {
+	if s.hooks == nil {
+		return json.Marshal(obj)
+	}
+
+	stripped, err := s.hooks.strip(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(stripped)
+}
+
+// marshalEvent returns the JSON stored in the object column of the watch_events table,
+// without the parts of the object shared with the other events.
+func (s *store) marshalEvent(obj runtime.Object) ([]byte, error) {
+	if s.events == nil {
+		return json.Marshal(obj)
+	}
+
+	stripped, err := s.events.stripEvent(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(stripped)
+}
+
+// prepare stores the parts of the object written before the write transaction, if any.
+func (s *store) prepare(ctx context.Context, obj runtime.Object) error {
+	if s.hooks == nil {
+		return nil
+	}
+
+	return s.hooks.prepare(ctx, s.db, obj)
+}
+
+// persist stores the parts of the object kept outside of the object column, if any.
+func (s *store) persist(ctx context.Context, tx transaction, name, namespace string, obj runtime.Object) error {
+	if s.hooks == nil {
+		return nil
+	}
+
+	return s.hooks.persist(ctx, tx, name, namespace, obj)
+}
+
+// hydrate restores the parts of the objects kept outside of the object column, if any.
+func (s *store) hydrate(ctx context.Context, q querier, objs ...runtime.Object) error {
+	if s.hooks == nil {
+		return nil
+	}
+
+	return s.hooks.hydrate(ctx, q, objs)
+}
+
 // Count returns number of different entries under the key (generally being path prefix).
 func (s *store) Count(key string) (int64, error) {
 	s.logger.Debug("Counting objects", "key", key)
@@ -587,15 +1145,39 @@ func (s *store) Count(key string) (int64, error) {
 	return count, nil
 }
 
-// Stats returns storage stats.
-//
-// TODO: this is a dummy implementation to satisfy the storage.Interface.
-func (s *store) Stats(_ context.Context) (storage.Stats, error) {
-	return storage.Stats{}, nil
+// Stats returns the number of objects of the table and the average size of their object column.
+// The size is not estimated for the projections, as computing them for every row would be expensive.
+func (s *store) Stats(ctx context.Context) (storage.Stats, error) {
+	size := psql.Raw("0")
+	if s.projection == nil {
+		size = psql.Raw("COALESCE(CAST(AVG(?) AS BIGINT), 0)", s.db.dialect().objectSize(psql.Quote("object")))
+	}
+
+	query, args, err := psql.Select(
+		sm.Columns("COUNT(*)", size),
+		sm.From(psql.Quote(s.table)),
+	).Build(ctx)
+	if err != nil {
+		return storage.Stats{}, storage.NewInternalError(err)
+	}
+
+	var stats storage.Stats
+	if err = s.db.QueryRow(ctx, query, args...).Scan(&stats.ObjectCount, &stats.EstimatedAverageObjectSizeBytes); err != nil {
+		return storage.Stats{}, storage.NewInternalError(err)
+	}
+
+	return stats, nil
 }
 
-// ReadinessCheck checks if the storage is ready for accepting requests.
+// ReadinessCheck checks if the storage is ready for accepting requests, that is if the database is reachable.
 func (s *store) ReadinessCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), databaseCheckTimeout)
+	defer cancel()
+
+	if err := s.db.Ping(ctx); err != nil {
+		return fmt.Errorf("%w: %w", storage.ErrStorageNotReady, err)
+	}
+
 	return nil
 }
 
@@ -603,15 +1185,20 @@ func (s *store) ReadinessCheck() error {
 // Without ListFromCacheSnapshot enabled only locally executed compaction will be observed.
 // Returns 0 if no compaction was yet observed.
 func (s *store) CompactRevision() int64 {
-	// Return 0, as we don't have compaction in SQL storage.
-	return 0
+	resourceVersion, err := compactedResourceVersion(context.Background(), s.db)
+	if err != nil {
+		s.logger.Error("failed to get compacted resource version", "error", err)
+		return 0
+	}
+
+	return int64(resourceVersion) //nolint:gosec // resource versions are stored as BIGINT
 }
 
 // SetKeysFunc allows to override the function used to get keys from storage.
 // This allows to replace default function that fetches keys from storage with one using cache.
-func (s *store) SetKeysFunc(_ storage.KeysFunc) {
-	// No-op, we don't have a cache implementation.
-}
+// The keys function is ignored: it lets the storages listing their keys to compute the Stats read them from the cache,
+// while Stats counts the objects in SQL without listing their keys.
+func (s *store) SetKeysFunc(_ storage.KeysFunc) {}
 
 // RequestWatchProgress requests the a watch stream progress status be sent in the
 // watch response stream as soon as possible.
@@ -624,19 +1211,28 @@ func (s *store) SetKeysFunc(_ storage.KeysFunc) {
 // Note: Only watches with matching context grpc metadata will be notified.
 // https://github.com/kubernetes/kubernetes/blob/9325a57125e8502941d1b0c7379c4bb80a678d5c/vendor/go.etcd.io/etcd/client/v3/watch.go#L1037-L1042
 //
+// The watches of the store allowing bookmarks are notified with a bookmark carrying
+// the resourceVersion the events have been dispatched up to.
+//
 // TODO: Remove when storage.Interface will be separate from etc3.store.
 // Deprecated: Added temporarily to simplify exposing RequestProgress for watch cache.
 func (s *store) RequestWatchProgress(_ context.Context) error {
-	// As this is a deprecated method, we are not implementing it.
+	s.broadcaster.requestProgress()
+
 	return nil
 }
 
 // GetCurrentResourceVersion gets the current resource version from etcd.
 // This method issues an empty list request and reads only the ResourceVersion from the object metadata
 //
-// TODO: this is a dummy implementation to satisfy the storage.Interface.
-func (s *store) GetCurrentResourceVersion(_ context.Context) (uint64, error) {
-	return 0, nil
+// The current resource version is the resource version of the latest event recorded in the watch_events table.
+func (s *store) GetCurrentResourceVersion(ctx context.Context) (uint64, error) {
+	resourceVersion, err := currentResourceVersion(ctx, s.db)
+	if err != nil {
+		return 0, storage.NewInternalError(err)
+	}
+
+	return resourceVersion, nil
 }
 
 // extractNameAndNamespace extracts the name and namespace from the key.
@@ -697,9 +1293,68 @@ func getItems(listObj runtime.Object) (reflect.Value, error) {
 	return itemsValue, nil
 }
 
-// buildLabelSelectorExpressions builds SQL expressions from the provided k8s label selector
-// using PostgreSQL JSONB operators.
-func buildLabelSelectorExpressions(labelSelector labels.Selector) ([]psql.Expression, error) {
+// labelSelectorColumns are the columns generated from the labels of the objects, by label key.
+var labelSelectorColumns = map[string]string{
+	sbombasticv1alpha1.LabelScanJobUIDKey: "scan_job_uid",
+}
+
+// fieldSelectorColumns are the columns holding the selectable fields of the objects, by field.
+// Apart from the name and the namespace, they are generated from the object column.
+var fieldSelectorColumns = map[string]string{
+	"metadata.name":             "name",
+	"metadata.namespace":        "namespace",
+	"imageMetadata.registry":    "registry",
+	"imageMetadata.registryURI": "registry_uri",
+	"imageMetadata.repository":  "repository",
+	"imageMetadata.tag":         "tag",
+	"imageMetadata.platform":    "platform",
+	"imageMetadata.digest":      "digest",
+}
+
+// integerFieldSelectors are the selectable fields holding integers, compared as numbers.
+var integerFieldSelectors = sets.New(
+	"report.summary.critical",
+	"report.summary.high",
+	"report.summary.medium",
+	"report.summary.low",
+	"report.summary.unknown",
+	"report.summary.suppressed",
+)
+
+// buildSelectorExpressions builds the SQL expressions selecting the objects with the given name and namespace,
+// if not empty, matching the label and field selectors of the predicate.
+func buildSelectorExpressions(
+	d sqlDialect,
+	name, namespace string,
+	predicate storage.SelectionPredicate,
+) ([]psql.Expression, error) {
+	var expressions []psql.Expression
+	if name != "" {
+		expressions = append(expressions, psql.Quote("name").EQ(psql.Arg(name)))
+	}
+	if namespace != "" {
+		expressions = append(expressions, psql.Quote("namespace").EQ(psql.Arg(namespace)))
+	}
+
+	labelSelectorExpressions, err := buildLabelSelectorExpressions(d, predicate.Label)
+	if err != nil {
+		return nil, err
+	}
+	expressions = append(expressions, labelSelectorExpressions...)
+
+	fieldSelectorExpressions, err := buildFieldSelectorExpressions(d, predicate.Field)
+	if err != nil {
+		return nil, err
+	}
+	expressions = append(expressions, fieldSelectorExpressions...)
+
+	return expressions, nil
+}
+
+// buildLabelSelectorExpressions builds SQL expressions from the provided k8s label selector,
+// matching the objects like labels.Requirement does.
+// The labels with a generated column are compared on the column, the others use the JSON operators of the SQL dialect.
+func buildLabelSelectorExpressions(d sqlDialect, labelSelector labels.Selector) ([]psql.Expression, error) {
 	var expressions []psql.Expression
 	requirements, selectable := labelSelector.Requirements()
 	if !selectable {
@@ -708,22 +1363,41 @@ func buildLabelSelectorExpressions(labelSelector labels.Selector) ([]psql.Expres
 
 	for _, req := range requirements {
 		var expression psql.Expression
+		label := d.jsonText("metadata", "labels", req.Key())
+		column, hasColumn := labelSelectorColumns[req.Key()]
+		if hasColumn {
+			label = psql.Quote(column)
+		}
+		values := req.Values().List()
 
 		switch req.Operator() {
 		case selection.Equals, selection.DoubleEquals:
-			expression = psql.Raw("object->'metadata'->'labels'->>?", req.Key()).EQ(psql.Arg(req.Values().List()[0]))
+			if hasColumn {
+				expression = label.EQ(psql.Arg(values[0]))
+			} else {
+				expression = d.labelEquals(req.Key(), values[0])
+			}
 		case selection.NotEquals:
-			expression = psql.Raw("object->'metadata'->'labels'->>?", req.Key()).NE(psql.Arg(req.Values().List()[0]))
+			expression = isNullOr(label, label.NE(psql.Arg(values[0])))
 		case selection.In:
-			expression = psql.Raw("object->'metadata'->'labels'->>? = ANY(?)", req.Key(), req.Values().List())
+			expression = d.in(label, values)
 		case selection.NotIn:
-			expression = psql.Raw("object->'metadata'->'labels'->>? != ALL(?)", req.Key(), req.Values().List())
+			expression = isNullOr(label, d.notIn(label, values))
 		case selection.Exists:
-			expression = psql.Raw("jsonb_exists(object->'metadata'->'labels', ?)", req.Key())
+			expression = d.labelExists(req.Key())
 		case selection.DoesNotExist:
-			expression = psql.Not(psql.Raw("jsonb_exists(object->'metadata'->'labels', ?)", req.Key()))
+			expression = psql.Not(d.labelExists(req.Key()))
 		case selection.GreaterThan, selection.LessThan:
-			return nil, fmt.Errorf("unsupported label selector operator: %s", req.Operator())
+			// The labels whose value is not an integer do not match.
+			value, err := strconv.ParseInt(values[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of label selector %q: %w", req.String(), err)
+			}
+			if req.Operator() == selection.GreaterThan {
+				expression = d.integer(label).GT(psql.Arg(value))
+			} else {
+				expression = d.integer(label).LT(psql.Arg(value))
+			}
 		}
 
 		expressions = append(expressions, expression)
@@ -732,25 +1406,39 @@ func buildLabelSelectorExpressions(labelSelector labels.Selector) ([]psql.Expres
 	return expressions, nil
 }
 
-// buildFieldSelectorExpressions builds SQL expressions from the provided k8s field selector
-// using PostgreSQL JSONB operators.
-func buildFieldSelectorExpressions(fieldSelector fields.Selector) ([]psql.Expression, error) {
+// buildFieldSelectorExpressions builds SQL expressions from the provided k8s field selector.
+// The fields with a column are compared on the column, the others use the JSON operators of the SQL dialect.
+// The integer fields are compared as numbers.
+// The field selectors are parsed by fields.ParseSelector, which only supports the =, == and != operators.
+func buildFieldSelectorExpressions(d sqlDialect, fieldSelector fields.Selector) ([]psql.Expression, error) {
 	var expressions []psql.Expression
 	requirements := fieldSelector.Requirements()
 
 	for _, req := range requirements {
-		// Convert dot notation to JSON path
-		// "metadata.name" -> {metadata,name}
-		pathParts := strings.Split(req.Field, ".")
-		jsonPath := "{" + strings.Join(pathParts, ",") + "}"
+		// Convert dot notation to a JSON path
+		// "metadata.labels" -> [metadata, labels]
+		field := d.jsonText(strings.Split(req.Field, ".")...)
+		if column, ok := fieldSelectorColumns[req.Field]; ok {
+			field = psql.Quote(column)
+		}
+
+		var value any = req.Value
+		if integerFieldSelectors.Has(req.Field) {
+			integerValue, err := strconv.ParseInt(req.Value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of field selector %q: %w", req.Field, err)
+			}
+			field = d.integer(field)
+			value = integerValue
+		}
 
 		var expression psql.Expression
 
 		switch req.Operator {
 		case selection.Equals, selection.DoubleEquals:
-			expression = psql.Raw("object #>> ?", jsonPath).EQ(psql.Arg(req.Value))
+			expression = field.EQ(psql.Arg(value))
 		case selection.NotEquals:
-			expression = psql.Raw("object #>> ?", jsonPath).NE(psql.Arg(req.Value))
+			expression = isNullOr(field, field.NE(psql.Arg(value)))
 		case selection.In, selection.NotIn, selection.Exists, selection.DoesNotExist, selection.GreaterThan, selection.LessThan:
 			return nil, fmt.Errorf("unsupported field selector operator: %v", req.Operator)
 		}
@@ -759,3 +1447,9 @@ func buildFieldSelectorExpressions(fieldSelector fields.Selector) ([]psql.Expres
 	}
 	return expressions, nil
 }
+
+// isNullOr returns an expression matching when the expression is NULL or the condition is true.
+// The negative selectors match the objects without the label or the field.
+func isNullOr(expression, condition psql.Expression) psql.Expression {
+	return psql.Group(psql.Or(expression.IsNull(), condition))
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+const (
+	// benchmarkSBOMs is the number of SBOMs stored by the benchmarks.
+	benchmarkSBOMs = 100_000
+	// benchmarkRegistries is the number of registries the SBOMs are spread across.
+	benchmarkRegistries = 100
+	// benchmarkWriters is the number of concurrent writers of the write benchmarks, per CPU.
+	benchmarkWriters = 4
+)
+
+// BenchmarkGetListFieldSelector lists the SBOMs of a registry with a field selector,
+// served by the generated registry column, and compares it with the JSON expression
+// the field selectors were translated to before the column was added.
+func BenchmarkGetListFieldSelector(b *testing.B) {
+	for _, backend := range []Backend{PostgresBackend, SQLiteBackend} {
+		b.Run(string(backend), func(b *testing.B) {
+			ctx := context.Background()
+			db := openBenchmarkDatabase(b, backend)
+			storeBenchmarkSBOMs(b, db)
+
+			s := newStore(
+				db,
+				"sboms",
+				func() runtime.Object { return &v1alpha1.SBOM{} },
+				func() runtime.Object { return &v1alpha1.SBOMList{} },
+				nil,
+				slog.Default(),
+			)
+			b.Cleanup(s.destroy)
+
+			b.Run("generated column", func(b *testing.B) {
+				opts := storage.ListOptions{
+					Recursive: true,
+					Predicate: matcher(labels.Everything(), fields.OneTermEqualSelector("imageMetadata.registry", "registry-42")),
+				}
+
+				for b.Loop() {
+					list := &v1alpha1.SBOMList{}
+					require.NoError(b, s.GetList(ctx, keyPrefix, opts, list))
+					require.Len(b, list.Items, benchmarkSBOMs/benchmarkRegistries)
+				}
+			})
+
+			b.Run("json expression", func(b *testing.B) {
+				query, args, err := psql.Select(
+					sm.Columns("name", "namespace", "object"),
+					sm.From("sboms"),
+					sm.Where(db.dialect().jsonText("imageMetadata", "registry").EQ(psql.Arg("registry-42"))),
+					sm.OrderBy("namespace"),
+					sm.OrderBy("name"),
+				).Build(ctx)
+				require.NoError(b, err)
+
+				for b.Loop() {
+					r, err := db.Query(ctx, query, args...)
+					require.NoError(b, err)
+
+					objects, err := collectRows(r, func(row row) ([]byte, error) {
+						var objectRecord objectSchema
+						err := row.Scan(&objectRecord.Name, &objectRecord.Namespace, &objectRecord.Object)
+						return objectRecord.Object, err
+					})
+					require.NoError(b, err)
+					require.Len(b, objects, benchmarkSBOMs/benchmarkRegistries)
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkCreateVulnerabilityReports creates VulnerabilityReports from concurrent writers,
+// to measure the write throughput of the storage, which serializes the write transactions.
+// The throughput decreases with the findings of the reports, which are written while holding the writers lock.
+func BenchmarkCreateVulnerabilityReports(b *testing.B) {
+	for _, backend := range []Backend{PostgresBackend, SQLiteBackend} {
+		b.Run(string(backend), func(b *testing.B) {
+			db := openBenchmarkDatabase(b, backend)
+
+			s := newStore(
+				db,
+				"vulnerabilityreports",
+				func() runtime.Object { return &v1alpha1.VulnerabilityReport{} },
+				func() runtime.Object { return &v1alpha1.VulnerabilityReportList{} },
+				vulnerabilityReportHooks(db),
+				slog.Default(),
+			)
+			b.Cleanup(s.destroy)
+
+			// The sub-benchmarks run several times, so the names of the reports are unique across the runs.
+			var reports atomic.Int64
+			for _, findings := range []int{10, 1000} {
+				b.Run(fmt.Sprintf("%d findings", findings), func(b *testing.B) {
+					vulnerabilities := make([]v1alpha1.Vulnerability, 0, findings)
+					for i := range findings {
+						vulnerability := testVulnerability1
+						vulnerability.CVE = fmt.Sprintf("CVE-2024-%05d", i)
+						vulnerabilities = append(vulnerabilities, vulnerability)
+					}
+
+					b.SetParallelism(benchmarkWriters)
+					b.RunParallel(func(pb *testing.PB) {
+						for pb.Next() {
+							name := fmt.Sprintf("report-%d", reports.Add(1))
+							report := newTestVulnerabilityReport(name, vulnerabilities...)
+							err := s.Create(
+								context.Background(),
+								vulnerabilityReportKeyPrefix+"/default/"+name,
+								report,
+								&v1alpha1.VulnerabilityReport{},
+								0,
+							)
+							require.NoError(b, err)
+						}
+					})
+					b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "reports/s")
+				})
+			}
+		})
+	}
+}
+
+// openBenchmarkDatabase opens a migrated database of the given backend, closed when the benchmark ends.
+func openBenchmarkDatabase(b *testing.B, backend Backend) Database {
+	b.Helper()
+	ctx := context.Background()
+
+	var db Database
+	switch backend {
+	case PostgresBackend:
+		pgContainer, err := postgres.Run(ctx,
+			"postgres:16-alpine",
+			postgres.WithDatabase("testdb"),
+			postgres.WithUsername("testuser"),
+			postgres.WithPassword("testpassword"),
+			postgres.BasicWaitStrategies(),
+		)
+		require.NoError(b, err, "failed to start postgres container")
+		b.Cleanup(func() {
+			require.NoError(b, pgContainer.Terminate(context.Background()), "failed to terminate postgres container")
+		})
+
+		connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+		require.NoError(b, err, "failed to get connection string")
+
+		pool, err := pgxpool.New(ctx, connStr)
+		require.NoError(b, err, "failed to create connection pool")
+		db = NewPostgresDatabase(pool)
+	case SQLiteBackend:
+		var err error
+		db, err = OpenSQLiteDatabase(filepath.Join(b.TempDir(), "storage.db"))
+		require.NoError(b, err, "failed to open sqlite database")
+	}
+	b.Cleanup(func() {
+		require.NoError(b, db.Close(), "failed to close database")
+	})
+
+	require.NoError(b, Migrate(ctx, db, slog.Default()), "failed to run migrations")
+
+	return db
+}
+
+// storeBenchmarkSBOMs bulk inserts the SBOMs, spread evenly across the registries.
+func storeBenchmarkSBOMs(b *testing.B, db Database) {
+	b.Helper()
+	ctx := context.Background()
+
+	rows := make([][]any, 0, benchmarkSBOMs)
+	for i := range benchmarkSBOMs {
+		sbom := &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("sbom-%d", i),
+				Namespace:       "default",
+				ResourceVersion: "1",
+			},
+			ImageMetadata: v1alpha1.ImageMetadata{
+				Registry:   fmt.Sprintf("registry-%d", i%benchmarkRegistries),
+				Repository: fmt.Sprintf("repository-%d", i),
+				Tag:        "latest",
+			},
+		}
+		object, err := json.Marshal(sbom)
+		require.NoError(b, err)
+
+		rows = append(rows, []any{sbom.Name, sbom.Namespace, object, int64(1)})
+	}
+
+	tx, err := db.begin(ctx)
+	require.NoError(b, err)
+	defer func() {
+		require.NoError(b, tx.Rollback(ctx))
+	}()
+
+	require.NoError(b, tx.copyFrom(ctx, "sboms", []string{"name", "namespace", "object", "resource_version"}, rows))
+	require.NoError(b, tx.Commit(ctx))
+}
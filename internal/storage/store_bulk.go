@@ -0,0 +1,493 @@
+//nolint:wrapcheck // We want to return the errors from k8s.io/apiserver/pkg/storage as they are.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dm"
+	"github.com/stephenafamo/bob/dialect/psql/im"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+// bulkWriteBatchSize is the maximum number of objects read or written by a single statement of the bulk writes,
+// keeping the number of bound parameters under the limits of the databases.
+const bulkWriteBatchSize = 1000
+
+// deleteCollection deletes the objects found at key matching the predicate in a single transaction,
+// recording a DELETED event with its own resourceVersion for each of them.
+// validateDeletion is called with every object before any is deleted, so that an error aborts the whole deletion.
+//
+// The objects with finalizers are not deleted, as deleting them means setting their deletionTimestamp
+// and waiting for the finalizers to be removed: they are returned apart, so that the registry can delete them.
+// It returns the deleted objects, with the resourceVersion they had before the deletion, and the finalized ones.
+//
+//nolint:gocognit,funlen // The deletion is a single transaction.
+func (s *store) deleteCollection(
+	ctx context.Context,
+	key string,
+	predicate storage.SelectionPredicate,
+	validateDeletion storage.ValidateObjectFunc,
+) ([]runtime.Object, []runtime.Object, error) {
+	if predicate.Label == nil {
+		predicate.Label = labels.Everything()
+	}
+	if predicate.Field == nil {
+		predicate.Field = fields.Everything()
+	}
+
+	s.logger.DebugContext(ctx, "Deleting collection",
+		"key", key,
+		"labelSelector", predicate.Label.String(),
+		"fieldSelector", predicate.Field.String(),
+	)
+
+	selectorExpressions, err := buildSelectorExpressions(s.db.dialect(), "", extractNamespace(key), predicate)
+	if err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+
+	queryBuilder := psql.Select(
+		sm.Columns("name", "namespace", "object"),
+		sm.From(psql.Quote(s.table)),
+		sm.OrderBy("namespace"),
+		sm.OrderBy("name"),
+	)
+	for _, expression := range selectorExpressions {
+		queryBuilder.Apply(sm.Where(expression))
+	}
+	if s.db.dialect().rowLocks() {
+		queryBuilder.Apply(sm.ForUpdate())
+	}
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+
+	tx, err := s.db.begin(ctx)
+	if err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// The writers are serialized before the rows are locked, like the other write paths.
+	if err = s.db.dialect().lockWrites(ctx, tx); err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+
+	// The selected rows are locked, so the objects are deleted as they are read.
+	objs, err := s.readObjects(ctx, tx, query, args)
+	if err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+
+	var deleted, finalized []runtime.Object
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, nil, storage.NewInternalError(err)
+		}
+		if len(accessor.GetFinalizers()) > 0 {
+			finalized = append(finalized, obj)
+			continue
+		}
+
+		if err = validateDeletion(ctx, obj); err != nil {
+			return nil, nil, err
+		}
+		deleted = append(deleted, obj)
+	}
+
+	if len(deleted) == 0 {
+		return nil, finalized, nil
+	}
+
+	resourceVersion, err := s.db.dialect().nextResourceVersions(ctx, tx, len(deleted))
+	if err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+
+	names := make(map[string][]string)
+	events := make([]eventSchema, len(deleted))
+	for i, obj := range deleted {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, nil, storage.NewInternalError(err)
+		}
+		names[accessor.GetNamespace()] = append(names[accessor.GetNamespace()], accessor.GetName())
+
+		// The object sent in the DELETED event carries the resourceVersion of the deletion,
+		// so that watchers can resume after it.
+		deletedObj := obj.DeepCopyObject()
+		if err = s.Versioner().UpdateObject(deletedObj, resourceVersion+uint64(i)); err != nil { //nolint:gosec // i is positive
+			return nil, nil, storage.NewInternalError(err)
+		}
+
+		bytes, err := s.marshalEvent(deletedObj)
+		if err != nil {
+			return nil, nil, storage.NewInternalError(err)
+		}
+
+		events[i] = eventSchema{
+			ResourceVersion: resourceVersion + uint64(i), //nolint:gosec // i is positive
+			Type:            string(watch.Deleted),
+			Name:            accessor.GetName(),
+			Namespace:       accessor.GetNamespace(),
+			Object:          bytes,
+		}
+	}
+
+	for namespace, namespaceNames := range names {
+		for chunk := range slices.Chunk(namespaceNames, bulkWriteBatchSize) {
+			deleteQuery, deleteArgs, err := psql.Delete(
+				dm.From(psql.Quote(s.table)),
+				dm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
+				dm.Where(s.db.dialect().in(psql.Quote("name"), chunk)),
+			).Build(ctx)
+			if err != nil {
+				return nil, nil, storage.NewInternalError(err)
+			}
+
+			if _, err = tx.Exec(ctx, deleteQuery, deleteArgs...); err != nil {
+				return nil, nil, storage.NewInternalError(err)
+			}
+		}
+	}
+
+	if err = recordEvents(ctx, tx, s.table, events); err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, nil, storage.NewInternalError(err)
+	}
+
+	s.broadcaster.notify()
+
+	return deleted, finalized, nil
+}
+
+// upsert creates the objects of the namespace found at key that do not exist and updates the others,
+// in a single transaction recording an ADDED or MODIFIED event with its own resourceVersion for each write.
+// prepare is called with every object and its current version, nil if it does not exist,
+// before any is written, so that an error aborts the whole write.
+// The objects whose prepared version is equal to the current one are not written.
+// The written objects are updated with their new resourceVersion.
+//
+// It returns the number of created and updated objects.
+// A dry run returns them without writing anything.
+// If an object is created concurrently, a KeyExists error is returned, as it was prepared as a new object.
+//
+//nolint:gocognit,gocyclo,cyclop,funlen // The write is a single transaction.
+func (s *store) upsert(
+	ctx context.Context,
+	key string,
+	objs []runtime.Object,
+	prepare func(obj, current runtime.Object) error,
+	dryRun bool,
+) (int, int, error) {
+	namespace := extractNamespace(key)
+	if namespace == "" {
+		return 0, 0, storage.NewInternalError(fmt.Errorf("invalid key: %s", key))
+	}
+
+	s.logger.DebugContext(ctx, "Upserting objects", "key", key, "count", len(objs))
+
+	names := make([]string, len(objs))
+	for i, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+		if accessor.GetNamespace() != namespace {
+			return 0, 0, storage.NewInternalError(
+				fmt.Errorf("object %s/%s is not in namespace %s", accessor.GetNamespace(), accessor.GetName(), namespace),
+			)
+		}
+		names[i] = accessor.GetName()
+
+		// The parts of the objects uploaded by the hooks are prepared before taking the writers lock.
+		if !dryRun {
+			if err = s.prepare(ctx, obj); err != nil {
+				return 0, 0, storage.NewInternalError(err)
+			}
+		}
+	}
+
+	tx, err := s.db.begin(ctx)
+	if err != nil {
+		return 0, 0, storage.NewInternalError(err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// The writers are serialized before the rows are locked, like the other write paths.
+	if err = s.db.dialect().lockWrites(ctx, tx); err != nil {
+		return 0, 0, storage.NewInternalError(err)
+	}
+
+	currentObjs := make(map[string]runtime.Object, len(objs))
+	for chunk := range slices.Chunk(names, bulkWriteBatchSize) {
+		queryBuilder := psql.Select(
+			sm.Columns("name", "namespace", "object"),
+			sm.From(psql.Quote(s.table)),
+			sm.Where(psql.Quote("namespace").EQ(psql.Arg(namespace))),
+			sm.Where(s.db.dialect().in(psql.Quote("name"), chunk)),
+		)
+		if s.db.dialect().rowLocks() {
+			queryBuilder.Apply(sm.ForUpdate())
+		}
+
+		query, args, err := queryBuilder.Build(ctx)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+
+		chunkObjs, err := s.readObjects(ctx, tx, query, args)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+		for _, obj := range chunkObjs {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return 0, 0, storage.NewInternalError(err)
+			}
+			currentObjs[accessor.GetName()] = obj
+		}
+	}
+
+	var created, updated []runtime.Object
+	for i, obj := range objs {
+		current, exists := currentObjs[names[i]]
+		if !exists {
+			if err = prepare(obj, nil); err != nil {
+				return 0, 0, err
+			}
+			created = append(created, obj)
+			continue
+		}
+
+		if err = prepare(obj, current); err != nil {
+			return 0, 0, err
+		}
+
+		version, err := s.Versioner().ObjectResourceVersion(current)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+		if err = s.Versioner().UpdateObject(obj, version); err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+
+		bytes, err := json.Marshal(obj)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+		currentBytes, err := json.Marshal(current)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+
+		// If the object didn't change, skip the write and keep the current resourceVersion.
+		if string(bytes) != string(currentBytes) {
+			updated = append(updated, obj)
+		}
+	}
+
+	writes := len(created) + len(updated)
+	if writes == 0 || dryRun {
+		return len(created), len(updated), nil
+	}
+
+	resourceVersion, err := s.db.dialect().nextResourceVersions(ctx, tx, writes)
+	if err != nil {
+		return 0, 0, storage.NewInternalError(err)
+	}
+
+	events := make([]eventSchema, 0, writes)
+	rowValues := func(objs []runtime.Object, eventType watch.EventType) ([][]any, error) {
+		values := make([][]any, len(objs))
+		for i, obj := range objs {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			objResourceVersion := resourceVersion + uint64(len(events)) //nolint:gosec // the length is positive
+			if err = s.Versioner().UpdateObject(obj, objResourceVersion); err != nil {
+				return nil, err
+			}
+
+			storedBytes, err := s.marshalStored(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = []any{accessor.GetName(), namespace, storedBytes, objResourceVersion}
+			events = append(events, eventSchema{
+				ResourceVersion: objResourceVersion,
+				Type:            string(eventType),
+				Name:            accessor.GetName(),
+				Namespace:       namespace,
+			})
+		}
+
+		return values, nil
+	}
+
+	createdRows, err := rowValues(created, watch.Added)
+	if err != nil {
+		return 0, 0, storage.NewInternalError(err)
+	}
+	for chunk := range slices.Chunk(createdRows, bulkWriteBatchSize) {
+		if err = s.insertRows(ctx, tx, key, chunk); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	updatedRows, err := rowValues(updated, watch.Modified)
+	if err != nil {
+		return 0, 0, storage.NewInternalError(err)
+	}
+	for chunk := range slices.Chunk(updatedRows, bulkWriteBatchSize) {
+		// The updated rows are locked, or the writes serialized, so they have not been written since they were read.
+		queryBuilder := psql.Insert(
+			im.Into(psql.Quote(s.table), "name", "namespace", "object", "resource_version"),
+			im.OnConflict("name", "namespace").DoUpdate(im.SetExcluded("object", "resource_version")),
+		)
+		for _, values := range chunk {
+			queryBuilder.Apply(im.Values(psql.Arg(values...)))
+		}
+
+		query, args, err := queryBuilder.Build(ctx)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+	}
+
+	// The events are in the order of the written objects, and marshaled after the hooks completed them.
+	for i, obj := range slices.Concat(created, updated) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+		if err = s.persist(ctx, tx, accessor.GetName(), namespace, obj); err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+		if events[i].Object, err = s.marshalEvent(obj); err != nil {
+			return 0, 0, storage.NewInternalError(err)
+		}
+	}
+
+	if err = recordEvents(ctx, tx, s.table, events); err != nil {
+		return 0, 0, storage.NewInternalError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, 0, storage.NewInternalError(err)
+	}
+
+	s.broadcaster.notify()
+
+	return len(created), len(updated), nil
+}
+
+// insertRows inserts the rows of new objects, given as name, namespace, object and resource_version values.
+// If one of the objects already exists, a KeyExists error is returned.
+func (s *store) insertRows(ctx context.Context, tx transaction, key string, values [][]any) error {
+	queryBuilder := psql.Insert(
+		im.Into(psql.Quote(s.table), "name", "namespace", "object", "resource_version"),
+		im.OnConflict().DoNothing(),
+		im.Returning("name"),
+	)
+	for _, rowValues := range values {
+		queryBuilder.Apply(im.Values(psql.Arg(rowValues...)))
+	}
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	result, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	inserted, err := collectRows(result, func(row row) (string, error) {
+		var name string
+		err := row.Scan(&name)
+		return name, err
+	})
+	if err != nil {
+		return storage.NewInternalError(err)
+	}
+
+	if len(inserted) == len(values) {
+		return nil
+	}
+
+	insertedNames := sets.New(inserted...)
+	for _, rowValues := range values {
+		if name, ok := rowValues[0].(string); ok && !insertedNames.Has(name) {
+			return storage.NewKeyExistsError(key+"/"+name, 0)
+		}
+	}
+
+	return nil
+}
+
+// readObjects returns the objects selected by the query, whose columns must be the name, the namespace
+// and the object, with the parts kept outside of the object column.
+func (s *store) readObjects(ctx context.Context, tx transaction, query string, args []any) ([]runtime.Object, error) {
+	result, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := collectRows(result, func(row row) (runtime.Object, error) {
+		var objectRecord objectSchema
+		if err := row.Scan(&objectRecord.Name, &objectRecord.Namespace, &objectRecord.Object); err != nil {
+			return nil, err
+		}
+
+		obj := s.newFunc()
+		if err := json.Unmarshal(objectRecord.Object, obj); err != nil {
+			return nil, err
+		}
+
+		return obj, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.hydrate(ctx, tx, objs...); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
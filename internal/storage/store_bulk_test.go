@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// createTestSBOMs creates an SBOM in the default namespace for each name, with the given labels.
+func (suite *storeTestSuite) createTestSBOMs(objectLabels map[string]string, names ...string) []*v1alpha1.SBOM {
+	sboms := make([]*v1alpha1.SBOM, 0, len(names))
+	for _, name := range names {
+		sbom := &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    objectLabels,
+			},
+		}
+		err := suite.store.Create(context.Background(), keyPrefix+"/default/"+name, sbom, &v1alpha1.SBOM{}, 0)
+		suite.Require().NoError(err)
+		sboms = append(sboms, sbom)
+	}
+
+	return sboms
+}
+
+// storedNames returns the names of the SBOMs of the default namespace, in order.
+func (suite *storeTestSuite) storedNames() []string {
+	list := &v1alpha1.SBOMList{}
+	err := suite.store.GetList(context.Background(), keyPrefix+"/default", storage.ListOptions{
+		Recursive: true,
+		Predicate: storage.Everything,
+	}, list)
+	suite.Require().NoError(err)
+
+	names := []string{}
+	for _, sbom := range list.Items {
+		names = append(names, sbom.Name)
+	}
+
+	return names
+}
+
+func (suite *storeTestSuite) TestDeleteCollection() {
+	sboms := suite.createTestSBOMs(map[string]string{"env": "test"}, "test1", "test2")
+	suite.createTestSBOMs(map[string]string{"env": "prod"}, "prod")
+
+	finalizedSBOM := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "finalized",
+			Namespace:  "default",
+			Labels:     map[string]string{"env": "test"},
+			Finalizers: []string{"test/finalizer"},
+		},
+	}
+	err := suite.store.Create(context.Background(), keyPrefix+"/default/finalized", finalizedSBOM, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	currentResourceVersion, err := suite.store.GetCurrentResourceVersion(context.Background())
+	suite.Require().NoError(err)
+
+	predicate := matcher(labels.SelectorFromSet(labels.Set{"env": "test"}), fields.Everything())
+
+	// A failed validation aborts the whole deletion.
+	validationErr := errors.New("deletion not allowed")
+	_, _, err = suite.store.deleteCollection(context.Background(), keyPrefix+"/default", predicate,
+		func(_ context.Context, obj runtime.Object) error {
+			if sbom, ok := obj.(*v1alpha1.SBOM); ok && sbom.Name == "test2" {
+				return validationErr
+			}
+			return nil
+		},
+	)
+	suite.Require().ErrorIs(err, validationErr)
+	suite.Equal([]string{"finalized", "prod", "test1", "test2"}, suite.storedNames())
+
+	deleted, finalized, err := suite.store.deleteCollection(context.Background(), keyPrefix+"/default", predicate,
+		func(_ context.Context, _ runtime.Object) error { return nil },
+	)
+	suite.Require().NoError(err)
+	suite.Equal([]runtime.Object{sboms[0], sboms[1]}, deleted)
+	suite.Equal([]runtime.Object{finalizedSBOM}, finalized)
+	suite.Equal([]string{"finalized", "prod"}, suite.storedNames())
+
+	// Every deleted object has its own DELETED event, carrying the resourceVersion of the deletion.
+	events, err := fetchEvents(context.Background(), suite.db, "sboms", "object", currentResourceVersion, 0, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 2)
+	for i, event := range events {
+		suite.Equal(currentResourceVersion+uint64(i)+1, event.ResourceVersion)
+		suite.Equal(string(watch.Deleted), event.Type)
+		suite.Equal(sboms[i].Name, event.Name)
+	}
+
+	// Nothing is written when no object matches.
+	deleted, finalized, err = suite.store.deleteCollection(context.Background(), keyPrefix+"/default", predicate,
+		func(_ context.Context, _ runtime.Object) error { return nil },
+	)
+	suite.Require().NoError(err)
+	suite.Empty(deleted)
+	suite.Len(finalized, 1)
+}
+
+func (suite *storeTestSuite) TestUpsert() {
+	existing := suite.createTestSBOMs(nil, "unchanged", "updated")
+
+	currentResourceVersion, err := suite.store.GetCurrentResourceVersion(context.Background())
+	suite.Require().NoError(err)
+
+	newSBOM := func(name string, objectLabels map[string]string) *v1alpha1.SBOM {
+		return &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: objectLabels},
+		}
+	}
+	objs := []runtime.Object{
+		newSBOM("unchanged", nil),
+		newSBOM("updated", map[string]string{"updated": "true"}),
+		newSBOM("created", nil),
+	}
+
+	var currents []runtime.Object
+	prepare := func(_, current runtime.Object) error {
+		currents = append(currents, current)
+		return nil
+	}
+
+	// A dry run counts the writes without doing them.
+	created, updated, err := suite.store.upsert(context.Background(), keyPrefix+"/default", objs, prepare, true)
+	suite.Require().NoError(err)
+	suite.Equal(1, created)
+	suite.Equal(1, updated)
+	suite.Equal([]string{"unchanged", "updated"}, suite.storedNames())
+
+	currents = nil
+	created, updated, err = suite.store.upsert(context.Background(), keyPrefix+"/default", objs, prepare, false)
+	suite.Require().NoError(err)
+	suite.Equal(1, created)
+	suite.Equal(1, updated)
+	suite.Equal([]runtime.Object{existing[0], existing[1], nil}, currents)
+	suite.Equal([]string{"created", "unchanged", "updated"}, suite.storedNames())
+
+	// The unchanged object keeps its resourceVersion, the written ones get their own.
+	unchanged, ok := objs[0].(*v1alpha1.SBOM)
+	suite.Require().True(ok)
+	suite.Equal(existing[0].ResourceVersion, unchanged.ResourceVersion)
+
+	events, err := fetchEvents(context.Background(), suite.db, "sboms", "object", currentResourceVersion, 0, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 2)
+	suite.Equal(string(watch.Added), events[0].Type)
+	suite.Equal("created", events[0].Name)
+	suite.Equal(currentResourceVersion+1, events[0].ResourceVersion)
+	suite.Equal(string(watch.Modified), events[1].Type)
+	suite.Equal("updated", events[1].Name)
+	suite.Equal(currentResourceVersion+2, events[1].ResourceVersion)
+
+	stored := &v1alpha1.SBOM{}
+	err = suite.store.Get(context.Background(), keyPrefix+"/default/updated", storage.GetOptions{}, stored)
+	suite.Require().NoError(err)
+	suite.Equal(objs[1], stored)
+
+	// An error of prepare aborts the whole write.
+	prepareErr := errors.New("invalid object")
+	_, _, err = suite.store.upsert(
+		context.Background(),
+		keyPrefix+"/default",
+		[]runtime.Object{newSBOM("other", nil), newSBOM("invalid", nil)},
+		func(obj, _ runtime.Object) error {
+			if sbom, ok := obj.(*v1alpha1.SBOM); ok && sbom.Name == "invalid" {
+				return prepareErr
+			}
+			return nil
+		},
+		false,
+	)
+	suite.Require().ErrorIs(err, prepareErr)
+	suite.Equal([]string{"created", "unchanged", "updated"}, suite.storedNames())
+}
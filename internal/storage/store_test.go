@@ -3,19 +3,27 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/utils/ptr"
 
@@ -24,41 +32,49 @@ import (
 
 const keyPrefix = "/storage.sbomscanner.kubewarden.io/sboms"
 
+// storeTestSuite runs the store tests against the database of the given backend.
 type storeTestSuite struct {
 	suite.Suite
+	backend     Backend
 	store       *store
-	db          *pgxpool.Pool
-	broadcaster *watch.Broadcaster
+	db          Database
 	pgContainer *postgres.PostgresContainer
 }
 
 func (suite *storeTestSuite) SetupSuite() {
 	ctx := context.Background()
 
-	pgContainer, err := postgres.Run(ctx,
-		"postgres:16-alpine",
-		postgres.WithDatabase("testdb"),
-		postgres.WithUsername("testuser"),
-		postgres.WithPassword("testpassword"),
-		postgres.BasicWaitStrategies(),
-	)
-	suite.Require().NoError(err, "failed to start postgres container")
-	suite.pgContainer = pgContainer
-
-	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
-	suite.Require().NoError(err, "failed to get connection string")
-
-	db, err := pgxpool.New(ctx, connStr)
-	suite.Require().NoError(err, "failed to create connection pool")
-	suite.db = db
+	switch suite.backend {
+	case PostgresBackend:
+		pgContainer, err := postgres.Run(ctx,
+			"postgres:16-alpine",
+			postgres.WithDatabase("testdb"),
+			postgres.WithUsername("testuser"),
+			postgres.WithPassword("testpassword"),
+			postgres.BasicWaitStrategies(),
+		)
+		suite.Require().NoError(err, "failed to start postgres container")
+		suite.pgContainer = pgContainer
+
+		connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+		suite.Require().NoError(err, "failed to get connection string")
+
+		pool, err := pgxpool.New(ctx, connStr)
+		suite.Require().NoError(err, "failed to create connection pool")
+		suite.db = NewPostgresDatabase(pool)
+	case SQLiteBackend:
+		db, err := OpenSQLiteDatabase(filepath.Join(suite.T().TempDir(), "storage.db"))
+		suite.Require().NoError(err, "failed to open sqlite database")
+		suite.db = db
+	}
 
-	_, err = db.Exec(ctx, CreateSBOMTableSQL)
-	suite.Require().NoError(err, "failed to create SBOM table")
+	err := Migrate(ctx, suite.db, slog.Default())
+	suite.Require().NoError(err, "failed to run migrations")
 }
 
 func (suite *storeTestSuite) TearDownSuite() {
 	if suite.db != nil {
-		suite.db.Close()
+		suite.Require().NoError(suite.db.Close(), "failed to close database")
 	}
 
 	if suite.pgContainer != nil {
@@ -69,26 +85,56 @@ func (suite *storeTestSuite) TearDownSuite() {
 
 func (suite *storeTestSuite) SetupTest() {
 	ctx := context.Background()
-	_, err := suite.db.Exec(ctx, "TRUNCATE TABLE sboms")
-	suite.Require().NoError(err, "failed to truncate table")
 
-	suite.broadcaster = watch.NewBroadcaster(1000, watch.WaitIfChannelFull)
-	suite.store = &store{
-		db:          suite.db,
-		broadcaster: suite.broadcaster,
-		table:       "sboms",
-		newFunc:     func() runtime.Object { return &v1alpha1.SBOM{} },
-		newListFunc: func() runtime.Object { return &v1alpha1.SBOMList{} },
-		logger:      slog.Default(),
+	switch suite.backend {
+	case PostgresBackend:
+		_, err := suite.db.Exec(
+			ctx,
+			"TRUNCATE TABLE images, sboms, sbom_packages, spdx_blobs, blob_deletions, blob_uploads, "+
+				"vulnerabilityreports, vulnerability_findings, vulnerability_timeline, cve_metadata, watch_events",
+		)
+		suite.Require().NoError(err, "failed to truncate tables")
+
+		_, err = suite.db.Exec(ctx, "ALTER SEQUENCE resource_version_seq RESTART WITH 2")
+		suite.Require().NoError(err, "failed to restart resource version sequence")
+	case SQLiteBackend:
+		for _, table := range []string{"images", "sboms", "vulnerabilityreports", "watch_events"} {
+			_, err := suite.db.Exec(ctx, "DELETE FROM "+table)
+			suite.Require().NoError(err, "failed to truncate tables")
+		}
+
+		_, err := suite.db.Exec(ctx, "UPDATE resource_version_seq SET value = 1")
+		suite.Require().NoError(err, "failed to restart resource version sequence")
 	}
+
+	suite.store = newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		nil,
+		slog.Default(),
+	)
 }
 
 func (suite *storeTestSuite) TearDownTest() {
-	suite.broadcaster.Shutdown()
+	suite.store.destroy()
+}
+
+// requirePostgres skips the test on the backends not serving the resources computed
+// from the findings and packages tables.
+func (suite *storeTestSuite) requirePostgres() {
+	if suite.backend != PostgresBackend {
+		suite.T().Skipf("not supported by the %s backend", suite.backend)
+	}
 }
 
 func TestStoreTestSuite(t *testing.T) {
-	suite.Run(t, &storeTestSuite{})
+	suite.Run(t, &storeTestSuite{backend: PostgresBackend})
+}
+
+func TestSQLiteStoreTestSuite(t *testing.T) {
+	suite.Run(t, &storeTestSuite{backend: SQLiteBackend})
 }
 
 func (suite *storeTestSuite) TestCreate() {
@@ -105,8 +151,12 @@ func (suite *storeTestSuite) TestCreate() {
 	suite.Require().NoError(err)
 
 	suite.Equal(sbom, out)
-	suite.Equal("1", out.ResourceVersion)
+	suite.Equal("2", out.ResourceVersion)
+
+	err = suite.store.Create(context.Background(), key, sbom, out, 0)
+	suite.Require().Equal(storage.ErrResourceVersionSetOnCreate, err)
 
+	sbom.ResourceVersion = ""
 	err = suite.store.Create(context.Background(), key, sbom, out, 0)
 	suite.Require().Equal(storage.NewKeyExistsError(key, 0).Error(), err.Error())
 }
@@ -150,6 +200,7 @@ func (suite *storeTestSuite) TestDelete() {
 
 	for _, test := range tests {
 		suite.Run(test.name, func() {
+			sbom := sbom.DeepCopy()
 			err := suite.store.Create(context.Background(), key, sbom, &v1alpha1.SBOM{}, 0)
 			suite.Require().NoError(err)
 
@@ -180,15 +231,24 @@ func (suite *storeTestSuite) TestDelete() {
 
 func (suite *storeTestSuite) TestWatchEmptyResourceVersion() {
 	key := keyPrefix + "/default/test"
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	err := suite.store.Create(context.Background(), key, sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
 	opts := storage.ListOptions{ResourceVersion: ""}
 
 	watcher, err := suite.store.Watch(context.Background(), key, opts)
 	suite.Require().NoError(err)
 
-	suite.broadcaster.Shutdown()
-
-	events := collectEvents(watcher)
-	suite.Require().Empty(events)
+	events := collectEvents(watcher, 1)
+	suite.Require().Len(events, 1)
+	suite.Equal(watch.Added, events[0].Type)
+	suite.Equal(sbom, events[0].Object)
 }
 
 func (suite *storeTestSuite) TestWatchResourceVersionZero() {
@@ -221,55 +281,60 @@ func (suite *storeTestSuite) TestWatchResourceVersionZero() {
 	)
 	suite.Require().NoError(err)
 
-	suite.broadcaster.Shutdown()
+	deletedSBOM := sbom.DeepCopy()
+	deletedSBOM.ResourceVersion = "3"
 
-	events := collectEvents(watcher)
+	events := collectEvents(watcher, 2)
 	suite.Require().Len(events, 2)
 	suite.Equal(watch.Added, events[0].Type)
 	suite.Equal(sbom, events[0].Object)
 	suite.Equal(watch.Deleted, events[1].Type)
-	suite.Equal(sbom, events[1].Object)
+	suite.Equal(deletedSBOM, events[1].Object)
 }
 
 func (suite *storeTestSuite) TestWatchSpecificResourceVersion() {
 	key := keyPrefix + "/default"
-	sbom := &v1alpha1.SBOM{
+	sbom1 := &v1alpha1.SBOM{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "test",
+			Name:      "test1",
 			Namespace: "default",
 		},
 	}
-	suite.Require().NoError(suite.store.Create(context.Background(), key+"/test", sbom, &v1alpha1.SBOM{}, 0))
+	suite.Require().NoError(suite.store.Create(context.Background(), key+"/test1", sbom1, &v1alpha1.SBOM{}, 0))
+
+	sbom2 := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test2",
+			Namespace: "default",
+		},
+	}
+	suite.Require().NoError(suite.store.Create(context.Background(), key+"/test2", sbom2, &v1alpha1.SBOM{}, 0))
 
+	// Only the events following the resourceVersion of sbom1 are expected.
 	opts := storage.ListOptions{
-		ResourceVersion: "1",
+		ResourceVersion: sbom1.ResourceVersion,
 		Predicate:       matcher(labels.Everything(), fields.Everything()),
 	}
 
 	watcher, err := suite.store.Watch(context.Background(), key, opts)
 	suite.Require().NoError(err)
 
-	tryUpdate := func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
-		return input, ptr.To(uint64(0)), nil
-	}
 	updatedSBOM := &v1alpha1.SBOM{}
 	err = suite.store.GuaranteedUpdate(
 		context.Background(),
-		key+"/test",
+		key+"/test1",
 		updatedSBOM,
 		false,
 		&storage.Preconditions{},
-		tryUpdate,
+		updateLabels(map[string]string{"sbomscanner.kubewarden.io/test": "true"}),
 		nil,
 	)
 	suite.Require().NoError(err)
 
-	suite.broadcaster.Shutdown()
-
-	events := collectEvents(watcher)
+	events := collectEvents(watcher, 2)
 	suite.Require().Len(events, 2)
 	suite.Equal(watch.Added, events[0].Type)
-	suite.Equal(sbom, events[0].Object)
+	suite.Equal(sbom2, events[0].Object)
 	suite.Equal(watch.Modified, events[1].Type)
 	suite.Equal(updatedSBOM, events[1].Object)
 }
@@ -299,7 +364,7 @@ func (suite *storeTestSuite) TestWatchWithLabelSelector() {
 	suite.Require().NoError(err)
 
 	opts := storage.ListOptions{
-		ResourceVersion: "1",
+		ResourceVersion: "0",
 		Predicate: matcher(labels.SelectorFromSet(labels.Set{
 			"sbomscanner.kubewarden.io/test": "true",
 		}), fields.Everything()),
@@ -307,23 +372,382 @@ func (suite *storeTestSuite) TestWatchWithLabelSelector() {
 	watcher, err := suite.store.Watch(context.Background(), key, opts)
 	suite.Require().NoError(err)
 
-	suite.broadcaster.Shutdown()
+	sbom3 := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test3",
+			Namespace: "default",
+			Labels: map[string]string{
+				"sbomscanner.kubewarden.io/test": "true",
+			},
+		},
+	}
+	err = suite.store.Create(context.Background(), key+"/test3", sbom3, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
 
-	events := collectEvents(watcher)
+	events := collectEvents(watcher, 2)
+	suite.Require().Len(events, 2)
+	suite.Equal(watch.Added, events[0].Type)
+	suite.Equal(sbom1, events[0].Object)
+	suite.Equal(watch.Added, events[1].Type)
+	suite.Equal(sbom3, events[1].Object)
+}
+
+func (suite *storeTestSuite) TestWatchCompactedResourceVersion() {
+	key := keyPrefix + "/default/test"
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	err := suite.store.Create(context.Background(), key, sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	for _, value := range []string{"1", "2"} {
+		err = suite.store.GuaranteedUpdate(
+			context.Background(),
+			key,
+			&v1alpha1.SBOM{},
+			false,
+			&storage.Preconditions{},
+			updateLabels(map[string]string{"sbomscanner.kubewarden.io/test": value}),
+			nil,
+		)
+		suite.Require().NoError(err)
+	}
+
+	deleted, err := NewCompactor(suite.db, 0, slog.Default()).Compact(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), deleted)
+
+	suite.Equal(int64(3), suite.store.CompactRevision())
+	currentResourceVersion, err := suite.store.GetCurrentResourceVersion(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(uint64(4), currentResourceVersion)
+
+	_, err = suite.store.Watch(context.Background(), key, storage.ListOptions{ResourceVersion: "2"})
+	suite.Require().Error(err)
+	suite.True(apierrors.IsResourceExpired(err))
+
+	watcher, err := suite.store.Watch(context.Background(), key, storage.ListOptions{ResourceVersion: "3"})
+	suite.Require().NoError(err)
+
+	events := collectEvents(watcher, 1)
 	suite.Require().Len(events, 1)
+	suite.Equal(watch.Modified, events[0].Type)
+	suite.Equal("4", events[0].Object.(*v1alpha1.SBOM).ResourceVersion)
+}
+
+func (suite *storeTestSuite) TestWatchSlowWatcherIsTerminated() {
+	key := keyPrefix + "/default"
+	opts := storage.ListOptions{ResourceVersion: "0"}
+
+	slowWatcher, err := suite.store.Watch(context.Background(), key, opts)
+	suite.Require().NoError(err)
+	defer slowWatcher.Stop()
+
+	watcher, err := suite.store.Watch(context.Background(), key, opts)
+	suite.Require().NoError(err)
+
+	count := watcherBufferSize * 2
+	done := make(chan []watch.Event)
+	go func() {
+		done <- collectEvents(watcher, count)
+	}()
+
+	for i := range count {
+		name := fmt.Sprintf("test%d", i)
+		err = suite.store.Create(context.Background(), key+"/"+name, &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}, nil, 0)
+		suite.Require().NoError(err)
+	}
+
+	// The slow watcher does not stall the delivery to the other watchers.
+	events := <-done
+	suite.Require().Len(events, count)
+
+	var slowEvents []watch.Event
+	for event := range slowWatcher.ResultChan() {
+		slowEvents = append(slowEvents, event)
+	}
+	suite.Require().NotEmpty(slowEvents)
+	suite.Less(len(slowEvents), count)
+
+	lastEvent := slowEvents[len(slowEvents)-1]
+	suite.Require().Equal(watch.Error, lastEvent.Type)
+	status, ok := lastEvent.Object.(*metav1.Status)
+	suite.Require().True(ok)
+	suite.Equal(metav1.StatusReasonExpired, status.Reason)
+}
+
+func (suite *storeTestSuite) TestWatchReplayingWatcherIsNotTerminated() {
+	key := keyPrefix + "/default"
+	createSBOMs := func(from, to int) {
+		for i := from; i < to; i++ {
+			name := fmt.Sprintf("test%d", i)
+			err := suite.store.Create(context.Background(), key+"/"+name, &v1alpha1.SBOM{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+			}, nil, 0)
+			suite.Require().NoError(err)
+		}
+	}
+
+	// The watcher resumes after the first SBOM, so it replays the next ones from the log.
+	replayed := 10
+	createSBOMs(0, replayed)
+	watcher, err := suite.store.Watch(context.Background(), key, storage.ListOptions{ResourceVersion: "2"})
+	suite.Require().NoError(err)
+
+	// The watcher is blocked sending the first replayed event while more events than its buffer are dispatched.
+	count := replayed + watcherBufferSize*2
+	createSBOMs(replayed, count)
+	suite.Eventually(func() bool {
+		suite.store.broadcaster.mu.Lock()
+		defer suite.store.broadcaster.mu.Unlock()
+		return suite.store.broadcaster.resourceVersion >= uint64(count+1)
+	}, 10*time.Second, 10*time.Millisecond)
+
+	events := collectEvents(watcher, count-1)
+	suite.Require().Len(events, count-1)
+	for i, event := range events {
+		suite.Require().Equal(watch.Added, event.Type)
+		sbom, ok := event.Object.(*v1alpha1.SBOM)
+		suite.Require().True(ok)
+		suite.Equal(fmt.Sprintf("test%d", i+1), sbom.Name)
+		suite.Equal(strconv.Itoa(i+3), sbom.ResourceVersion)
+	}
+}
+
+func (suite *storeTestSuite) TestWatchAcrossReplicas() {
+	// The second store simulates another storage replica sharing the same database.
+	replica := newStore(
+		suite.db,
+		"sboms",
+		func() runtime.Object { return &v1alpha1.SBOM{} },
+		func() runtime.Object { return &v1alpha1.SBOMList{} },
+		nil,
+		slog.Default(),
+	)
+	defer replica.destroy()
+
+	key := keyPrefix + "/default/test"
+	watcher, err := replica.Watch(context.Background(), key, storage.ListOptions{ResourceVersion: "0"})
+	suite.Require().NoError(err)
+
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	err = suite.store.Create(context.Background(), key, sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	updatedSBOM := &v1alpha1.SBOM{}
+	err = suite.store.GuaranteedUpdate(
+		context.Background(),
+		key,
+		updatedSBOM,
+		false,
+		&storage.Preconditions{},
+		updateLabels(map[string]string{"sbomscanner.kubewarden.io/test": "true"}),
+		nil,
+	)
+	suite.Require().NoError(err)
+
+	err = suite.store.Delete(
+		context.Background(),
+		key,
+		&v1alpha1.SBOM{},
+		&storage.Preconditions{},
+		func(_ context.Context, _ runtime.Object) error { return nil },
+		nil,
+		storage.DeleteOptions{},
+	)
+	suite.Require().NoError(err)
+
+	deletedSBOM := updatedSBOM.DeepCopy()
+	deletedSBOM.ResourceVersion = "4"
+
+	events := collectEvents(watcher, 3)
+	suite.Require().Len(events, 3)
+	suite.Equal(watch.Added, events[0].Type)
+	suite.Equal(sbom, events[0].Object)
+	suite.Equal(watch.Modified, events[1].Type)
+	suite.Equal(updatedSBOM, events[1].Object)
+	suite.Equal(watch.Deleted, events[2].Type)
+	suite.Equal(deletedSBOM, events[2].Object)
+}
+
+func (suite *storeTestSuite) TestWatchSendInitialEvents() {
+	key := keyPrefix + "/default"
+	sbom1 := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1",
+			Namespace: "default",
+		},
+	}
+	suite.Require().NoError(suite.store.Create(context.Background(), key+"/test1", sbom1, &v1alpha1.SBOM{}, 0))
+
+	predicate := matcher(labels.Everything(), fields.Everything())
+	predicate.AllowWatchBookmarks = true
+	opts := storage.ListOptions{
+		ResourceVersion:   sbom1.ResourceVersion,
+		SendInitialEvents: ptr.To(true),
+		Recursive:         true,
+		Predicate:         predicate,
+	}
+
+	watcher, err := suite.store.Watch(context.Background(), key, opts)
+	suite.Require().NoError(err)
+
+	sbom2 := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test2",
+			Namespace: "default",
+		},
+	}
+	suite.Require().NoError(suite.store.Create(context.Background(), key+"/test2", sbom2, &v1alpha1.SBOM{}, 0))
+
+	// The initial events are followed by the bookmark marking their end, then by the changes.
+	events := collectEvents(watcher, 3)
+	suite.Require().Len(events, 3)
 	suite.Equal(watch.Added, events[0].Type)
 	suite.Equal(sbom1, events[0].Object)
+	suite.Equal(watch.Bookmark, events[1].Type)
+	suite.Equal(&v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			ResourceVersion: "2",
+			Annotations:     map[string]string{metav1.InitialEventsAnnotationKey: "true"},
+		},
+	}, events[1].Object)
+	suite.Equal(watch.Added, events[2].Type)
+	suite.Equal(sbom2, events[2].Object)
+
+	// The initial events cannot be served from a snapshot older than the requested resourceVersion.
+	opts.ResourceVersion = "10"
+	_, err = suite.store.Watch(context.Background(), key, opts)
+	suite.Require().Error(err)
+	suite.True(storage.IsTooLargeResourceVersion(err))
+}
+
+func (suite *storeTestSuite) TestWatchRequestProgress() {
+	imageStore := newStore(
+		suite.db,
+		"images",
+		func() runtime.Object { return &v1alpha1.Image{} },
+		func() runtime.Object { return &v1alpha1.ImageList{} },
+		nil,
+		slog.Default(),
+	)
+	defer imageStore.destroy()
+
+	key := keyPrefix + "/default"
+	predicate := matcher(labels.Everything(), fields.Everything())
+	predicate.AllowWatchBookmarks = true
+	opts := storage.ListOptions{
+		ResourceVersion:   "0",
+		SendInitialEvents: ptr.To(false),
+		Recursive:         true,
+		Predicate:         predicate,
+	}
+
+	watcher, err := suite.store.Watch(context.Background(), key, opts)
+	suite.Require().NoError(err)
+
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	suite.Require().NoError(suite.store.Create(context.Background(), key+"/test", sbom, &v1alpha1.SBOM{}, 0))
+
+	err = imageStore.Create(
+		context.Background(),
+		"/storage.sbomscanner.kubewarden.io/images/default/test",
+		&v1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+		},
+		&v1alpha1.Image{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.store.RequestWatchProgress(context.Background()))
+
+	// The bookmark carries the current resourceVersion, even if it was reached by writing another resource.
+	events := collectEvents(watcher, 2)
+	suite.Require().Len(events, 2)
+	suite.Equal(watch.Added, events[0].Type)
+	suite.Equal(sbom, events[0].Object)
+	suite.Equal(watch.Bookmark, events[1].Type)
+	suite.Equal(&v1alpha1.SBOM{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "3"}}, events[1].Object)
 }
 
-// collectEvents reads events from the watcher and returns them in a slice.
-func collectEvents(watcher watch.Interface) []watch.Event {
+func (suite *storeTestSuite) TestGetCurrentResourceVersion() {
+	currentResourceVersion, err := suite.store.GetCurrentResourceVersion(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(uint64(1), currentResourceVersion)
+
+	err = suite.store.Create(context.Background(), keyPrefix+"/default/test", &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	currentResourceVersion, err = suite.store.GetCurrentResourceVersion(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(uint64(2), currentResourceVersion)
+}
+
+// collectEvents reads the given number of events from the watcher and stops it.
+// It returns the events received before the timeout expires.
+func collectEvents(watcher watch.Interface, count int) []watch.Event {
+	defer watcher.Stop()
+
+	timeout := time.After(10 * time.Second)
+
 	var events []watch.Event
-	for event := range watcher.ResultChan() {
-		events = append(events, event)
+	for len(events) < count {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		case <-timeout:
+			return events
+		}
 	}
 	return events
 }
 
+// updateLabels returns an update function setting the labels of the object.
+func updateLabels(labels map[string]string) storage.UpdateFunc {
+	return func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+		sbom, ok := input.(*v1alpha1.SBOM)
+		if !ok {
+			return nil, nil, errors.New("input is not of type *v1alpha1.SBOM")
+		}
+		sbom.Labels = labels
+
+		return sbom, nil, nil
+	}
+}
+
 func (suite *storeTestSuite) TestGetList() {
 	key := keyPrefix + "/default"
 	sbom1 := v1alpha1.SBOM{
@@ -331,9 +755,13 @@ func (suite *storeTestSuite) TestGetList() {
 			Name:      "test1",
 			Namespace: "default",
 			Labels: map[string]string{
-				"sbomscanner.kubewarden.io/env": "test",
+				"sbomscanner.kubewarden.io/env":      "test",
+				"sbomscanner.kubewarden.io/priority": "1",
 			},
 		},
+		ImageMetadata: v1alpha1.ImageMetadata{
+			Registry: "registry1",
+		},
 	}
 	err := suite.store.Create(context.Background(), key+"/test1", &sbom1, nil, 0)
 	suite.Require().NoError(err)
@@ -343,9 +771,13 @@ func (suite *storeTestSuite) TestGetList() {
 			Name:      "test2",
 			Namespace: "default",
 			Labels: map[string]string{
-				"sbomscanner.kubewarden.io/env": "dev",
+				"sbomscanner.kubewarden.io/env":         "dev",
+				"sbomscanner.kubewarden.io/scanjob-uid": "scanjob-uid",
 			},
 		},
+		ImageMetadata: v1alpha1.ImageMetadata{
+			Registry: "registry2",
+		},
 	}
 	err = suite.store.Create(context.Background(), key+"/test2", &sbom2, nil, 0)
 	suite.Require().NoError(err)
@@ -357,6 +789,7 @@ func (suite *storeTestSuite) TestGetList() {
 			Labels: map[string]string{
 				"sbomscanner.kubewarden.io/env":      "prod",
 				"sbomscanner.kubewarden.io/critical": "true",
+				"sbomscanner.kubewarden.io/priority": "5",
 			},
 		},
 	}
@@ -417,6 +850,41 @@ func (suite *storeTestSuite) TestGetList() {
 				Predicate: matcher(mustParseLabelSelector("!sbomscanner.kubewarden.io/critical"), fields.Everything()),
 			},
 		},
+		{
+			name:          "list label selector (gt)",
+			expectedItems: []v1alpha1.SBOM{sbom3},
+			listOptions: storage.ListOptions{
+				Predicate: matcher(mustParseLabelSelector("sbomscanner.kubewarden.io/priority>2"), fields.Everything()),
+			},
+		},
+		{
+			name:          "list label selector (lt)",
+			expectedItems: []v1alpha1.SBOM{sbom1},
+			listOptions: storage.ListOptions{
+				Predicate: matcher(mustParseLabelSelector("sbomscanner.kubewarden.io/priority<2"), fields.Everything()),
+			},
+		},
+		{
+			name:          "list label selector (notin) matching the objects without the label",
+			expectedItems: []v1alpha1.SBOM{sbom2},
+			listOptions: storage.ListOptions{
+				Predicate: matcher(mustParseLabelSelector("sbomscanner.kubewarden.io/priority notin (1,5)"), fields.Everything()),
+			},
+		},
+		{
+			name:          "list label selector on a generated column (=)",
+			expectedItems: []v1alpha1.SBOM{sbom2},
+			listOptions: storage.ListOptions{
+				Predicate: matcher(mustParseLabelSelector("sbomscanner.kubewarden.io/scanjob-uid=scanjob-uid"), fields.Everything()),
+			},
+		},
+		{
+			name:          "list label selector on a generated column (!=)",
+			expectedItems: []v1alpha1.SBOM{sbom1, sbom3},
+			listOptions: storage.ListOptions{
+				Predicate: matcher(mustParseLabelSelector("sbomscanner.kubewarden.io/scanjob-uid!=scanjob-uid"), fields.Everything()),
+			},
+		},
 		{
 			name:          "list field selector (=)",
 			expectedItems: []v1alpha1.SBOM{sbom1},
@@ -431,6 +899,20 @@ func (suite *storeTestSuite) TestGetList() {
 				Predicate: matcher(labels.Everything(), mustParseFieldSelector("metadata.name!=test1")),
 			},
 		},
+		{
+			name:          "list field selector on a generated column (=)",
+			expectedItems: []v1alpha1.SBOM{sbom2},
+			listOptions: storage.ListOptions{
+				Predicate: matcher(labels.Everything(), mustParseFieldSelector("imageMetadata.registry=registry2")),
+			},
+		},
+		{
+			name:          "list field selector on a generated column (!=)",
+			expectedItems: []v1alpha1.SBOM{sbom1, sbom3},
+			listOptions: storage.ListOptions{
+				Predicate: matcher(labels.Everything(), mustParseFieldSelector("imageMetadata.registry!=registry2")),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -443,6 +925,250 @@ func (suite *storeTestSuite) TestGetList() {
 	}
 }
 
+func (suite *storeTestSuite) TestGetListSummarySelectors() {
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+	strategy := newVulnerabilityReportStrategy(runtime.NewScheme())
+
+	report1 := newTestVulnerabilityReport("test1")
+	report1.Report.Summary.Critical = 2
+	report2 := newTestVulnerabilityReport("test2")
+	report2.Report.Summary.Critical = 10
+	report3 := newTestVulnerabilityReport("test3")
+
+	for _, report := range []*v1alpha1.VulnerabilityReport{report1, report2, report3} {
+		strategy.PrepareForCreate(context.Background(), report)
+		err := vulnerabilityReportStore.Create(
+			context.Background(),
+			vulnerabilityReportKeyPrefix+"/default/"+report.Name,
+			report,
+			&v1alpha1.VulnerabilityReport{},
+			0,
+		)
+		suite.Require().NoError(err)
+	}
+
+	tests := []struct {
+		name          string
+		labelSelector string
+		fieldSelector string
+		expectedItems []*v1alpha1.VulnerabilityReport
+	}{
+		{
+			name:          "equal",
+			fieldSelector: "report.summary.critical=10",
+			expectedItems: []*v1alpha1.VulnerabilityReport{report2},
+		},
+		{
+			name:          "not equal",
+			fieldSelector: "report.summary.critical!=0",
+			expectedItems: []*v1alpha1.VulnerabilityReport{report1, report2},
+		},
+		{
+			name:          "combined with an imageMetadata field",
+			fieldSelector: "report.summary.critical!=0,imageMetadata.tag=test2",
+			expectedItems: []*v1alpha1.VulnerabilityReport{report2},
+		},
+		{
+			name:          "summary label greater than",
+			labelSelector: v1alpha1.LabelSummaryCriticalKey + ">5",
+			expectedItems: []*v1alpha1.VulnerabilityReport{report2},
+		},
+		{
+			name:          "summary label less than",
+			labelSelector: v1alpha1.LabelSummaryCriticalKey + "<5",
+			expectedItems: []*v1alpha1.VulnerabilityReport{report1, report3},
+		},
+		{
+			name:          "summary label combined with an imageMetadata field",
+			labelSelector: v1alpha1.LabelSummaryCriticalKey + ">0",
+			fieldSelector: "imageMetadata.tag=test1",
+			expectedItems: []*v1alpha1.VulnerabilityReport{report1},
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			predicate := matcher(mustParseLabelSelector(test.labelSelector), mustParseFieldSelector(test.fieldSelector))
+
+			list := &v1alpha1.VulnerabilityReportList{}
+			err := vulnerabilityReportStore.GetList(
+				context.Background(),
+				vulnerabilityReportKeyPrefix+"/default",
+				storage.ListOptions{Predicate: predicate},
+				list,
+			)
+			suite.Require().NoError(err)
+
+			names := make([]string, 0, len(list.Items))
+			for _, item := range list.Items {
+				names = append(names, item.Name)
+			}
+			var expectedNames []string
+			for _, report := range test.expectedItems {
+				expectedNames = append(expectedNames, report.Name)
+			}
+			suite.ElementsMatch(expectedNames, names)
+
+			// The watch filters match the same reports.
+			for _, report := range []*v1alpha1.VulnerabilityReport{report1, report2, report3} {
+				matches, err := predicate.Matches(report)
+				suite.Require().NoError(err)
+				suite.Equal(slices.Contains(expectedNames, report.Name), matches, report.Name)
+			}
+		})
+	}
+}
+
+func (suite *storeTestSuite) TestGetListPagination() {
+	var sboms []v1alpha1.SBOM
+	for _, namespacedName := range []types.NamespacedName{
+		{Namespace: "default", Name: "test1"},
+		{Namespace: "default", Name: "test2"},
+		{Namespace: "default", Name: "test3"},
+		{Namespace: "other", Name: "test1"},
+		{Namespace: "other", Name: "test2"},
+	} {
+		sbom := v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		err := suite.store.Create(
+			context.Background(),
+			keyPrefix+"/"+namespacedName.Namespace+"/"+namespacedName.Name,
+			&sbom,
+			nil,
+			0,
+		)
+		suite.Require().NoError(err)
+		sboms = append(sboms, sbom)
+	}
+
+	tests := []struct {
+		name                       string
+		key                        string
+		limit                      int64
+		expectedPages              [][]v1alpha1.SBOM
+		expectedRemainingItemCount []*int64
+	}{
+		{
+			name:                       "all namespaces",
+			key:                        keyPrefix,
+			limit:                      2,
+			expectedPages:              [][]v1alpha1.SBOM{sboms[0:2], sboms[2:4], sboms[4:5]},
+			expectedRemainingItemCount: []*int64{ptr.To(int64(3)), ptr.To(int64(1)), nil},
+		},
+		{
+			name:                       "single namespace",
+			key:                        keyPrefix + "/default",
+			limit:                      2,
+			expectedPages:              [][]v1alpha1.SBOM{sboms[0:2], sboms[2:3]},
+			expectedRemainingItemCount: []*int64{ptr.To(int64(1)), nil},
+		},
+		{
+			name:                       "limit matching the number of objects",
+			key:                        keyPrefix + "/other",
+			limit:                      2,
+			expectedPages:              [][]v1alpha1.SBOM{sboms[3:5]},
+			expectedRemainingItemCount: []*int64{nil},
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			var continueValue string
+			for i, expectedPage := range test.expectedPages {
+				sbomList := &v1alpha1.SBOMList{}
+				err := suite.store.GetList(context.Background(), test.key, storage.ListOptions{
+					Recursive: true,
+					Predicate: storage.SelectionPredicate{
+						Label:    labels.Everything(),
+						Field:    fields.Everything(),
+						Limit:    test.limit,
+						Continue: continueValue,
+					},
+				}, sbomList)
+				suite.Require().NoError(err)
+
+				suite.Equal(expectedPage, sbomList.Items)
+				suite.Equal("6", sbomList.ResourceVersion)
+				suite.Equal(test.expectedRemainingItemCount[i], sbomList.RemainingItemCount)
+
+				continueValue = sbomList.Continue
+			}
+			suite.Empty(continueValue)
+		})
+	}
+}
+
+func (suite *storeTestSuite) TestGetListPaginationCompacted() {
+	key := keyPrefix + "/default"
+	for _, name := range []string{"test1", "test2", "test3"} {
+		err := suite.store.Create(context.Background(), key+"/"+name, &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}, nil, 0)
+		suite.Require().NoError(err)
+	}
+
+	listOptions := func(continueValue string) storage.ListOptions {
+		return storage.ListOptions{
+			Recursive: true,
+			Predicate: storage.SelectionPredicate{
+				Label:    labels.Everything(),
+				Field:    fields.Everything(),
+				Limit:    1,
+				Continue: continueValue,
+			},
+		}
+	}
+
+	firstPage := &v1alpha1.SBOMList{}
+	err := suite.store.GetList(context.Background(), key, listOptions(""), firstPage)
+	suite.Require().NoError(err)
+	suite.Require().Len(firstPage.Items, 1)
+	suite.Equal("test1", firstPage.Items[0].Name)
+
+	// Write after the first page, so that the events following its resourceVersion are compacted.
+	for _, name := range []string{"test4", "test5"} {
+		err = suite.store.Create(context.Background(), key+"/"+name, &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}, nil, 0)
+		suite.Require().NoError(err)
+	}
+
+	_, err = NewCompactor(suite.db, 0, slog.Default()).Compact(context.Background())
+	suite.Require().NoError(err)
+
+	err = suite.store.GetList(context.Background(), key, listOptions(firstPage.Continue), &v1alpha1.SBOMList{})
+	suite.Require().Error(err)
+	suite.Require().True(apierrors.IsResourceExpired(err))
+
+	var statusErr *apierrors.StatusError
+	suite.Require().ErrorAs(err, &statusErr)
+	suite.Require().NotEmpty(statusErr.ErrStatus.ListMeta.Continue)
+
+	// The new token continues the list from the current resourceVersion.
+	secondPage := &v1alpha1.SBOMList{}
+	err = suite.store.GetList(
+		context.Background(),
+		key,
+		listOptions(statusErr.ErrStatus.ListMeta.Continue),
+		secondPage,
+	)
+	suite.Require().NoError(err)
+	suite.Require().Len(secondPage.Items, 1)
+	suite.Equal("test2", secondPage.Items[0].Name)
+	suite.Equal("6", secondPage.ResourceVersion)
+}
+
 func mustParseLabelSelector(selector string) labels.Selector {
 	labelSelector, err := labels.Parse(selector)
 	if err != nil {
@@ -500,7 +1226,7 @@ func (suite *storeTestSuite) TestGuaranteedUpdate() {
 					Name:            "test1",
 					Namespace:       "default",
 					UID:             "test1-uid",
-					ResourceVersion: "2",
+					ResourceVersion: "3",
 				},
 				SPDX: runtime.RawExtension{
 					Raw: []byte(`{"foo": "bar"}`),
@@ -549,6 +1275,33 @@ func (suite *storeTestSuite) TestGuaranteedUpdate() {
 			},
 			expectedError: storage.NewInternalError(errors.New("tryUpdate failed")),
 		},
+		{
+			name:          "tryUpdate failed with an optimistic lock conflict",
+			key:           keyPrefix + "/default/test4",
+			preconditions: &storage.Preconditions{},
+			tryUpdate: func(_ runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+				return nil, nil, apierrors.NewConflict(
+					v1alpha1.Resource("sboms"),
+					"test4",
+					errors.New(registry.OptimisticLockErrorMsg),
+				)
+			},
+			sbom: &v1alpha1.SBOM{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test4",
+					Namespace: "default",
+					UID:       "test4-uid",
+				},
+				SPDX: runtime.RawExtension{
+					Raw: []byte("{}"),
+				},
+			},
+			expectedError: apierrors.NewConflict(
+				v1alpha1.Resource("sboms"),
+				"test4",
+				errors.New(registry.OptimisticLockErrorMsg),
+			),
+		},
 		{
 			name:          "not found",
 			key:           keyPrefix + "/default/notfound",
@@ -609,6 +1362,11 @@ func (suite *storeTestSuite) TestGuaranteedUpdate() {
 					// Verify the object was updated in the store.
 					err = suite.store.Get(context.Background(), test.key, storage.GetOptions{}, currentSBOM)
 					suite.Require().NoError(err)
+					// The whitespace of the stored JSON documents depends on the database.
+					if test.expectedUpdatedSBOM.SPDX.Raw != nil {
+						suite.JSONEq(string(test.expectedUpdatedSBOM.SPDX.Raw), string(currentSBOM.SPDX.Raw))
+						currentSBOM.SPDX.Raw = test.expectedUpdatedSBOM.SPDX.Raw
+					}
 					suite.Equal(test.expectedUpdatedSBOM, currentSBOM)
 				}
 			}
@@ -616,6 +1374,177 @@ func (suite *storeTestSuite) TestGuaranteedUpdate() {
 	}
 }
 
+// TestGuaranteedUpdateConcurrentDelete deletes an object while it is being updated.
+// The writers lock the writes before the rows, so the deletion waits for the update instead of deadlocking.
+func (suite *storeTestSuite) TestGuaranteedUpdateConcurrentDelete() {
+	key := keyPrefix + "/default/test"
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	err := suite.store.Create(context.Background(), key, sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	attempts := 0
+	err = suite.store.GuaranteedUpdate(
+		context.Background(),
+		key,
+		&v1alpha1.SBOM{},
+		false,
+		&storage.Preconditions{},
+		func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+			attempts++
+			// No lock is held while the update is computed, the object is deleted before it is written.
+			err := suite.store.Delete(
+				context.Background(),
+				key,
+				&v1alpha1.SBOM{},
+				&storage.Preconditions{},
+				storage.ValidateAllObjectFunc,
+				nil,
+				storage.DeleteOptions{},
+			)
+			suite.Require().NoError(err)
+
+			sbom, ok := input.(*v1alpha1.SBOM)
+			suite.Require().True(ok)
+			sbom.Labels = map[string]string{"updated": "true"}
+
+			return sbom, nil, nil
+		},
+		nil,
+	)
+	suite.True(storage.IsNotFound(err))
+	suite.Equal(1, attempts)
+
+	err = suite.store.Get(context.Background(), key, storage.GetOptions{}, &v1alpha1.SBOM{})
+	suite.True(storage.IsNotFound(err))
+}
+
+func (suite *storeTestSuite) TestGuaranteedUpdateConcurrentUpdate() {
+	key := keyPrefix + "/default/test"
+	sbom := &v1alpha1.SBOM{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	err := suite.store.Create(context.Background(), key, sbom, &v1alpha1.SBOM{}, 0)
+	suite.Require().NoError(err)
+
+	setLabel := func(name string) storage.UpdateFunc {
+		return func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+			sbom, ok := input.(*v1alpha1.SBOM)
+			suite.Require().True(ok)
+			if sbom.Labels == nil {
+				sbom.Labels = map[string]string{}
+			}
+			sbom.Labels[name] = "true"
+
+			return sbom, nil, nil
+		}
+	}
+
+	attempts := 0
+	updated := &v1alpha1.SBOM{}
+	err = suite.store.GuaranteedUpdate(
+		context.Background(),
+		key,
+		updated,
+		false,
+		&storage.Preconditions{},
+		func(input runtime.Object, meta storage.ResponseMeta) (runtime.Object, *uint64, error) {
+			attempts++
+			// The object is updated once before it is written, the update is retried on the current object.
+			if attempts == 1 {
+				err := suite.store.GuaranteedUpdate(
+					context.Background(), key, &v1alpha1.SBOM{}, false, &storage.Preconditions{}, setLabel("first"), nil,
+				)
+				suite.Require().NoError(err)
+			}
+
+			return setLabel("second")(input, meta)
+		},
+		nil,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(2, attempts)
+	suite.Equal(map[string]string{"first": "true", "second": "true"}, updated.Labels)
+
+	current := &v1alpha1.SBOM{}
+	err = suite.store.Get(context.Background(), key, storage.GetOptions{}, current)
+	suite.Require().NoError(err)
+	suite.Equal(updated, current)
+}
+
+func (suite *storeTestSuite) TestGuaranteedUpdateParallelDelete() {
+	suite.requirePostgres()
+
+	const objects = 20
+	for i := range objects {
+		err := suite.store.Create(context.Background(), fmt.Sprintf("%s/default/test%d", keyPrefix, i), &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test%d", i),
+				Namespace: "default",
+			},
+		}, &v1alpha1.SBOM{}, 0)
+		suite.Require().NoError(err)
+	}
+
+	// The updates and the deletions of the same objects run in parallel, none of them can deadlock.
+	errs := make(chan error, 2*objects)
+	var wg sync.WaitGroup
+	for i := range objects {
+		key := fmt.Sprintf("%s/default/test%d", keyPrefix, i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			err := suite.store.GuaranteedUpdate(
+				context.Background(),
+				key,
+				&v1alpha1.SBOM{},
+				false,
+				&storage.Preconditions{},
+				func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					sbom, ok := input.(*v1alpha1.SBOM)
+					if !ok {
+						return nil, nil, fmt.Errorf("unexpected object type: %T", input)
+					}
+					sbom.Labels = map[string]string{"updated": "true"}
+
+					return sbom, nil, nil
+				},
+				nil,
+			)
+			if storage.IsNotFound(err) {
+				err = nil
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- suite.store.Delete(
+				context.Background(),
+				key,
+				&v1alpha1.SBOM{},
+				&storage.Preconditions{},
+				storage.ValidateAllObjectFunc,
+				nil,
+				storage.DeleteOptions{},
+			)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		suite.Require().NoError(err)
+	}
+	suite.Equal(0, suite.countRows("sboms"))
+}
+
 func (suite *storeTestSuite) TestCount() {
 	err := suite.store.Create(context.Background(), keyPrefix+"/default/test1", &v1alpha1.SBOM{
 		ObjectMeta: metav1.ObjectMeta{
@@ -667,3 +1596,28 @@ func (suite *storeTestSuite) TestCount() {
 		})
 	}
 }
+
+func (suite *storeTestSuite) TestStats() {
+	stats, err := suite.store.Stats(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(storage.Stats{}, stats)
+
+	for _, name := range []string{"test1", "test2"} {
+		err = suite.store.Create(context.Background(), keyPrefix+"/default/"+name, &v1alpha1.SBOM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}, &v1alpha1.SBOM{}, 0)
+		suite.Require().NoError(err)
+	}
+
+	stats, err = suite.store.Stats(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), stats.ObjectCount)
+	suite.Positive(stats.EstimatedAverageObjectSizeBytes)
+}
+
+func (suite *storeTestSuite) TestReadinessCheck() {
+	suite.Require().NoError(suite.store.ReadinessCheck())
+}
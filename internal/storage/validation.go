@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// DefaultMaxSPDXSize is the default maximum size in bytes of the SPDX document of an SBOM.
+const DefaultMaxSPDXSize = 3 * 1024 * 1024
+
+var (
+	// digestRegexp matches the sha256 digests, the only algorithm used by the scanner.
+	digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	// platformRegexp matches the platforms formatted as <os>/<architecture>[/<variant>][:<os version>].
+	platformRegexp = regexp.MustCompile(`^[a-z0-9_-]+/[a-z0-9_-]+(/[a-z0-9_.-]+)?(:[a-zA-Z0-9_.-]+)?$`)
+)
+
+// spdxDocument contains the fields of an SPDX document checked by the validation.
+type spdxDocument struct {
+	SPDXVersion string            `json:"spdxVersion"`
+	Packages    []json.RawMessage `json:"packages"`
+}
+
+// unexpectedTypeError returns the validation error of a strategy given an object of an unexpected type.
+func unexpectedTypeError(obj runtime.Object) field.ErrorList {
+	return field.ErrorList{field.InternalError(nil, fmt.Errorf("unexpected type %T", obj))}
+}
+
+// validateImageMetadata validates the metadata of the image an object was generated from.
+func validateImageMetadata(metadata v1alpha1.ImageMetadata, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if metadata.Registry == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("registry"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(metadata.Registry) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("registry"), metadata.Registry, msg))
+		}
+	}
+	if metadata.RegistryURI == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("registryURI"), ""))
+	}
+	if metadata.Repository == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("repository"), ""))
+	}
+	if metadata.Tag == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("tag"), ""))
+	}
+	if metadata.Platform != "" && !platformRegexp.MatchString(metadata.Platform) {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("platform"), metadata.Platform, "must be formatted as <os>/<architecture>[/<variant>][:<os version>]",
+		))
+	}
+	allErrs = append(allErrs, validateDigest(metadata.Digest, fldPath.Child("digest"))...)
+
+	return allErrs
+}
+
+// validateImageMetadataUpdate checks that the image metadata are not changed,
+// as the object always describes the image it was generated from.
+func validateImageMetadataUpdate(newMetadata, oldMetadata v1alpha1.ImageMetadata, fldPath *field.Path) field.ErrorList {
+	return apimachineryvalidation.ValidateImmutableField(newMetadata, oldMetadata, fldPath)
+}
+
+// validateDigest validates a required sha256 digest.
+func validateDigest(digest string, fldPath *field.Path) field.ErrorList {
+	if digest == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	if !digestRegexp.MatchString(digest) {
+		return field.ErrorList{field.Invalid(fldPath, digest, "must be a sha256 digest formatted as sha256:<64 hex characters>")}
+	}
+
+	return nil
+}
+
+// validateSPDX validates that the SPDX document is a JSON object no larger than maxSize bytes.
+func validateSPDX(spdx runtime.RawExtension, maxSize int, fldPath *field.Path) field.ErrorList {
+	if len(spdx.Raw) == 0 {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	if len(spdx.Raw) > maxSize {
+		return field.ErrorList{field.TooLong(fldPath, "", maxSize)}
+	}
+
+	var document spdxDocument
+	if err := json.Unmarshal(spdx.Raw, &document); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{}, fmt.Sprintf("must be an SPDX document in JSON format: %v", err))}
+	}
+
+	return nil
+}
+
+// spdxWarnings returns warnings for the SPDX documents the packages cannot be indexed from.
+// The document must be valid.
+func spdxWarnings(spdx runtime.RawExtension, fldPath *field.Path) []string {
+	var document spdxDocument
+	if err := json.Unmarshal(spdx.Raw, &document); err != nil {
+		return nil
+	}
+
+	var warnings []string
+	if !strings.HasPrefix(document.SPDXVersion, "SPDX-2.") {
+		warnings = append(warnings, fmt.Sprintf("%s: spdxVersion %q is not SPDX 2, its packages may not be indexed", fldPath, document.SPDXVersion))
+	}
+	if len(document.Packages) == 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: the document has no packages", fldPath))
+	}
+
+	return warnings
+}
+
+// reportSummaryWarnings returns a warning if the summary of the report does not count its vulnerabilities.
+func reportSummaryWarnings(report v1alpha1.Report, fldPath *field.Path) []string {
+	var vulnerabilities, suppressed int
+	for _, result := range report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			if vulnerability.Suppressed {
+				suppressed++
+			} else {
+				vulnerabilities++
+			}
+		}
+	}
+
+	summary := report.Summary
+	if summary.Critical+summary.High+summary.Medium+summary.Low+summary.Unknown != vulnerabilities ||
+		summary.Suppressed != suppressed {
+		return []string{fmt.Sprintf("%s: the summary does not match the vulnerabilities of the results", fldPath)}
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func newValidImageMetadata() v1alpha1.ImageMetadata {
+	return v1alpha1.ImageMetadata{
+		Registry:    "registry",
+		RegistryURI: "registry-1.docker.io:5000",
+		Repository:  "kubewarden/sbomscanner",
+		Tag:         "latest",
+		Platform:    "linux/arm64/v8",
+		Digest:      testDigest,
+	}
+}
+
+func newValidSBOM() *v1alpha1.SBOM {
+	return &v1alpha1.SBOM{
+		ObjectMeta:    metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		ImageMetadata: newValidImageMetadata(),
+		SPDX: runtime.RawExtension{
+			Raw: []byte(`{"spdxVersion":"SPDX-2.3","packages":[{"name":"musl"}]}`),
+		},
+	}
+}
+
+// errorFields returns the fields of the errors, in order.
+func errorFields(errs field.ErrorList) []string {
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+
+	return fields
+}
+
+func TestValidateImageMetadata(t *testing.T) {
+	tests := []struct {
+		name           string
+		mutate         func(metadata *v1alpha1.ImageMetadata)
+		expectedFields []string
+	}{
+		{
+			name:           "valid",
+			mutate:         func(_ *v1alpha1.ImageMetadata) {},
+			expectedFields: []string{},
+		},
+		{
+			name:           "platform without variant and with os version",
+			mutate:         func(metadata *v1alpha1.ImageMetadata) { metadata.Platform = "windows/amd64:10.0.17763.1234" },
+			expectedFields: []string{},
+		},
+		{
+			name:           "no platform",
+			mutate:         func(metadata *v1alpha1.ImageMetadata) { metadata.Platform = "" },
+			expectedFields: []string{},
+		},
+		{
+			name: "missing required fields",
+			mutate: func(metadata *v1alpha1.ImageMetadata) {
+				*metadata = v1alpha1.ImageMetadata{}
+			},
+			expectedFields: []string{
+				"imageMetadata.registry",
+				"imageMetadata.registryURI",
+				"imageMetadata.repository",
+				"imageMetadata.tag",
+				"imageMetadata.digest",
+			},
+		},
+		{
+			name:           "invalid registry name",
+			mutate:         func(metadata *v1alpha1.ImageMetadata) { metadata.Registry = "Registry_1" },
+			expectedFields: []string{"imageMetadata.registry"},
+		},
+		{
+			name:           "invalid platform",
+			mutate:         func(metadata *v1alpha1.ImageMetadata) { metadata.Platform = "linux/arm64/v8/extra" },
+			expectedFields: []string{"imageMetadata.platform"},
+		},
+		{
+			name:           "digest of another algorithm",
+			mutate:         func(metadata *v1alpha1.ImageMetadata) { metadata.Digest = "sha512:" + strings.Repeat("a", 128) },
+			expectedFields: []string{"imageMetadata.digest"},
+		},
+		{
+			name:           "truncated digest",
+			mutate:         func(metadata *v1alpha1.ImageMetadata) { metadata.Digest = testDigest[:20] },
+			expectedFields: []string{"imageMetadata.digest"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata := newValidImageMetadata()
+			test.mutate(&metadata)
+
+			errs := validateImageMetadata(metadata, field.NewPath("imageMetadata"))
+			assert.Equal(t, test.expectedFields, errorFields(errs))
+		})
+	}
+}
+
+func TestSBOMStrategyValidate(t *testing.T) {
+	strategy := newSBOMStrategy(runtime.NewScheme(), 128)
+
+	tests := []struct {
+		name           string
+		spdx           string
+		expectedFields []string
+		expectedType   field.ErrorType
+	}{
+		{
+			name:           "valid",
+			spdx:           `{"spdxVersion":"SPDX-2.3","packages":[]}`,
+			expectedFields: []string{},
+		},
+		{
+			name:           "missing",
+			spdx:           "",
+			expectedFields: []string{"spdx"},
+			expectedType:   field.ErrorTypeRequired,
+		},
+		{
+			name:           "not a JSON object",
+			spdx:           `["SPDX-2.3"]`,
+			expectedFields: []string{"spdx"},
+			expectedType:   field.ErrorTypeInvalid,
+		},
+		{
+			name:           "too large",
+			spdx:           `{"spdxVersion":"SPDX-2.3","name":"` + strings.Repeat("a", 128) + `"}`,
+			expectedFields: []string{"spdx"},
+			expectedType:   field.ErrorTypeTooLong,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sbom := newValidSBOM()
+			sbom.SPDX = runtime.RawExtension{Raw: []byte(test.spdx)}
+
+			errs := strategy.Validate(context.Background(), sbom)
+			require.Equal(t, test.expectedFields, errorFields(errs))
+			if len(errs) > 0 {
+				assert.Equal(t, test.expectedType, errs[0].Type)
+			}
+		})
+	}
+}
+
+func TestSBOMStrategyWarningsOnCreate(t *testing.T) {
+	strategy := newSBOMStrategy(runtime.NewScheme(), DefaultMaxSPDXSize)
+
+	sbom := newValidSBOM()
+	assert.Empty(t, strategy.WarningsOnCreate(context.Background(), sbom))
+
+	sbom.SPDX = runtime.RawExtension{Raw: []byte(`{"spdxVersion":"SPDX-3.0"}`)}
+	assert.Equal(t, []string{
+		`spdx: spdxVersion "SPDX-3.0" is not SPDX 2, its packages may not be indexed`,
+		"spdx: the document has no packages",
+	}, strategy.WarningsOnCreate(context.Background(), sbom))
+}
+
+func TestImageMetadataImmutable(t *testing.T) {
+	sbomStrategy := newSBOMStrategy(runtime.NewScheme(), DefaultMaxSPDXSize)
+	oldSBOM := newValidSBOM()
+	newSBOM := newValidSBOM()
+	newSBOM.Labels = map[string]string{"updated": "true"}
+	assert.Empty(t, sbomStrategy.ValidateUpdate(context.Background(), newSBOM, oldSBOM))
+
+	newSBOM.ImageMetadata.Tag = "other"
+	errs := sbomStrategy.ValidateUpdate(context.Background(), newSBOM, oldSBOM)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "imageMetadata", errs[0].Field)
+	assert.Equal(t, field.ErrorTypeInvalid, errs[0].Type)
+
+	reportStrategy := newVulnerabilityReportStrategy(runtime.NewScheme())
+	oldReport := &v1alpha1.VulnerabilityReport{ImageMetadata: newValidImageMetadata()}
+	newReport := &v1alpha1.VulnerabilityReport{ImageMetadata: newValidImageMetadata()}
+	newReport.ImageMetadata.Digest = "sha256:" + strings.Repeat("f", 64)
+	assert.Equal(t, []string{"imageMetadata"}, errorFields(reportStrategy.ValidateUpdate(context.Background(), newReport, oldReport)))
+
+	imageStrategy := newImageStrategy(runtime.NewScheme())
+	oldImage := &v1alpha1.Image{ImageMetadata: newValidImageMetadata()}
+	newImage := &v1alpha1.Image{ImageMetadata: newValidImageMetadata()}
+	newImage.Platform = "linux/amd64"
+	assert.Equal(t, []string{"imageMetadata"}, errorFields(imageStrategy.ValidateUpdate(context.Background(), newImage, oldImage)))
+}
+
+func TestImageStrategyValidateLayers(t *testing.T) {
+	strategy := newImageStrategy(runtime.NewScheme())
+
+	image := &v1alpha1.Image{
+		ImageMetadata: newValidImageMetadata(),
+		Layers: []v1alpha1.ImageLayer{
+			{Digest: testDigest, DiffID: testDigest},
+			{Digest: "latest", DiffID: ""},
+		},
+	}
+
+	assert.Equal(t, []string{"layers[1].digest", "layers[1].diffID"}, errorFields(strategy.Validate(context.Background(), image)))
+	assert.Empty(t, strategy.WarningsOnCreate(context.Background(), image))
+
+	image.Layers = nil
+	assert.Equal(t, []string{"layers: the image has no layers"}, strategy.WarningsOnCreate(context.Background(), image))
+}
+
+func TestVulnerabilityReportStrategyWarningsOnCreate(t *testing.T) {
+	strategy := newVulnerabilityReportStrategy(runtime.NewScheme())
+
+	report := &v1alpha1.VulnerabilityReport{
+		ImageMetadata: newValidImageMetadata(),
+		Report: v1alpha1.Report{
+			Summary: v1alpha1.Summary{High: 1, Suppressed: 1},
+			Results: []v1alpha1.Result{
+				{
+					Vulnerabilities: []v1alpha1.Vulnerability{
+						{CVE: "CVE-2025-0001", Severity: "HIGH"},
+						{CVE: "CVE-2025-0002", Severity: "LOW", Suppressed: true},
+					},
+				},
+			},
+		},
+	}
+	assert.Empty(t, strategy.WarningsOnCreate(context.Background(), report))
+
+	report.Report.Summary.Critical = 3
+	assert.Equal(t, []string{
+		"report: the summary does not match the vulnerabilities of the results",
+	}, strategy.WarningsOnCreate(context.Background(), report))
+}
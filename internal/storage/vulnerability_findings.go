@@ -0,0 +1,476 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// objectHooks customize how the objects of a store are persisted.
+// They allow a store to keep parts of the objects in relational tables, outside of the object column.
+type objectHooks interface {
+	// strip returns a copy of the object without the parts persisted by the hooks.
+	// The returned object is stored in the object column.
+	strip(obj runtime.Object) (runtime.Object, error)
+	// prepare stores the parts of the object that can be written before the write transaction,
+	// such as the blobs uploaded to a blob store, so that the writers do not wait on them.
+	// It is called without holding any lock, and what it stores must be reclaimed if the write is not committed.
+	prepare(ctx context.Context, q querier, obj runtime.Object) error
+	// persist stores the parts of the object removed by strip.
+	// It is called in the same transaction, after the object row has been written,
+	// and may complete the object with the fields computed by the database, such as the timestamps.
+	persist(ctx context.Context, tx transaction, name, namespace string, obj runtime.Object) error
+	// hydrate restores the parts of the objects removed by strip.
+	hydrate(ctx context.Context, q querier, objs []runtime.Object) error
+}
+
+// eventHooks customize how the objects of the watch events of a store are persisted.
+// They allow the events to leave out the parts of the objects shared with the other objects,
+// which are kept in relational tables and would otherwise be repeated in every event.
+type eventHooks interface {
+	// stripEvent returns a copy of the object without the shared parts.
+	// The returned object is stored in the object column of the watch_events table.
+	stripEvent(obj runtime.Object) (runtime.Object, error)
+	// hydrateEvents restores the parts of the objects of the events removed by stripEvent.
+	hydrateEvents(ctx context.Context, q querier, objs []runtime.Object) error
+}
+
+// hookKey identifies an object in the tables of the hooks.
+type hookKey struct {
+	name      string
+	namespace string
+}
+
+// chainedHooks persist different parts of the objects, each with its own hooks.
+type chainedHooks []objectHooks
+
+func (c chainedHooks) strip(obj runtime.Object) (runtime.Object, error) {
+	var err error
+	for _, hooks := range c {
+		if obj, err = hooks.strip(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, nil
+}
+
+func (c chainedHooks) prepare(ctx context.Context, q querier, obj runtime.Object) error {
+	for _, hooks := range c {
+		if err := hooks.prepare(ctx, q, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c chainedHooks) persist(ctx context.Context, tx transaction, name, namespace string, obj runtime.Object) error {
+	for _, hooks := range c {
+		if err := hooks.persist(ctx, tx, name, namespace, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c chainedHooks) hydrate(ctx context.Context, q querier, objs []runtime.Object) error {
+	for _, hooks := range c {
+		if err := hooks.hydrate(ctx, q, objs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var (
+	_ objectHooks = vulnerabilityFindingsHooks{}
+	_ eventHooks  = vulnerabilityFindingsHooks{}
+)
+
+// vulnerabilityReportHooks returns the hooks of the VulnerabilityReports stored in the database.
+// The findings tables only exist on PostgreSQL, SQLite keeps the whole reports in the object column.
+func vulnerabilityReportHooks(db Database) objectHooks {
+	if db.Backend() != PostgresBackend {
+		return nil
+	}
+
+	return vulnerabilityFindingsHooks{}
+}
+
+// vulnerabilityFindingsHooks store the vulnerabilities of the VulnerabilityReports in the
+// vulnerability_findings table, and the metadata of their CVEs in the cve_metadata table,
+// which is shared by all the reports.
+// They also record the vulnerabilities in the timeline of the image digest, which sets their first seen time.
+// The watch events keep the findings of the reports, as they were written, without the metadata of their CVEs.
+type vulnerabilityFindingsHooks struct{}
+
+func (vulnerabilityFindingsHooks) strip(obj runtime.Object) (runtime.Object, error) {
+	report, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	stripped := report.DeepCopy()
+	for i := range stripped.Report.Results {
+		// Keep the difference between a nil and an empty list of vulnerabilities.
+		if stripped.Report.Results[i].Vulnerabilities != nil {
+			stripped.Report.Results[i].Vulnerabilities = []v1alpha1.Vulnerability{}
+		}
+	}
+
+	return stripped, nil
+}
+
+func (vulnerabilityFindingsHooks) prepare(_ context.Context, _ querier, _ runtime.Object) error {
+	return nil
+}
+
+//nolint:funlen // The columns of the findings are listed explicitly.
+func (vulnerabilityFindingsHooks) persist(
+	ctx context.Context,
+	tx transaction,
+	name, namespace string,
+	obj runtime.Object,
+) error {
+	report, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	if _, err := tx.Exec(
+		ctx,
+		"DELETE FROM vulnerability_findings WHERE report_name = $1 AND report_namespace = $2",
+		name, namespace,
+	); err != nil {
+		return fmt.Errorf("failed to delete vulnerability findings: %w", err)
+	}
+
+	var ids, titles, descriptions []string
+	var references, cvss [][]byte
+	seen := map[string]bool{}
+	var findings [][]any
+	for resultIndex, result := range report.Report.Results {
+		for vulnerabilityIndex, vulnerability := range result.Vulnerabilities {
+			if !seen[vulnerability.CVE] {
+				seen[vulnerability.CVE] = true
+
+				referencesBytes, err := marshalNullable(len(vulnerability.References) > 0, vulnerability.References)
+				if err != nil {
+					return err
+				}
+				cvssBytes, err := marshalNullable(len(vulnerability.CVSS) > 0, vulnerability.CVSS)
+				if err != nil {
+					return err
+				}
+
+				ids = append(ids, vulnerability.CVE)
+				titles = append(titles, vulnerability.Title)
+				descriptions = append(descriptions, vulnerability.Description)
+				references = append(references, referencesBytes)
+				cvss = append(cvss, cvssBytes)
+			}
+
+			vexStatus, err := marshalNullable(vulnerability.VEXStatus != nil, vulnerability.VEXStatus)
+			if err != nil {
+				return err
+			}
+
+			findings = append(findings, []any{
+				name,
+				namespace,
+				resultIndex,
+				vulnerabilityIndex,
+				vulnerability.CVE,
+				vulnerability.PackageName,
+				vulnerability.PackagePath,
+				vulnerability.PURL,
+				vulnerability.InstalledVersion,
+				vulnerability.FixedVersions,
+				vulnerability.DiffID,
+				vulnerability.Severity,
+				vulnerability.Suppressed,
+				vexStatus,
+			})
+		}
+	}
+
+	if len(findings) == 0 {
+		return updateVulnerabilityTimeline(ctx, tx, namespace, report)
+	}
+
+	// The metadata is only rewritten when it changed, to avoid bloating the table
+	// with dead rows when the same CVEs are reported by many images.
+	if _, err := tx.Exec(ctx, `
+INSERT INTO cve_metadata (id, title, description, "references", cvss)
+SELECT * FROM unnest($1::TEXT[], $2::TEXT[], $3::TEXT[], $4::JSONB[], $5::JSONB[])
+ORDER BY 1
+ON CONFLICT (id) DO UPDATE SET
+    title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    "references" = EXCLUDED."references",
+    cvss = EXCLUDED.cvss
+WHERE (cve_metadata.title, cve_metadata.description, cve_metadata."references", cve_metadata.cvss)
+    IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.description, EXCLUDED."references", EXCLUDED.cvss)`,
+		ids, titles, descriptions, references, cvss,
+	); err != nil {
+		return fmt.Errorf("failed to upsert CVE metadata: %w", err)
+	}
+
+	if err := tx.copyFrom(
+		ctx,
+		"vulnerability_findings",
+		[]string{
+			"report_name",
+			"report_namespace",
+			"result_index",
+			"vulnerability_index",
+			"cve",
+			"package_name",
+			"package_path",
+			"purl",
+			"installed_version",
+			"fixed_versions",
+			"diff_id",
+			"severity",
+			"suppressed",
+			"vex_status",
+		},
+		findings,
+	); err != nil {
+		return fmt.Errorf("failed to insert vulnerability findings: %w", err)
+	}
+
+	return updateVulnerabilityTimeline(ctx, tx, namespace, report)
+}
+
+func (vulnerabilityFindingsHooks) hydrate(ctx context.Context, q querier, objs []runtime.Object) error {
+	if len(objs) == 0 {
+		return nil
+	}
+
+	reports := make(map[hookKey]*v1alpha1.VulnerabilityReport, len(objs))
+	names := make([]string, 0, len(objs))
+	namespaces := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		report, ok := obj.(*v1alpha1.VulnerabilityReport)
+		if !ok {
+			return fmt.Errorf("unexpected object type: %T", obj)
+		}
+		reports[hookKey{name: report.Name, namespace: report.Namespace}] = report
+		names = append(names, report.Name)
+		namespaces = append(namespaces, report.Namespace)
+	}
+
+	rows, err := q.Query(ctx, `
+SELECT
+    f.report_name, f.report_namespace, f.result_index,
+    f.cve, c.title, f.package_name, f.package_path, f.purl, f.installed_version, f.fixed_versions,
+    f.diff_id, c.description, f.severity, c."references", c.cvss, f.suppressed, f.vex_status, t.first_seen
+FROM vulnerability_findings f
+JOIN cve_metadata c ON c.id = f.cve
+JOIN vulnerabilityreports r ON r.name = f.report_name AND r.namespace = f.report_namespace
+LEFT JOIN vulnerability_timeline t ON t.namespace = r.namespace AND t.digest = r.digest
+    AND t.cve = f.cve AND t.package_name = f.package_name AND t.purl = f.purl
+WHERE (f.report_name, f.report_namespace) IN (SELECT * FROM unnest($1::TEXT[], $2::TEXT[]))
+ORDER BY f.report_namespace, f.report_name, f.result_index, f.vulnerability_index`,
+		names, namespaces,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query vulnerability findings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key hookKey
+		var resultIndex int
+		var vulnerability v1alpha1.Vulnerability
+		var references, cvss, vexStatus []byte
+		var firstSeen *time.Time
+		if err = rows.Scan(
+			&key.name,
+			&key.namespace,
+			&resultIndex,
+			&vulnerability.CVE,
+			&vulnerability.Title,
+			&vulnerability.PackageName,
+			&vulnerability.PackagePath,
+			&vulnerability.PURL,
+			&vulnerability.InstalledVersion,
+			&vulnerability.FixedVersions,
+			&vulnerability.DiffID,
+			&vulnerability.Description,
+			&vulnerability.Severity,
+			&references,
+			&cvss,
+			&vulnerability.Suppressed,
+			&vexStatus,
+			&firstSeen,
+		); err != nil {
+			return fmt.Errorf("failed to scan vulnerability finding: %w", err)
+		}
+		if firstSeen != nil {
+			t := metav1.NewTime(firstSeen.Local())
+			vulnerability.FirstSeen = &t
+		}
+
+		if err = unmarshalNullable(references, &vulnerability.References); err != nil {
+			return err
+		}
+		if err = unmarshalNullable(cvss, &vulnerability.CVSS); err != nil {
+			return err
+		}
+		if err = unmarshalNullable(vexStatus, &vulnerability.VEXStatus); err != nil {
+			return err
+		}
+
+		report := reports[key]
+		if resultIndex >= len(report.Report.Results) {
+			return fmt.Errorf(
+				"vulnerability finding of %s/%s refers to missing result %d",
+				key.namespace, key.name, resultIndex,
+			)
+		}
+		result := &report.Report.Results[resultIndex]
+		result.Vulnerabilities = append(result.Vulnerabilities, vulnerability)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to query vulnerability findings: %w", err)
+	}
+
+	return nil
+}
+
+// marshalNullable marshals the value to JSON, or returns nil to store a NULL if the value is not set.
+func marshalNullable(set bool, value any) ([]byte, error) {
+	if !set {
+		return nil, nil
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %T: %w", value, err)
+	}
+
+	return bytes, nil
+}
+
+// unmarshalNullable unmarshals the JSON value, leaving the destination untouched if the value is NULL.
+func unmarshalNullable(bytes []byte, value any) error {
+	if bytes == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(bytes, value); err != nil {
+		return fmt.Errorf("failed to unmarshal %T: %w", value, err)
+	}
+
+	return nil
+}
+
+func (vulnerabilityFindingsHooks) stripEvent(obj runtime.Object) (runtime.Object, error) {
+	report, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	stripped := report.DeepCopy()
+	for i := range stripped.Report.Results {
+		vulnerabilities := stripped.Report.Results[i].Vulnerabilities
+		for j := range vulnerabilities {
+			vulnerabilities[j].Title = ""
+			vulnerabilities[j].Description = ""
+			vulnerabilities[j].References = nil
+			vulnerabilities[j].CVSS = nil
+		}
+	}
+
+	return stripped, nil
+}
+
+// hydrateEvents restores the metadata of the CVEs from the cve_metadata table.
+// The rows of the table are never deleted, so the CVEs of the deleted reports are found too,
+// with the metadata of their latest write.
+func (vulnerabilityFindingsHooks) hydrateEvents(ctx context.Context, q querier, objs []runtime.Object) error {
+	var vulnerabilities []*v1alpha1.Vulnerability
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, obj := range objs {
+		report, ok := obj.(*v1alpha1.VulnerabilityReport)
+		if !ok {
+			return fmt.Errorf("unexpected object type: %T", obj)
+		}
+		for i := range report.Report.Results {
+			for j := range report.Report.Results[i].Vulnerabilities {
+				vulnerability := &report.Report.Results[i].Vulnerabilities[j]
+				vulnerabilities = append(vulnerabilities, vulnerability)
+				if !seen[vulnerability.CVE] {
+					seen[vulnerability.CVE] = true
+					ids = append(ids, vulnerability.CVE)
+				}
+			}
+		}
+	}
+
+	if len(vulnerabilities) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query(
+		ctx,
+		`SELECT id, title, description, "references", cvss FROM cve_metadata WHERE id = ANY($1::TEXT[])`,
+		ids,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query CVE metadata: %w", err)
+	}
+	metadata, err := collectRows(rows, func(row row) (v1alpha1.Vulnerability, error) {
+		var vulnerability v1alpha1.Vulnerability
+		var references, cvss []byte
+		if err := row.Scan(
+			&vulnerability.CVE,
+			&vulnerability.Title,
+			&vulnerability.Description,
+			&references,
+			&cvss,
+		); err != nil {
+			return vulnerability, err
+		}
+		if err := unmarshalNullable(references, &vulnerability.References); err != nil {
+			return vulnerability, err
+		}
+		err := unmarshalNullable(cvss, &vulnerability.CVSS)
+
+		return vulnerability, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query CVE metadata: %w", err)
+	}
+
+	byID := make(map[string]*v1alpha1.Vulnerability, len(metadata))
+	for i := range metadata {
+		byID[metadata[i].CVE] = &metadata[i]
+	}
+	for _, vulnerability := range vulnerabilities {
+		cve, ok := byID[vulnerability.CVE]
+		if !ok {
+			continue
+		}
+		// The objects of the events must not share their references and scores.
+		cve = cve.DeepCopy()
+		vulnerability.Title = cve.Title
+		vulnerability.Description = cve.Description
+		vulnerability.References = cve.References
+		vulnerability.CVSS = cve.CVSS
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+const vulnerabilityReportKeyPrefix = "/storage.sbomscanner.kubewarden.io/vulnerabilityreports"
+
+func (suite *storeTestSuite) newVulnerabilityReportStore() *store {
+	return newStore(
+		suite.db,
+		"vulnerabilityreports",
+		func() runtime.Object { return &v1alpha1.VulnerabilityReport{} },
+		func() runtime.Object { return &v1alpha1.VulnerabilityReportList{} },
+		vulnerabilityReportHooks(suite.db),
+		slog.Default(),
+	)
+}
+
+func newTestVulnerabilityReport(name string, vulnerabilities ...v1alpha1.Vulnerability) *v1alpha1.VulnerabilityReport {
+	return &v1alpha1.VulnerabilityReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		ImageMetadata: v1alpha1.ImageMetadata{
+			Registry:    "test-registry",
+			RegistryURI: "registry.test",
+			Repository:  "test",
+			Tag:         name,
+			Platform:    "linux/amd64",
+			Digest:      "sha256:" + name,
+		},
+		Report: v1alpha1.Report{
+			Summary: v1alpha1.Summary{High: len(vulnerabilities)},
+			Results: []v1alpha1.Result{
+				{
+					Target: "test",
+					Class:  v1alpha1.ClassLangPackages,
+					Type:   "gomod",
+				},
+				{
+					Target:          "test (debian 12)",
+					Class:           v1alpha1.ClassOSPackages,
+					Type:            "debian",
+					Vulnerabilities: vulnerabilities,
+				},
+			},
+		},
+	}
+}
+
+var (
+	testVulnerability1 = v1alpha1.Vulnerability{
+		CVE:              "CVE-2024-3094",
+		Title:            "xz: malicious code in distributed source",
+		PackageName:      "liblzma5",
+		PURL:             "pkg:deb/debian/liblzma5@5.6.0-0.2",
+		InstalledVersion: "5.6.0-0.2",
+		FixedVersions:    []string{"5.6.1+really5.4.5-1"},
+		DiffID:           "sha256:diff1",
+		Description:      "Malicious code was discovered in the upstream tarballs of xz.",
+		Severity:         "CRITICAL",
+		References:       []string{"https://nvd.nist.gov/vuln/detail/CVE-2024-3094"},
+		CVSS: map[string]v1alpha1.CVSS{
+			"nvd": {V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", V3Score: "10"},
+		},
+	}
+	testVulnerability2 = v1alpha1.Vulnerability{
+		CVE:              "CVE-2023-4911",
+		PackageName:      "libc6",
+		PURL:             "pkg:deb/debian/libc6@2.36-9",
+		InstalledVersion: "2.36-9",
+		DiffID:           "sha256:diff2",
+		Severity:         "HIGH",
+		Suppressed:       true,
+		VEXStatus: &v1alpha1.VEXStatus{
+			Repository: "https://vex.test",
+			Status:     "not_affected",
+		},
+	}
+)
+
+func (suite *storeTestSuite) countRows(table string) int {
+	var count int
+	err := suite.db.QueryRow(context.Background(), "SELECT COUNT(*) FROM "+table).Scan(&count)
+	suite.Require().NoError(err)
+
+	return count
+}
+
+func (suite *storeTestSuite) TestVulnerabilityFindings() {
+	suite.requirePostgres()
+
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+
+	report1 := newTestVulnerabilityReport("test1", testVulnerability1, testVulnerability2)
+	err := vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		report1,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	report2 := newTestVulnerabilityReport("test2", testVulnerability1)
+	err = vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test2",
+		report2,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	// The CVE metadata is shared by the reports.
+	suite.Equal(2, suite.countRows("cve_metadata"))
+	suite.Equal(3, suite.countRows("vulnerability_findings"))
+
+	var storedVulnerabilities int
+	err = suite.db.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM vulnerabilityreports, jsonb_array_elements(object->'report'->'results') AS result "+
+			"WHERE jsonb_array_length(result->'vulnerabilities') > 0",
+	).Scan(&storedVulnerabilities)
+	suite.Require().NoError(err)
+	suite.Equal(0, storedVulnerabilities)
+
+	report := &v1alpha1.VulnerabilityReport{}
+	err = vulnerabilityReportStore.Get(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		storage.GetOptions{},
+		report,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(report1, report)
+
+	reportList := &v1alpha1.VulnerabilityReportList{}
+	err = vulnerabilityReportStore.GetList(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default",
+		storage.ListOptions{Recursive: true},
+		reportList,
+	)
+	suite.Require().NoError(err)
+	suite.Require().Len(reportList.Items, 2)
+	suite.Equal(*report1, reportList.Items[0])
+	suite.Equal(*report2, reportList.Items[1])
+
+	updatedReport := &v1alpha1.VulnerabilityReport{}
+	err = vulnerabilityReportStore.GuaranteedUpdate(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		updatedReport,
+		false,
+		&storage.Preconditions{},
+		func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+			report, ok := input.(*v1alpha1.VulnerabilityReport)
+			if !ok {
+				return nil, nil, errors.New("input is not of type *v1alpha1.VulnerabilityReport")
+			}
+			report.Report.Results[1].Vulnerabilities = report.Report.Results[1].Vulnerabilities[1:]
+
+			return report, nil, nil
+		},
+		nil,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(2, suite.countRows("vulnerability_findings"))
+
+	report = &v1alpha1.VulnerabilityReport{}
+	err = vulnerabilityReportStore.Get(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		storage.GetOptions{},
+		report,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(updatedReport, report)
+
+	deletedReport := &v1alpha1.VulnerabilityReport{}
+	err = vulnerabilityReportStore.Delete(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test2",
+		deletedReport,
+		&storage.Preconditions{},
+		func(_ context.Context, _ runtime.Object) error { return nil },
+		nil,
+		storage.DeleteOptions{},
+	)
+	suite.Require().NoError(err)
+	suite.Equal(report2, deletedReport)
+	suite.Equal(1, suite.countRows("vulnerability_findings"))
+}
+
+func (suite *storeTestSuite) TestVulnerabilityFindingsEvents() {
+	suite.requirePostgres()
+
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+
+	report1 := newTestVulnerabilityReport("test1")
+	err := vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		report1,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	report2 := newTestVulnerabilityReport("test2", testVulnerability1, testVulnerability2)
+	err = vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test2",
+		report2,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	deletedReport := &v1alpha1.VulnerabilityReport{}
+	err = vulnerabilityReportStore.Delete(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test2",
+		deletedReport,
+		&storage.Preconditions{},
+		func(_ context.Context, _ runtime.Object) error { return nil },
+		nil,
+		storage.DeleteOptions{},
+	)
+	suite.Require().NoError(err)
+
+	// The events keep the findings, without the metadata of the CVEs.
+	var storedDescriptions, storedFindings int
+	err = suite.db.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FILTER (WHERE vulnerability ? 'description'), COUNT(*) "+
+			"FROM watch_events, jsonb_path_query(object, '$.report.results[*].vulnerabilities[*]') AS vulnerability "+
+			"WHERE resource = 'vulnerabilityreports'",
+	).Scan(&storedDescriptions, &storedFindings)
+	suite.Require().NoError(err)
+	suite.Equal(0, storedDescriptions)
+	suite.Equal(4, storedFindings)
+
+	// The replayed events are restored with the metadata of the CVEs, also once the report is deleted.
+	watcher, err := vulnerabilityReportStore.Watch(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default",
+		storage.ListOptions{
+			ResourceVersion: report1.ResourceVersion,
+			Predicate:       storage.Everything,
+		},
+	)
+	suite.Require().NoError(err)
+
+	events := collectEvents(watcher, 2)
+	suite.Require().Len(events, 2)
+	suite.Equal(watch.Added, events[0].Type)
+	suite.Equal(report2, events[0].Object)
+	// The DELETED event carries the resourceVersion of the deletion.
+	suite.Equal(watch.Deleted, events[1].Type)
+	deletedEventReport, ok := events[1].Object.(*v1alpha1.VulnerabilityReport)
+	suite.Require().True(ok)
+	deletedReport.ResourceVersion = deletedEventReport.ResourceVersion
+	suite.Equal(deletedReport, deletedEventReport)
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// upsertTimelineSQL records the vulnerabilities found by a report of the digest.
+// A vulnerability keeps the time it was first seen, also when it is found again after being resolved.
+const upsertTimelineSQL = `
+INSERT INTO vulnerability_timeline (namespace, digest, cve, package_name, purl, severity, first_seen, last_seen)
+SELECT $1::TEXT, $2::TEXT, seen.*, date_trunc('second', now()), date_trunc('second', now())
+FROM unnest($3::TEXT[], $4::TEXT[], $5::TEXT[], $6::TEXT[]) AS seen (cve, package_name, purl, severity)
+ORDER BY 3, 4, 5
+ON CONFLICT (namespace, digest, cve, package_name, purl) DO UPDATE SET
+    severity = EXCLUDED.severity,
+    last_seen = EXCLUDED.last_seen,
+    resolved_at = NULL
+RETURNING cve, package_name, purl, first_seen`
+
+// resolveTimelineSQL resolves the vulnerabilities of the digest no longer found by any report of the digest.
+const resolveTimelineSQL = `
+UPDATE vulnerability_timeline t
+SET resolved_at = date_trunc('second', now())
+WHERE t.namespace = $1 AND t.digest = $2 AND t.resolved_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1
+        FROM vulnerabilityreports r
+        JOIN vulnerability_findings f ON f.report_name = r.name AND f.report_namespace = r.namespace
+        WHERE r.namespace = t.namespace AND r.digest = t.digest
+            AND f.cve = t.cve AND f.package_name = t.package_name AND f.purl = t.purl
+    )`
+
+// timelineKey identifies a vulnerability of a package in the timeline of a digest.
+type timelineKey struct {
+	cve         string
+	packageName string
+	purl        string
+}
+
+// updateVulnerabilityTimeline records the vulnerabilities of the report in the timeline of its image digest,
+// and sets the time each of them was first seen.
+// It is called after the findings of the report have been written.
+func updateVulnerabilityTimeline(
+	ctx context.Context,
+	tx transaction,
+	namespace string,
+	report *v1alpha1.VulnerabilityReport,
+) error {
+	digest := report.ImageMetadata.Digest
+	if digest == "" {
+		return nil
+	}
+
+	// A vulnerability can be found in several results, the severity of the first one is recorded.
+	var cves, packageNames, purls, severities []string
+	seen := map[timelineKey]bool{}
+	for _, result := range report.Report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			key := timelineKey{cve: vulnerability.CVE, packageName: vulnerability.PackageName, purl: vulnerability.PURL}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			cves = append(cves, key.cve)
+			packageNames = append(packageNames, key.packageName)
+			purls = append(purls, key.purl)
+			severities = append(severities, vulnerability.Severity)
+		}
+	}
+
+	firstSeen := make(map[timelineKey]metav1.Time, len(seen))
+	if len(seen) > 0 {
+		rows, err := tx.Query(ctx, upsertTimelineSQL, namespace, digest, cves, packageNames, purls, severities)
+		if err != nil {
+			return fmt.Errorf("failed to update vulnerability timeline: %w", err)
+		}
+		type seenEntry struct {
+			key       timelineKey
+			firstSeen time.Time
+		}
+		entries, err := collectRows(rows, func(row row) (seenEntry, error) {
+			var entry seenEntry
+			err := row.Scan(&entry.key.cve, &entry.key.packageName, &entry.key.purl, &entry.firstSeen)
+
+			return entry, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update vulnerability timeline: %w", err)
+		}
+		for _, entry := range entries {
+			firstSeen[entry.key] = metav1.NewTime(entry.firstSeen.Local())
+		}
+	}
+
+	if _, err := tx.Exec(ctx, resolveTimelineSQL, namespace, digest); err != nil {
+		return fmt.Errorf("failed to resolve vulnerability timeline: %w", err)
+	}
+
+	for i := range report.Report.Results {
+		vulnerabilities := report.Report.Results[i].Vulnerabilities
+		for j := range vulnerabilities {
+			key := timelineKey{
+				cve:         vulnerabilities[j].CVE,
+				packageName: vulnerabilities[j].PackageName,
+				purl:        vulnerabilities[j].PURL,
+			}
+			if t, ok := firstSeen[key]; ok {
+				vulnerabilities[j].FirstSeen = &t
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+// timelineEntry is a row of the vulnerability_timeline table.
+type timelineEntry struct {
+	cve        string
+	firstSeen  time.Time
+	lastSeen   time.Time
+	resolvedAt *time.Time
+}
+
+func (suite *storeTestSuite) timeline(digest string) map[string]timelineEntry {
+	rows, err := suite.db.Query(
+		context.Background(),
+		"SELECT cve, first_seen, last_seen, resolved_at FROM vulnerability_timeline WHERE namespace = 'default' AND digest = $1",
+		digest,
+	)
+	suite.Require().NoError(err)
+
+	entries, err := collectRows(rows, func(row row) (timelineEntry, error) {
+		var entry timelineEntry
+		err := row.Scan(&entry.cve, &entry.firstSeen, &entry.lastSeen, &entry.resolvedAt)
+
+		return entry, err
+	})
+	suite.Require().NoError(err)
+
+	timeline := make(map[string]timelineEntry, len(entries))
+	for _, entry := range entries {
+		timeline[entry.cve] = entry
+	}
+
+	return timeline
+}
+
+func (suite *storeTestSuite) TestVulnerabilityTimeline() {
+	suite.requirePostgres()
+
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+
+	report := newTestVulnerabilityReport("test1", testVulnerability1, testVulnerability2)
+	err := vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		report,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	timeline := suite.timeline("sha256:test1")
+	suite.Require().Len(timeline, 2)
+	for _, vulnerability := range report.Report.Results[1].Vulnerabilities {
+		suite.Require().NotNil(vulnerability.FirstSeen)
+		suite.True(timeline[vulnerability.CVE].firstSeen.Equal(vulnerability.FirstSeen.Time))
+		suite.Nil(timeline[vulnerability.CVE].resolvedAt)
+	}
+
+	// Move the timeline to the past, as if the report was scanned a day ago.
+	firstScan := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	_, err = suite.db.Exec(
+		context.Background(),
+		"UPDATE vulnerability_timeline SET first_seen = $1, last_seen = $1",
+		firstScan,
+	)
+	suite.Require().NoError(err)
+
+	// The report is overwritten by rescans, which do not carry the first seen times.
+	rescan := func(vulnerabilities ...v1alpha1.Vulnerability) *v1alpha1.VulnerabilityReport {
+		updatedReport := &v1alpha1.VulnerabilityReport{}
+		err := vulnerabilityReportStore.GuaranteedUpdate(
+			context.Background(),
+			vulnerabilityReportKeyPrefix+"/default/test1",
+			updatedReport,
+			false,
+			&storage.Preconditions{},
+			func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+				report, ok := input.(*v1alpha1.VulnerabilityReport)
+				if !ok {
+					return nil, nil, errors.New("input is not of type *v1alpha1.VulnerabilityReport")
+				}
+				report.Report.Results[1].Vulnerabilities = vulnerabilities
+
+				return report, nil, nil
+			},
+			nil,
+		)
+		suite.Require().NoError(err)
+
+		return updatedReport
+	}
+
+	updatedReport := rescan(testVulnerability2)
+	suite.Require().Len(updatedReport.Report.Results[1].Vulnerabilities, 1)
+	suite.Equal(metav1.NewTime(firstScan), *updatedReport.Report.Results[1].Vulnerabilities[0].FirstSeen)
+
+	storedReport := &v1alpha1.VulnerabilityReport{}
+	err = vulnerabilityReportStore.Get(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test1",
+		storage.GetOptions{},
+		storedReport,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(updatedReport, storedReport)
+
+	// The vulnerability no longer found is resolved, the other one is seen again.
+	timeline = suite.timeline("sha256:test1")
+	suite.Require().Len(timeline, 2)
+	suite.Require().NotNil(timeline[testVulnerability1.CVE].resolvedAt)
+	suite.True(timeline[testVulnerability1.CVE].lastSeen.Equal(firstScan))
+	suite.Nil(timeline[testVulnerability2.CVE].resolvedAt)
+	suite.True(timeline[testVulnerability2.CVE].lastSeen.After(firstScan))
+
+	// A vulnerability found again keeps the time it was first seen.
+	updatedReport = rescan(testVulnerability1, testVulnerability2)
+	for _, vulnerability := range updatedReport.Report.Results[1].Vulnerabilities {
+		suite.Equal(metav1.NewTime(firstScan), *vulnerability.FirstSeen)
+	}
+	timeline = suite.timeline("sha256:test1")
+	suite.Nil(timeline[testVulnerability1.CVE].resolvedAt)
+	suite.True(timeline[testVulnerability1.CVE].lastSeen.After(firstScan))
+
+	// The timeline is kept until the last report of the digest is deleted.
+	otherReport := newTestVulnerabilityReport("test2", testVulnerability1)
+	otherReport.ImageMetadata.Digest = "sha256:test1"
+	err = vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test2",
+		otherReport,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(metav1.NewTime(firstScan), *otherReport.Report.Results[1].Vulnerabilities[0].FirstSeen)
+
+	deleteReport := func(name string) {
+		err := vulnerabilityReportStore.Delete(
+			context.Background(),
+			vulnerabilityReportKeyPrefix+"/default/"+name,
+			&v1alpha1.VulnerabilityReport{},
+			&storage.Preconditions{},
+			func(_ context.Context, _ runtime.Object) error { return nil },
+			nil,
+			storage.DeleteOptions{},
+		)
+		suite.Require().NoError(err)
+	}
+	deleteReport("test1")
+	suite.Equal(2, suite.countRows("vulnerability_timeline"))
+
+	deleteReport("test2")
+	suite.Equal(0, suite.countRows("vulnerability_timeline"))
+}
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+var (
+	_ rest.Storage              = &vulnerabilityHistoryStore{}
+	_ rest.Scoper               = &vulnerabilityHistoryStore{}
+	_ rest.Getter               = &vulnerabilityHistoryStore{}
+	_ rest.Lister               = &vulnerabilityHistoryStore{}
+	_ rest.Watcher              = &vulnerabilityHistoryStore{}
+	_ rest.SingularNameProvider = &vulnerabilityHistoryStore{}
+)
+
+// vulnerabilityHistorySQL projects a VulnerabilityReport, read from the object column of the given table,
+// to a VulnerabilityHistory holding the timeline of the digest of the report.
+func vulnerabilityHistorySQL(table string) string {
+	return `jsonb_build_object(
+    'metadata', object->'metadata' - 'managedFields',
+    'imageMetadata', object->'imageMetadata',
+    'timeline', COALESCE(
+        (
+            SELECT jsonb_agg(
+                jsonb_strip_nulls(jsonb_build_object(
+                    'cve', t.cve,
+                    'packageName', t.package_name,
+                    'purl', t.purl,
+                    'severity', t.severity,
+                    'firstSeen', to_char(t.first_seen AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+                    'lastSeen', to_char(t.last_seen AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+                    'resolvedAt', to_char(t.resolved_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+                ))
+                ORDER BY t.first_seen, t.cve, t.package_name, t.purl
+            )
+            FROM vulnerability_timeline t
+            WHERE t.namespace = ` + table + `.namespace AND t.digest = ` + table + `.object #>> '{imageMetadata,digest}'
+        ),
+        '[]'::JSONB
+    )
+)`
+}
+
+// vulnerabilityHistoryProjection serves the VulnerabilityReports as VulnerabilityHistories.
+// The timeline is read when the objects and the events are served, so a watch event carries the current timeline of the digest.
+var vulnerabilityHistoryProjection = &projection{
+	object: vulnerabilityHistorySQL("vulnerabilityreports"),
+	event:  vulnerabilityHistorySQL("watch_events"),
+}
+
+// vulnerabilityHistoryStore serves the read-only VulnerabilityHistory resource.
+// Only the get, list and watch verbs of the underlying registry store are exposed.
+type vulnerabilityHistoryStore struct {
+	store *registry.Store
+}
+
+// NewVulnerabilityHistoryStore returns a read-only store serving the vulnerability timelines of the VulnerabilityReports.
+// It is not served from the watch cache, as the timeline of a report can be changed by the other reports of its digest.
+func NewVulnerabilityHistoryStore(
+	scheme *runtime.Scheme,
+	optsGetter generic.RESTOptionsGetter,
+	db Database,
+	logger *slog.Logger,
+) (rest.Storage, error) {
+	// The strategy is required to complete the store, the write verbs are not exposed.
+	strategy := newVulnerabilityReportStrategy(scheme)
+
+	newFunc := func() runtime.Object { return &v1alpha1.VulnerabilityHistory{} }
+	newListFunc := func() runtime.Object { return &v1alpha1.VulnerabilityHistoryList{} }
+
+	objectStore := newProjectionStore(
+		db,
+		"vulnerabilityreports",
+		vulnerabilityHistoryProjection,
+		newFunc,
+		newListFunc,
+		logger.With("store", "vulnerabilityhistory"),
+	)
+
+	store := &registry.Store{
+		NewFunc:                   newFunc,
+		NewListFunc:               newListFunc,
+		PredicateFunc:             matcher,
+		DefaultQualifiedResource:  v1alpha1.Resource("vulnerabilityhistories"),
+		SingularQualifiedResource: v1alpha1.Resource("vulnerabilityhistory"),
+		Storage: registry.DryRunnableStorage{
+			Storage: objectStore,
+		},
+		DestroyFunc:    objectStore.destroy,
+		CreateStrategy: strategy,
+		UpdateStrategy: strategy,
+		DeleteStrategy: strategy,
+		TableConvertor: &vulnerabilityHistoryTableConvertor{},
+	}
+
+	if err := completeStore(store, optsGetter, nil); err != nil {
+		return nil, err
+	}
+
+	return &vulnerabilityHistoryStore{store: store}, nil
+}
+
+// New returns an empty VulnerabilityHistory.
+func (s *vulnerabilityHistoryStore) New() runtime.Object {
+	return s.store.New()
+}
+
+// NewList returns an empty VulnerabilityHistoryList.
+func (s *vulnerabilityHistoryStore) NewList() runtime.Object {
+	return s.store.NewList()
+}
+
+// Destroy stops the watchers of the store.
+func (s *vulnerabilityHistoryStore) Destroy() {
+	s.store.Destroy()
+}
+
+// NamespaceScoped returns true, as a VulnerabilityHistory lives in the namespace of its report.
+func (s *vulnerabilityHistoryStore) NamespaceScoped() bool {
+	return s.store.NamespaceScoped()
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *vulnerabilityHistoryStore) GetSingularName() string {
+	return s.store.GetSingularName()
+}
+
+// Get returns the history of the VulnerabilityReport with the given name.
+func (s *vulnerabilityHistoryStore) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return s.store.Get(ctx, name, options) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+// List returns the histories of the VulnerabilityReports matching the options.
+func (s *vulnerabilityHistoryStore) List(
+	ctx context.Context,
+	options *metainternalversion.ListOptions,
+) (runtime.Object, error) {
+	return s.store.List(ctx, options) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+// Watch watches the histories of the VulnerabilityReports matching the options.
+func (s *vulnerabilityHistoryStore) Watch(
+	ctx context.Context,
+	options *metainternalversion.ListOptions,
+) (watch.Interface, error) {
+	return s.store.Watch(ctx, options) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+// ConvertToTable converts the histories to a table.
+func (s *vulnerabilityHistoryStore) ConvertToTable(
+	ctx context.Context,
+	object runtime.Object,
+	tableOptions runtime.Object,
+) (*metav1.Table, error) {
+	return s.store.ConvertToTable(ctx, object, tableOptions) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+type vulnerabilityHistoryTableConvertor struct{}
+
+func (c *vulnerabilityHistoryTableConvertor) ConvertToTable(
+	_ context.Context,
+	obj runtime.Object,
+	_ runtime.Object,
+) (*metav1.Table, error) {
+	columns := append(
+		imageMetadataTableColumns(),
+		metav1.TableColumnDefinition{Name: "Open", Type: "integer", Description: "Vulnerabilities still found"},
+		metav1.TableColumnDefinition{Name: "Resolved", Type: "integer", Description: "Vulnerabilities no longer found"},
+		metav1.TableColumnDefinition{Name: "Last Seen", Type: "date", Description: "Time of the last scan finding a vulnerability"},
+	)
+
+	table := &metav1.Table{
+		ColumnDefinitions: columns,
+		Rows:              []metav1.TableRow{},
+	}
+
+	// Handle both single object and list
+	var vulnerabilityHistories []v1alpha1.VulnerabilityHistory
+	switch t := obj.(type) {
+	case *v1alpha1.VulnerabilityHistoryList:
+		vulnerabilityHistories = t.Items
+	case *v1alpha1.VulnerabilityHistory:
+		vulnerabilityHistories = []v1alpha1.VulnerabilityHistory{*t}
+	default:
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+
+	for _, vulnerabilityHistory := range vulnerabilityHistories {
+		var open, resolved int
+		var lastSeen time.Time
+		for _, entry := range vulnerabilityHistory.Timeline {
+			if entry.ResolvedAt != nil {
+				resolved++
+			} else {
+				open++
+			}
+			if entry.LastSeen.After(lastSeen) {
+				lastSeen = entry.LastSeen.Time
+			}
+		}
+
+		lastSeenCell := ""
+		if !lastSeen.IsZero() {
+			lastSeenCell = lastSeen.UTC().Format(time.RFC3339)
+		}
+
+		cells := append(
+			imageMetadataTableRowCells(vulnerabilityHistory.Name, &vulnerabilityHistory),
+			open,
+			resolved,
+			lastSeenCell,
+		)
+		row := metav1.TableRow{
+			Object: runtime.RawExtension{Object: &vulnerabilityHistory},
+			Cells:  cells,
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+const vulnerabilityHistoryKeyPrefix = "/storage.sbomscanner.kubewarden.io/vulnerabilityhistories"
+
+func (suite *storeTestSuite) newVulnerabilityHistoryStore() *store {
+	return newProjectionStore(
+		suite.db,
+		"vulnerabilityreports",
+		vulnerabilityHistoryProjection,
+		func() runtime.Object { return &v1alpha1.VulnerabilityHistory{} },
+		func() runtime.Object { return &v1alpha1.VulnerabilityHistoryList{} },
+		slog.Default(),
+	)
+}
+
+func (suite *storeTestSuite) TestVulnerabilityHistoryStore() {
+	suite.requirePostgres()
+
+	report1, report2 := suite.createTestVulnerabilityReports()
+
+	vulnerabilityHistoryStore := suite.newVulnerabilityHistoryStore()
+	defer vulnerabilityHistoryStore.destroy()
+
+	// Fix the times of the timelines, and resolve a vulnerability of the first image.
+	_, err := suite.db.Exec(
+		context.Background(),
+		"UPDATE vulnerability_timeline SET first_seen = to_timestamp(1700000000), last_seen = to_timestamp(1700003600)",
+	)
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(
+		context.Background(),
+		"UPDATE vulnerability_timeline SET first_seen = to_timestamp(1699990000), resolved_at = to_timestamp(1700007200) "+
+			"WHERE digest = 'sha256:test1' AND cve = $1",
+		testVulnerability2.CVE,
+	)
+	suite.Require().NoError(err)
+
+	newTestVulnerabilityHistory := func(
+		report *v1alpha1.VulnerabilityReport,
+		timeline ...v1alpha1.VulnerabilityTimelineEntry,
+	) *v1alpha1.VulnerabilityHistory {
+		objectMeta := *report.ObjectMeta.DeepCopy()
+		objectMeta.ManagedFields = nil
+
+		return &v1alpha1.VulnerabilityHistory{
+			ObjectMeta:    objectMeta,
+			ImageMetadata: report.ImageMetadata,
+			Timeline:      timeline,
+		}
+	}
+	resolvedAt := metav1.Unix(1700007200, 0)
+	resolvedEntry := v1alpha1.VulnerabilityTimelineEntry{
+		CVE:         testVulnerability2.CVE,
+		PackageName: testVulnerability2.PackageName,
+		PURL:        testVulnerability2.PURL,
+		Severity:    testVulnerability2.Severity,
+		FirstSeen:   metav1.Unix(1699990000, 0),
+		LastSeen:    metav1.Unix(1700003600, 0),
+		ResolvedAt:  &resolvedAt,
+	}
+	openEntry := v1alpha1.VulnerabilityTimelineEntry{
+		CVE:         testVulnerability1.CVE,
+		PackageName: testVulnerability1.PackageName,
+		PURL:        testVulnerability1.PURL,
+		Severity:    testVulnerability1.Severity,
+		FirstSeen:   metav1.Unix(1700000000, 0),
+		LastSeen:    metav1.Unix(1700003600, 0),
+	}
+
+	history := &v1alpha1.VulnerabilityHistory{}
+	err = vulnerabilityHistoryStore.Get(
+		context.Background(),
+		vulnerabilityHistoryKeyPrefix+"/default/test1",
+		storage.GetOptions{},
+		history,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(newTestVulnerabilityHistory(report1, resolvedEntry, openEntry), history)
+
+	list := &v1alpha1.VulnerabilityHistoryList{}
+	err = vulnerabilityHistoryStore.GetList(
+		context.Background(),
+		vulnerabilityHistoryKeyPrefix+"/default",
+		storage.ListOptions{
+			Predicate: matcher(labels.Everything(), fields.OneTermEqualSelector("imageMetadata.tag", "test2")),
+		},
+		list,
+	)
+	suite.Require().NoError(err)
+	suite.Equal([]v1alpha1.VulnerabilityHistory{*newTestVulnerabilityHistory(report2, openEntry)}, list.Items)
+}
@@ -5,36 +5,35 @@ import (
 	"fmt"
 	"log/slog"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/generic/registry"
 )
 
-const CreateVulnerabilityReportTableSQL = `
-CREATE TABLE IF NOT EXISTS vulnerabilityreports (
-    name VARCHAR(253) NOT NULL,
-    namespace VARCHAR(253) NOT NULL,
-    object JSONB NOT NULL,
-    PRIMARY KEY (name, namespace)
-);
-`
-
 // NewVulnerabilityReport returns a store registry that will work against API services.
 func NewVulnerabilityReport(
 	scheme *runtime.Scheme,
 	optsGetter generic.RESTOptionsGetter,
-	db *pgxpool.Pool,
+	db Database,
+	watchCache *WatchCacheConfig,
 	logger *slog.Logger,
-) (*registry.Store, error) {
+) (*RegistryStore, error) {
 	strategy := newVulnerabilityReportStrategy(scheme)
 
 	newFunc := func() runtime.Object { return &v1alpha1.VulnerabilityReport{} }
 	newListFunc := func() runtime.Object { return &v1alpha1.VulnerabilityReportList{} }
 
+	objectStore := newStore(
+		db,
+		"vulnerabilityreports",
+		newFunc,
+		newListFunc,
+		vulnerabilityReportHooks(db),
+		logger.With("store", "vulnerabilityreport"),
+	)
+
 	store := &registry.Store{
 		NewFunc:                   newFunc,
 		NewListFunc:               newListFunc,
@@ -42,27 +41,20 @@ func NewVulnerabilityReport(
 		DefaultQualifiedResource:  v1alpha1.Resource("vulnerabilityreports"),
 		SingularQualifiedResource: v1alpha1.Resource("vulnerabilityreport"),
 		Storage: registry.DryRunnableStorage{
-			Storage: &store{
-				db:          db,
-				broadcaster: watch.NewBroadcaster(1000, watch.WaitIfChannelFull),
-				table:       "vulnerabilityreports",
-				newFunc:     newFunc,
-				newListFunc: newListFunc,
-				logger:      logger.With("store", "vulnerabilityreport"),
-			},
+			Storage: objectStore,
 		},
+		DestroyFunc:    objectStore.destroy,
 		CreateStrategy: strategy,
 		UpdateStrategy: strategy,
 		DeleteStrategy: strategy,
 		TableConvertor: &vulnerabilityReportTableConvertor{},
 	}
 
-	options := &generic.StoreOptions{RESTOptions: optsGetter, AttrFunc: getAttrs}
-	if err := store.CompleteWithOptions(options); err != nil {
-		return nil, fmt.Errorf("unable to complete store with options: %w", err)
+	if err := completeStore(store, optsGetter, watchCache); err != nil {
+		return nil, err
 	}
 
-	return store, nil
+	return &RegistryStore{Store: store, objects: objectStore}, nil
 }
 
 type vulnerabilityReportTableConvertor struct{}
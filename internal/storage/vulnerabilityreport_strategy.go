@@ -2,10 +2,13 @@ package storage
 
 import (
 	"context"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/storage/names"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
 )
 
 // newVulnerabilityReportStrategy creates and returns a vulnerabilityReportStrategy instance
@@ -22,19 +25,57 @@ func (vulnerabilityReportStrategy) NamespaceScoped() bool {
 	return true
 }
 
-func (vulnerabilityReportStrategy) PrepareForCreate(_ context.Context, _ runtime.Object) {
+func (vulnerabilityReportStrategy) PrepareForCreate(_ context.Context, obj runtime.Object) {
+	report, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return
+	}
+
+	setSummaryLabels(report)
 }
 
-func (vulnerabilityReportStrategy) PrepareForUpdate(_ context.Context, _, _ runtime.Object) {
+func (vulnerabilityReportStrategy) PrepareForUpdate(_ context.Context, obj, _ runtime.Object) {
+	report, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return
+	}
+
+	setSummaryLabels(report)
 }
 
-func (vulnerabilityReportStrategy) Validate(_ context.Context, _ runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+// setSummaryLabels sets the summary labels of the report to the counts of its summary,
+// replacing the values set by the clients.
+func setSummaryLabels(report *v1alpha1.VulnerabilityReport) {
+	if report.Labels == nil {
+		report.Labels = map[string]string{}
+	}
+
+	summary := report.Report.Summary
+	report.Labels[v1alpha1.LabelSummaryCriticalKey] = strconv.Itoa(summary.Critical)
+	report.Labels[v1alpha1.LabelSummaryHighKey] = strconv.Itoa(summary.High)
+	report.Labels[v1alpha1.LabelSummaryMediumKey] = strconv.Itoa(summary.Medium)
+	report.Labels[v1alpha1.LabelSummaryLowKey] = strconv.Itoa(summary.Low)
+	report.Labels[v1alpha1.LabelSummaryUnknownKey] = strconv.Itoa(summary.Unknown)
+	report.Labels[v1alpha1.LabelSummarySuppressedKey] = strconv.Itoa(summary.Suppressed)
+}
+
+func (vulnerabilityReportStrategy) Validate(_ context.Context, obj runtime.Object) field.ErrorList {
+	report, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return unexpectedTypeError(obj)
+	}
+
+	return validateImageMetadata(report.ImageMetadata, field.NewPath("imageMetadata"))
 }
 
 // WarningsOnCreate returns warnings for the creation of the given object.
-func (vulnerabilityReportStrategy) WarningsOnCreate(_ context.Context, _ runtime.Object) []string {
-	return nil
+func (vulnerabilityReportStrategy) WarningsOnCreate(_ context.Context, obj runtime.Object) []string {
+	report, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return nil
+	}
+
+	return reportSummaryWarnings(report.Report, field.NewPath("report"))
 }
 
 func (vulnerabilityReportStrategy) AllowCreateOnUpdate() bool {
@@ -48,8 +89,21 @@ func (vulnerabilityReportStrategy) AllowUnconditionalUpdate() bool {
 func (vulnerabilityReportStrategy) Canonicalize(_ runtime.Object) {
 }
 
-func (vulnerabilityReportStrategy) ValidateUpdate(_ context.Context, _, _ runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+func (vulnerabilityReportStrategy) ValidateUpdate(_ context.Context, obj, old runtime.Object) field.ErrorList {
+	newReport, ok := obj.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return unexpectedTypeError(obj)
+	}
+	oldReport, ok := old.(*v1alpha1.VulnerabilityReport)
+	if !ok {
+		return unexpectedTypeError(old)
+	}
+
+	imageMetadataPath := field.NewPath("imageMetadata")
+	allErrs := validateImageMetadata(newReport.ImageMetadata, imageMetadataPath)
+	allErrs = append(allErrs, validateImageMetadataUpdate(newReport.ImageMetadata, oldReport.ImageMetadata, imageMetadataPath)...)
+
+	return allErrs
 }
 
 // WarningsOnUpdate returns warnings for the given update.
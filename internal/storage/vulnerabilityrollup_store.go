@@ -0,0 +1,674 @@
+//nolint:wrapcheck // We want to return the errors from k8s.io/apiserver as they are.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+var (
+	_ rest.Storage              = &vulnerabilityRollupStore{}
+	_ rest.Scoper               = &vulnerabilityRollupStore{}
+	_ rest.Getter               = &vulnerabilityRollupStore{}
+	_ rest.Lister               = &vulnerabilityRollupStore{}
+	_ rest.SingularNameProvider = &vulnerabilityRollupStore{}
+
+	_ rest.Storage              = &clusterVulnerabilityRollupStore{}
+	_ rest.Scoper               = &clusterVulnerabilityRollupStore{}
+	_ rest.Getter               = &clusterVulnerabilityRollupStore{}
+	_ rest.Lister               = &clusterVulnerabilityRollupStore{}
+	_ rest.SingularNameProvider = &clusterVulnerabilityRollupStore{}
+)
+
+// rollupLimit is the number of top CVEs and most vulnerable images of a rollup.
+const rollupLimit = 10
+
+// rollupScope defines how the VulnerabilityReports are grouped into rollups.
+type rollupScope struct {
+	// namespace is the SQL expression of the namespace of the rollup of a report.
+	namespace string
+	// name is the SQL expression of the name of the rollup of a report.
+	name string
+}
+
+var (
+	// registryRollupScope groups the reports by namespace and registry.
+	registryRollupScope = rollupScope{
+		namespace: "namespace",
+		name:      "COALESCE(registry, '')",
+	}
+	// namespaceRollupScope groups the reports by namespace, the rollups are cluster-scoped.
+	namespaceRollupScope = rollupScope{
+		namespace: "''",
+		name:      "namespace",
+	}
+)
+
+// The rollup queries aggregate the reports selected by the "reports" common table expression,
+// which adds the rollup_namespace and rollup_name columns to the vulnerabilityreports table.
+const (
+	rollupTotalsSQL = `
+SELECT
+    r.rollup_namespace,
+    r.rollup_name,
+    COUNT(*),
+    COALESCE(SUM((r.object->'report'->'summary'->>'critical')::INTEGER), 0),
+    COALESCE(SUM((r.object->'report'->'summary'->>'high')::INTEGER), 0),
+    COALESCE(SUM((r.object->'report'->'summary'->>'medium')::INTEGER), 0),
+    COALESCE(SUM((r.object->'report'->'summary'->>'low')::INTEGER), 0),
+    COALESCE(SUM((r.object->'report'->'summary'->>'unknown')::INTEGER), 0),
+    COALESCE(SUM((r.object->'report'->'summary'->>'suppressed')::INTEGER), 0)
+FROM reports r
+GROUP BY r.rollup_namespace, r.rollup_name
+ORDER BY r.rollup_namespace, r.rollup_name`
+
+	rollupFixableSQL = `
+SELECT
+    r.rollup_namespace,
+    r.rollup_name,
+    COUNT(*) FILTER (WHERE cardinality(f.fixed_versions) > 0),
+    COUNT(*)
+FROM reports r
+INNER JOIN vulnerability_findings f ON f.report_name = r.name AND f.report_namespace = r.namespace
+WHERE NOT f.suppressed
+GROUP BY r.rollup_namespace, r.rollup_name`
+
+	rollupTopCVEsSQL = `
+SELECT rollup_namespace, rollup_name, cve, title, images
+FROM (
+    SELECT
+        r.rollup_namespace,
+        r.rollup_name,
+        f.cve,
+        c.title,
+        COUNT(DISTINCT (r.namespace, r.name)) AS images,
+        ROW_NUMBER() OVER (
+            PARTITION BY r.rollup_namespace, r.rollup_name
+            ORDER BY COUNT(DISTINCT (r.namespace, r.name)) DESC, f.cve
+        ) AS rank
+    FROM reports r
+    INNER JOIN vulnerability_findings f ON f.report_name = r.name AND f.report_namespace = r.namespace
+    INNER JOIN cve_metadata c ON c.id = f.cve
+    WHERE NOT f.suppressed
+    GROUP BY r.rollup_namespace, r.rollup_name, f.cve, c.title
+) AS ranked
+WHERE rank <= %d
+ORDER BY rollup_namespace, rollup_name, rank`
+
+	rollupMostVulnerableImagesSQL = `
+SELECT rollup_namespace, rollup_name, namespace, name, image_metadata, summary
+FROM (
+    SELECT
+        r.rollup_namespace,
+        r.rollup_name,
+        r.namespace,
+        r.name,
+        r.object->'imageMetadata' AS image_metadata,
+        r.object->'report'->'summary' AS summary,
+        ROW_NUMBER() OVER (
+            PARTITION BY r.rollup_namespace, r.rollup_name
+            ORDER BY
+                COALESCE((r.object->'report'->'summary'->>'critical')::INTEGER, 0) DESC,
+                COALESCE((r.object->'report'->'summary'->>'high')::INTEGER, 0) DESC,
+                COALESCE((r.object->'report'->'summary'->>'medium')::INTEGER, 0) DESC,
+                COALESCE((r.object->'report'->'summary'->>'low')::INTEGER, 0) DESC,
+                r.namespace,
+                r.name
+        ) AS rank
+    FROM reports r
+) AS ranked
+WHERE rank <= %d
+ORDER BY rollup_namespace, rollup_name, rank`
+)
+
+// rollupKey identifies a rollup.
+type rollupKey struct {
+	namespace string
+	name      string
+}
+
+// namedRollup is a rollup with its key.
+type namedRollup struct {
+	rollupKey
+	v1alpha1.Rollup
+}
+
+// queryRollups computes the rollups of the given scope, ordered by namespace and name.
+// The filters select the reports of the rollups, the rollups without reports are omitted.
+func queryRollups(
+	ctx context.Context,
+	q querier,
+	scope rollupScope,
+	filters []bob.Mod[*dialect.SelectQuery],
+) ([]namedRollup, error) {
+	reportsBuilder := psql.Select(
+		sm.Columns(
+			psql.Raw(scope.namespace+" AS rollup_namespace"),
+			psql.Raw(scope.name+" AS rollup_name"),
+			"name",
+			"namespace",
+			"object",
+		),
+		sm.From("vulnerabilityreports"),
+	)
+	reportsBuilder.Apply(filters...)
+
+	reportsQuery, args, err := reportsBuilder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	with := "WITH reports AS (" + reportsQuery + ")"
+
+	rows, err := q.Query(ctx, with+rollupTotalsSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rollup totals: %w", err)
+	}
+	rollups, err := collectRows(rows, func(row row) (namedRollup, error) {
+		var rollup namedRollup
+		err := row.Scan(
+			&rollup.namespace,
+			&rollup.name,
+			&rollup.Images,
+			&rollup.Summary.Critical,
+			&rollup.Summary.High,
+			&rollup.Summary.Medium,
+			&rollup.Summary.Low,
+			&rollup.Summary.Unknown,
+			&rollup.Summary.Suppressed,
+		)
+		rollup.TopCVEs = []v1alpha1.CVECount{}
+		rollup.MostVulnerableImages = []v1alpha1.VulnerableImage{}
+		return rollup, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rollup totals: %w", err)
+	}
+	if len(rollups) == 0 {
+		return rollups, nil
+	}
+
+	index := make(map[rollupKey]*v1alpha1.Rollup, len(rollups))
+	for i := range rollups {
+		index[rollups[i].rollupKey] = &rollups[i].Rollup
+	}
+
+	if err = queryRollupFixable(ctx, q, with, args, index); err != nil {
+		return nil, err
+	}
+	if err = queryRollupTopCVEs(ctx, q, with, args, index); err != nil {
+		return nil, err
+	}
+	if err = queryRollupMostVulnerableImages(ctx, q, with, args, index); err != nil {
+		return nil, err
+	}
+
+	return rollups, nil
+}
+
+// queryRollupFixable sets the fixable vulnerabilities of the indexed rollups.
+func queryRollupFixable(ctx context.Context, q querier, with string, args []any, index map[rollupKey]*v1alpha1.Rollup) error {
+	rows, err := q.Query(ctx, with+rollupFixableSQL, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query rollup fixable vulnerabilities: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key rollupKey
+		var fixable, total int
+		if err = rows.Scan(&key.namespace, &key.name, &fixable, &total); err != nil {
+			return fmt.Errorf("failed to scan rollup fixable vulnerabilities: %w", err)
+		}
+
+		if rollup, ok := index[key]; ok && total > 0 {
+			rollup.Fixable = fixable
+			rollup.FixablePercentage = fixable * 100 / total
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to query rollup fixable vulnerabilities: %w", err)
+	}
+
+	return nil
+}
+
+// queryRollupTopCVEs sets the top CVEs of the indexed rollups.
+func queryRollupTopCVEs(ctx context.Context, q querier, with string, args []any, index map[rollupKey]*v1alpha1.Rollup) error {
+	rows, err := q.Query(ctx, with+fmt.Sprintf(rollupTopCVEsSQL, rollupLimit), args...)
+	if err != nil {
+		return fmt.Errorf("failed to query rollup top CVEs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key rollupKey
+		var cveCount v1alpha1.CVECount
+		if err = rows.Scan(&key.namespace, &key.name, &cveCount.CVE, &cveCount.Title, &cveCount.Images); err != nil {
+			return fmt.Errorf("failed to scan rollup top CVE: %w", err)
+		}
+
+		if rollup, ok := index[key]; ok {
+			rollup.TopCVEs = append(rollup.TopCVEs, cveCount)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to query rollup top CVEs: %w", err)
+	}
+
+	return nil
+}
+
+// queryRollupMostVulnerableImages sets the most vulnerable images of the indexed rollups.
+func queryRollupMostVulnerableImages(
+	ctx context.Context,
+	q querier,
+	with string,
+	args []any,
+	index map[rollupKey]*v1alpha1.Rollup,
+) error {
+	rows, err := q.Query(ctx, with+fmt.Sprintf(rollupMostVulnerableImagesSQL, rollupLimit), args...)
+	if err != nil {
+		return fmt.Errorf("failed to query rollup most vulnerable images: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key rollupKey
+		var image v1alpha1.VulnerableImage
+		var imageMetadata, summary []byte
+		if err = rows.Scan(
+			&key.namespace,
+			&key.name,
+			&image.Namespace,
+			&image.VulnerabilityReport,
+			&imageMetadata,
+			&summary,
+		); err != nil {
+			return fmt.Errorf("failed to scan rollup most vulnerable image: %w", err)
+		}
+
+		if err = unmarshalNullable(imageMetadata, &image.ImageMetadata); err != nil {
+			return err
+		}
+		if err = unmarshalNullable(summary, &image.Summary); err != nil {
+			return err
+		}
+
+		if rollup, ok := index[key]; ok {
+			rollup.MostVulnerableImages = append(rollup.MostVulnerableImages, image)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to query rollup most vulnerable images: %w", err)
+	}
+
+	return nil
+}
+
+// listRollups computes the rollups of the given scope matching the list options.
+// The namespace restricts the rollups to the given namespace if not empty.
+// The rollups have no labels, and only the metadata field selectors are supported.
+func listRollups(
+	ctx context.Context,
+	db Database,
+	logger *slog.Logger,
+	scope rollupScope,
+	namespace string,
+	options *metainternalversion.ListOptions,
+) ([]namedRollup, error) {
+	label := labels.Everything()
+	field := fields.Everything()
+	if options != nil {
+		if options.LabelSelector != nil {
+			label = options.LabelSelector
+		}
+		if options.FieldSelector != nil {
+			field = options.FieldSelector
+		}
+	}
+
+	logger.DebugContext(ctx, "Listing rollups",
+		"namespace", namespace,
+		"labelSelector", label.String(),
+		"fieldSelector", field.String(),
+	)
+
+	// Rollups have no labels.
+	if !label.Matches(labels.Set{}) {
+		return nil, nil
+	}
+
+	filters, err := buildRollupFieldSelectorFilters(scope, field)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if namespace != "" {
+		filters = append(filters, sm.Where(psql.Raw(scope.namespace).EQ(psql.Arg(namespace))))
+	}
+
+	return queryRollupsInSnapshot(ctx, db, logger, scope, filters)
+}
+
+// getRollup computes the rollup of the given scope with the given namespace and name.
+// It returns a NotFound error if there are no reports to aggregate.
+func getRollup(
+	ctx context.Context,
+	db Database,
+	logger *slog.Logger,
+	scope rollupScope,
+	resource string,
+	namespace, name string,
+) (*v1alpha1.Rollup, error) {
+	logger.DebugContext(ctx, "Getting rollup", "namespace", namespace, "name", name)
+
+	rollups, err := queryRollupsInSnapshot(ctx, db, logger, scope, []bob.Mod[*dialect.SelectQuery]{
+		sm.Where(psql.Raw(scope.namespace).EQ(psql.Arg(namespace))),
+		sm.Where(psql.Raw(scope.name).EQ(psql.Arg(name))),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rollups) == 0 {
+		return nil, apierrors.NewNotFound(v1alpha1.Resource(resource), name)
+	}
+
+	return &rollups[0].Rollup, nil
+}
+
+// queryRollupsInSnapshot computes the rollups reading all the reports from the same snapshot.
+func queryRollupsInSnapshot(
+	ctx context.Context,
+	db Database,
+	logger *slog.Logger,
+	scope rollupScope,
+	filters []bob.Mod[*dialect.SelectQuery],
+) ([]namedRollup, error) {
+	tx, err := db.beginSnapshot(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	rollups, err := queryRollups(ctx, tx, scope, filters)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	return rollups, nil
+}
+
+// buildRollupFieldSelectorFilters builds the SQL filters on the vulnerability reports
+// from the provided rollup field selector.
+func buildRollupFieldSelectorFilters(scope rollupScope, fieldSelector fields.Selector) ([]bob.Mod[*dialect.SelectQuery], error) {
+	columns := map[string]string{
+		"metadata.name": scope.name,
+	}
+	if scope != namespaceRollupScope {
+		columns["metadata.namespace"] = scope.namespace
+	}
+
+	var filters []bob.Mod[*dialect.SelectQuery]
+	for _, req := range fieldSelector.Requirements() {
+		column, ok := columns[req.Field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported field selector: %s", req.Field)
+		}
+
+		switch req.Operator {
+		case selection.Equals, selection.DoubleEquals:
+			filters = append(filters, sm.Where(psql.Raw(column).EQ(psql.Arg(req.Value))))
+		case selection.NotEquals:
+			filters = append(filters, sm.Where(psql.Raw(column).NE(psql.Arg(req.Value))))
+		case selection.In, selection.NotIn, selection.Exists, selection.DoesNotExist, selection.GreaterThan, selection.LessThan:
+			return nil, fmt.Errorf("unsupported field selector operator: %v", req.Operator)
+		}
+	}
+
+	return filters, nil
+}
+
+// vulnerabilityRollupStore serves the read-only VulnerabilityRollup resource,
+// aggregating the VulnerabilityReports by namespace and registry.
+type vulnerabilityRollupStore struct {
+	db     Database
+	logger *slog.Logger
+	rollupTableConvertor
+}
+
+// NewVulnerabilityRollupStore returns a read-only store aggregating the VulnerabilityReports of each registry.
+func NewVulnerabilityRollupStore(db Database, logger *slog.Logger) rest.Storage {
+	return &vulnerabilityRollupStore{
+		db:     db,
+		logger: logger.With("store", "vulnerabilityrollup"),
+	}
+}
+
+// New returns an empty VulnerabilityRollup.
+func (s *vulnerabilityRollupStore) New() runtime.Object {
+	return &v1alpha1.VulnerabilityRollup{}
+}
+
+// NewList returns an empty VulnerabilityRollupList.
+func (s *vulnerabilityRollupStore) NewList() runtime.Object {
+	return &v1alpha1.VulnerabilityRollupList{}
+}
+
+// Destroy cleans up the resources of the store.
+func (s *vulnerabilityRollupStore) Destroy() {
+	// Nothing to clean up, the database connection pool is shared with the other stores.
+}
+
+// NamespaceScoped returns true, as a VulnerabilityRollup lives in the namespace of its registry.
+func (s *vulnerabilityRollupStore) NamespaceScoped() bool {
+	return true
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *vulnerabilityRollupStore) GetSingularName() string {
+	return "vulnerabilityrollup"
+}
+
+// Get returns the rollup of the registry with the given name.
+// It returns a NotFound error if no image of the registry has been scanned.
+func (s *vulnerabilityRollupStore) Get(ctx context.Context, name string, _ *metav1.GetOptions) (runtime.Object, error) {
+	namespace := genericapirequest.NamespaceValue(ctx)
+
+	rollup, err := getRollup(ctx, s.db, s.logger, registryRollupScope, "vulnerabilityrollups", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1alpha1.VulnerabilityRollup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Rollup:     *rollup,
+	}, nil
+}
+
+// List returns the rollups of the registries, ordered by namespace and name.
+func (s *vulnerabilityRollupStore) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	rollups, err := listRollups(
+		ctx,
+		s.db,
+		s.logger,
+		registryRollupScope,
+		genericapirequest.NamespaceValue(ctx),
+		options,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &v1alpha1.VulnerabilityRollupList{Items: make([]v1alpha1.VulnerabilityRollup, 0, len(rollups))}
+	for _, rollup := range rollups {
+		list.Items = append(list.Items, v1alpha1.VulnerabilityRollup{
+			ObjectMeta: metav1.ObjectMeta{Name: rollup.name, Namespace: rollup.namespace},
+			Rollup:     rollup.Rollup,
+		})
+	}
+
+	return list, nil
+}
+
+// clusterVulnerabilityRollupStore serves the read-only ClusterVulnerabilityRollup resource,
+// aggregating the VulnerabilityReports by namespace.
+type clusterVulnerabilityRollupStore struct {
+	db     Database
+	logger *slog.Logger
+	rollupTableConvertor
+}
+
+// NewClusterVulnerabilityRollupStore returns a read-only store aggregating the VulnerabilityReports of each namespace.
+func NewClusterVulnerabilityRollupStore(db Database, logger *slog.Logger) rest.Storage {
+	return &clusterVulnerabilityRollupStore{
+		db:     db,
+		logger: logger.With("store", "clustervulnerabilityrollup"),
+	}
+}
+
+// New returns an empty ClusterVulnerabilityRollup.
+func (s *clusterVulnerabilityRollupStore) New() runtime.Object {
+	return &v1alpha1.ClusterVulnerabilityRollup{}
+}
+
+// NewList returns an empty ClusterVulnerabilityRollupList.
+func (s *clusterVulnerabilityRollupStore) NewList() runtime.Object {
+	return &v1alpha1.ClusterVulnerabilityRollupList{}
+}
+
+// Destroy cleans up the resources of the store.
+func (s *clusterVulnerabilityRollupStore) Destroy() {
+	// Nothing to clean up, the database connection pool is shared with the other stores.
+}
+
+// NamespaceScoped returns false, as a ClusterVulnerabilityRollup is named after the namespace it aggregates.
+func (s *clusterVulnerabilityRollupStore) NamespaceScoped() bool {
+	return false
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *clusterVulnerabilityRollupStore) GetSingularName() string {
+	return "clustervulnerabilityrollup"
+}
+
+// Get returns the rollup of the namespace with the given name.
+// It returns a NotFound error if no image of the namespace has been scanned.
+func (s *clusterVulnerabilityRollupStore) Get(ctx context.Context, name string, _ *metav1.GetOptions) (runtime.Object, error) {
+	rollup, err := getRollup(ctx, s.db, s.logger, namespaceRollupScope, "clustervulnerabilityrollups", "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1alpha1.ClusterVulnerabilityRollup{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rollup:     *rollup,
+	}, nil
+}
+
+// List returns the rollups of the namespaces, ordered by name.
+func (s *clusterVulnerabilityRollupStore) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	rollups, err := listRollups(ctx, s.db, s.logger, namespaceRollupScope, "", options)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &v1alpha1.ClusterVulnerabilityRollupList{
+		Items: make([]v1alpha1.ClusterVulnerabilityRollup, 0, len(rollups)),
+	}
+	for _, rollup := range rollups {
+		list.Items = append(list.Items, v1alpha1.ClusterVulnerabilityRollup{
+			ObjectMeta: metav1.ObjectMeta{Name: rollup.name},
+			Rollup:     rollup.Rollup,
+		})
+	}
+
+	return list, nil
+}
+
+type rollupTableConvertor struct{}
+
+func (c rollupTableConvertor) ConvertToTable(_ context.Context, obj runtime.Object, _ runtime.Object) (*metav1.Table, error) {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name", Description: "Name"},
+			{Name: "Images", Type: "integer", Description: "Number of scanned images"},
+			{Name: "Critical", Type: "integer", Description: "Critical vulnerabilities count"},
+			{Name: "High", Type: "integer", Description: "High vulnerabilities count"},
+			{Name: "Fixable", Type: "string", Description: "Percentage of the vulnerabilities with a fix"},
+			{Name: "Top CVE", Type: "string", Description: "CVE affecting the most images"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	// Handle both single object and list
+	var objects []runtime.Object
+	switch t := obj.(type) {
+	case *v1alpha1.VulnerabilityRollupList:
+		for i := range t.Items {
+			objects = append(objects, &t.Items[i])
+		}
+	case *v1alpha1.ClusterVulnerabilityRollupList:
+		for i := range t.Items {
+			objects = append(objects, &t.Items[i])
+		}
+	case *v1alpha1.VulnerabilityRollup, *v1alpha1.ClusterVulnerabilityRollup:
+		objects = []runtime.Object{t}
+	default:
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+
+	for _, object := range objects {
+		var name string
+		var rollup v1alpha1.Rollup
+		switch t := object.(type) {
+		case *v1alpha1.VulnerabilityRollup:
+			name, rollup = t.Name, t.Rollup
+		case *v1alpha1.ClusterVulnerabilityRollup:
+			name, rollup = t.Name, t.Rollup
+		}
+
+		topCVE := ""
+		if len(rollup.TopCVEs) > 0 {
+			topCVE = rollup.TopCVEs[0].CVE
+		}
+
+		row := metav1.TableRow{
+			Object: runtime.RawExtension{Object: object},
+			Cells: []interface{}{
+				name,
+				rollup.Images,
+				rollup.Summary.Critical,
+				rollup.Summary.High,
+				fmt.Sprintf("%d%%", rollup.FixablePercentage),
+				topCVE,
+			},
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
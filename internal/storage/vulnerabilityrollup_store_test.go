@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+func newTestVulnerableImage(report *v1alpha1.VulnerabilityReport) v1alpha1.VulnerableImage {
+	return v1alpha1.VulnerableImage{
+		Namespace:           report.Namespace,
+		VulnerabilityReport: report.Name,
+		ImageMetadata:       report.ImageMetadata,
+		Summary:             report.Report.Summary,
+	}
+}
+
+func (suite *storeTestSuite) TestVulnerabilityRollupStore() {
+	suite.requirePostgres()
+
+	report1, report2 := suite.createTestVulnerabilityReports()
+	vulnerabilityRollupStore := NewVulnerabilityRollupStore(suite.db, slog.Default()).(*vulnerabilityRollupStore)
+
+	expectedRollup1 := v1alpha1.Rollup{
+		Images:  1,
+		Summary: report1.Report.Summary,
+		Fixable: 1,
+		// The second vulnerability is suppressed.
+		FixablePercentage: 100,
+		TopCVEs: []v1alpha1.CVECount{
+			{CVE: testVulnerability1.CVE, Title: testVulnerability1.Title, Images: 1},
+		},
+		MostVulnerableImages: []v1alpha1.VulnerableImage{newTestVulnerableImage(report1)},
+	}
+	expectedRollup2 := v1alpha1.Rollup{
+		Images:            1,
+		Summary:           report2.Report.Summary,
+		Fixable:           1,
+		FixablePercentage: 100,
+		TopCVEs: []v1alpha1.CVECount{
+			{CVE: testVulnerability1.CVE, Title: testVulnerability1.Title, Images: 1},
+		},
+		MostVulnerableImages: []v1alpha1.VulnerableImage{newTestVulnerableImage(report2)},
+	}
+
+	obj, err := vulnerabilityRollupStore.Get(
+		genericapirequest.WithNamespace(context.Background(), "default"),
+		report1.ImageMetadata.Registry,
+		&metav1.GetOptions{},
+	)
+	suite.Require().NoError(err)
+	suite.Equal(&v1alpha1.VulnerabilityRollup{
+		ObjectMeta: metav1.ObjectMeta{Name: report1.ImageMetadata.Registry, Namespace: "default"},
+		Rollup:     expectedRollup1,
+	}, obj)
+
+	_, err = vulnerabilityRollupStore.Get(
+		genericapirequest.WithNamespace(context.Background(), "default"),
+		"missing",
+		&metav1.GetOptions{},
+	)
+	suite.Require().Error(err)
+	suite.True(apierrors.IsNotFound(err))
+
+	obj, err = vulnerabilityRollupStore.List(context.Background(), &metainternalversion.ListOptions{})
+	suite.Require().NoError(err)
+	suite.Equal(&v1alpha1.VulnerabilityRollupList{
+		Items: []v1alpha1.VulnerabilityRollup{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: report1.ImageMetadata.Registry, Namespace: "default"},
+				Rollup:     expectedRollup1,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: report2.ImageMetadata.Registry, Namespace: "other"},
+				Rollup:     expectedRollup2,
+			},
+		},
+	}, obj)
+
+	obj, err = vulnerabilityRollupStore.List(
+		genericapirequest.WithNamespace(context.Background(), "other"),
+		&metainternalversion.ListOptions{},
+	)
+	suite.Require().NoError(err)
+	list, ok := obj.(*v1alpha1.VulnerabilityRollupList)
+	suite.Require().True(ok)
+	suite.Require().Len(list.Items, 1)
+	suite.Equal("other", list.Items[0].Namespace)
+}
+
+func (suite *storeTestSuite) TestClusterVulnerabilityRollupStore() {
+	suite.requirePostgres()
+
+	report1, report2 := suite.createTestVulnerabilityReports()
+	report3 := newTestVulnerabilityReport("test3", testVulnerability1, testVulnerability2)
+	report3.Report.Summary = v1alpha1.Summary{Critical: 1, High: 1}
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+	err := vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/default/test3",
+		report3,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	clusterVulnerabilityRollupStore := NewClusterVulnerabilityRollupStore(
+		suite.db,
+		slog.Default(),
+	).(*clusterVulnerabilityRollupStore)
+
+	obj, err := clusterVulnerabilityRollupStore.Get(context.Background(), "default", &metav1.GetOptions{})
+	suite.Require().NoError(err)
+	suite.Equal(&v1alpha1.ClusterVulnerabilityRollup{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Rollup: v1alpha1.Rollup{
+			Images:            2,
+			Summary:           v1alpha1.Summary{Critical: 1, High: 3},
+			Fixable:           2,
+			FixablePercentage: 100,
+			TopCVEs: []v1alpha1.CVECount{
+				{CVE: testVulnerability1.CVE, Title: testVulnerability1.Title, Images: 2},
+			},
+			// The image with the most critical vulnerabilities comes first.
+			MostVulnerableImages: []v1alpha1.VulnerableImage{
+				newTestVulnerableImage(report3),
+				newTestVulnerableImage(report1),
+			},
+		},
+	}, obj)
+
+	obj, err = clusterVulnerabilityRollupStore.List(context.Background(), &metainternalversion.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", "other"),
+	})
+	suite.Require().NoError(err)
+	list, ok := obj.(*v1alpha1.ClusterVulnerabilityRollupList)
+	suite.Require().True(ok)
+	suite.Require().Len(list.Items, 1)
+	suite.Equal("other", list.Items[0].Name)
+	suite.Equal([]v1alpha1.VulnerableImage{newTestVulnerableImage(report2)}, list.Items[0].Rollup.MostVulnerableImages)
+
+	_, err = clusterVulnerabilityRollupStore.List(context.Background(), &metainternalversion.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.namespace", "other"),
+	})
+	suite.Require().Error(err)
+	suite.True(apierrors.IsBadRequest(err))
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+var (
+	_ rest.Storage              = &vulnerabilitySummaryStore{}
+	_ rest.Scoper               = &vulnerabilitySummaryStore{}
+	_ rest.Getter               = &vulnerabilitySummaryStore{}
+	_ rest.Lister               = &vulnerabilitySummaryStore{}
+	_ rest.Watcher              = &vulnerabilitySummaryStore{}
+	_ rest.SingularNameProvider = &vulnerabilitySummaryStore{}
+)
+
+// vulnerabilitySummarySQL projects a VulnerabilityReport, read from the object column, to a VulnerabilitySummary.
+// The fixable vulnerabilities are counted by the given SQL expression.
+// The last scan is the latest write of the report recorded in the managed fields.
+func vulnerabilitySummarySQL(fixable string) string {
+	return `jsonb_build_object(
+    'metadata', object->'metadata' - 'managedFields',
+    'imageMetadata', object->'imageMetadata',
+    'summary', object->'report'->'summary',
+    'fixable', ` + fixable + `,
+    'firstScannedAt', object->'metadata'->'creationTimestamp',
+    'lastScannedAt', COALESCE(
+        (
+            SELECT MAX(managed_field.value #>> '{}')
+            FROM jsonb_path_query(object, '$.metadata.managedFields[*].time') AS managed_field (value)
+        ),
+        object->'metadata'->>'creationTimestamp'
+    )
+)`
+}
+
+// vulnerabilitySummaryProjection serves the VulnerabilityReports as VulnerabilitySummaries.
+// The vulnerabilities of the stored reports are kept in the vulnerability_findings table,
+// while the watch events carry the whole report.
+// The ? of the jsonpath filter is escaped, as it would otherwise be read as a placeholder.
+var vulnerabilitySummaryProjection = &projection{
+	object: vulnerabilitySummarySQL(`(
+        SELECT COUNT(*)
+        FROM vulnerability_findings f
+        WHERE f.report_name = vulnerabilityreports.name
+            AND f.report_namespace = vulnerabilityreports.namespace
+            AND cardinality(f.fixed_versions) > 0
+            AND NOT f.suppressed
+    )`),
+	event: vulnerabilitySummarySQL(`(
+        SELECT COUNT(*)
+        FROM jsonb_path_query(
+            object,
+            '$.report.results[*].vulnerabilities[*] \? (@.fixedVersions.size() > 0 && !(@.suppressed == true))'
+        )
+    )`),
+}
+
+// vulnerabilitySummaryStore serves the read-only VulnerabilitySummary resource.
+// Only the get, list and watch verbs of the underlying registry store are exposed.
+type vulnerabilitySummaryStore struct {
+	store *registry.Store
+}
+
+// NewVulnerabilitySummaryStore returns a read-only store summarizing the VulnerabilityReports.
+func NewVulnerabilitySummaryStore(
+	scheme *runtime.Scheme,
+	optsGetter generic.RESTOptionsGetter,
+	db Database,
+	watchCache *WatchCacheConfig,
+	logger *slog.Logger,
+) (rest.Storage, error) {
+	// The strategy is required to complete the store, the write verbs are not exposed.
+	strategy := newVulnerabilityReportStrategy(scheme)
+
+	newFunc := func() runtime.Object { return &v1alpha1.VulnerabilitySummary{} }
+	newListFunc := func() runtime.Object { return &v1alpha1.VulnerabilitySummaryList{} }
+
+	objectStore := newProjectionStore(
+		db,
+		"vulnerabilityreports",
+		vulnerabilitySummaryProjection,
+		newFunc,
+		newListFunc,
+		logger.With("store", "vulnerabilitysummary"),
+	)
+
+	store := &registry.Store{
+		NewFunc:                   newFunc,
+		NewListFunc:               newListFunc,
+		PredicateFunc:             matcher,
+		DefaultQualifiedResource:  v1alpha1.Resource("vulnerabilitysummaries"),
+		SingularQualifiedResource: v1alpha1.Resource("vulnerabilitysummary"),
+		Storage: registry.DryRunnableStorage{
+			Storage: objectStore,
+		},
+		DestroyFunc:    objectStore.destroy,
+		CreateStrategy: strategy,
+		UpdateStrategy: strategy,
+		DeleteStrategy: strategy,
+		TableConvertor: &vulnerabilitySummaryTableConvertor{},
+	}
+
+	if err := completeStore(store, optsGetter, watchCache); err != nil {
+		return nil, err
+	}
+
+	return &vulnerabilitySummaryStore{store: store}, nil
+}
+
+// New returns an empty VulnerabilitySummary.
+func (s *vulnerabilitySummaryStore) New() runtime.Object {
+	return s.store.New()
+}
+
+// NewList returns an empty VulnerabilitySummaryList.
+func (s *vulnerabilitySummaryStore) NewList() runtime.Object {
+	return s.store.NewList()
+}
+
+// Destroy stops the watchers of the store.
+func (s *vulnerabilitySummaryStore) Destroy() {
+	s.store.Destroy()
+}
+
+// NamespaceScoped returns true, as a VulnerabilitySummary lives in the namespace of its report.
+func (s *vulnerabilitySummaryStore) NamespaceScoped() bool {
+	return s.store.NamespaceScoped()
+}
+
+// GetSingularName returns the singular name of the resource.
+func (s *vulnerabilitySummaryStore) GetSingularName() string {
+	return s.store.GetSingularName()
+}
+
+// Get returns the summary of the VulnerabilityReport with the given name.
+func (s *vulnerabilitySummaryStore) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return s.store.Get(ctx, name, options) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+// List returns the summaries of the VulnerabilityReports matching the options.
+func (s *vulnerabilitySummaryStore) List(
+	ctx context.Context,
+	options *metainternalversion.ListOptions,
+) (runtime.Object, error) {
+	return s.store.List(ctx, options) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+// Watch watches the summaries of the VulnerabilityReports matching the options.
+func (s *vulnerabilitySummaryStore) Watch(
+	ctx context.Context,
+	options *metainternalversion.ListOptions,
+) (watch.Interface, error) {
+	return s.store.Watch(ctx, options) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+// ConvertToTable converts the summaries to a table.
+func (s *vulnerabilitySummaryStore) ConvertToTable(
+	ctx context.Context,
+	object runtime.Object,
+	tableOptions runtime.Object,
+) (*metav1.Table, error) {
+	return s.store.ConvertToTable(ctx, object, tableOptions) //nolint:wrapcheck // The errors of the registry store are API errors.
+}
+
+type vulnerabilitySummaryTableConvertor struct{}
+
+func (c *vulnerabilitySummaryTableConvertor) ConvertToTable(
+	_ context.Context,
+	obj runtime.Object,
+	_ runtime.Object,
+) (*metav1.Table, error) {
+	columns := append(
+		imageMetadataTableColumns(),
+		metav1.TableColumnDefinition{Name: "Vulnerabilities", Type: "string", Description: "Vulnerabilities"},
+		metav1.TableColumnDefinition{Name: "Fixable", Type: "integer", Description: "Fixable vulnerabilities"},
+		metav1.TableColumnDefinition{Name: "Last Scanned", Type: "date", Description: "Time of the last scan"},
+	)
+
+	table := &metav1.Table{
+		ColumnDefinitions: columns,
+		Rows:              []metav1.TableRow{},
+	}
+
+	// Handle both single object and list
+	var vulnerabilitySummaries []v1alpha1.VulnerabilitySummary
+	switch t := obj.(type) {
+	case *v1alpha1.VulnerabilitySummaryList:
+		vulnerabilitySummaries = t.Items
+	case *v1alpha1.VulnerabilitySummary:
+		vulnerabilitySummaries = []v1alpha1.VulnerabilitySummary{*t}
+	default:
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+
+	for _, vulnerabilitySummary := range vulnerabilitySummaries {
+		cells := append(
+			imageMetadataTableRowCells(vulnerabilitySummary.Name, &vulnerabilitySummary),
+			computeVulnerabilities(vulnerabilitySummary.Summary),
+			vulnerabilitySummary.Fixable,
+			vulnerabilitySummary.LastScannedAt.UTC().Format(time.RFC3339),
+		)
+		row := metav1.TableRow{
+			Object: runtime.RawExtension{Object: &vulnerabilitySummary},
+			Cells:  cells,
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+const vulnerabilitySummaryKeyPrefix = "/storage.sbomscanner.kubewarden.io/vulnerabilitysummaries"
+
+func (suite *storeTestSuite) newVulnerabilitySummaryStore() *store {
+	return newProjectionStore(
+		suite.db,
+		"vulnerabilityreports",
+		vulnerabilitySummaryProjection,
+		func() runtime.Object { return &v1alpha1.VulnerabilitySummary{} },
+		func() runtime.Object { return &v1alpha1.VulnerabilitySummaryList{} },
+		slog.Default(),
+	)
+}
+
+// createTestScannedVulnerabilityReport creates a report scanned for the first time at creationTimestamp
+// and for the last time at lastScanTimestamp.
+func (suite *storeTestSuite) createTestScannedVulnerabilityReport(
+	vulnerabilityReportStore *store,
+	report *v1alpha1.VulnerabilityReport,
+	creationTimestamp, lastScanTimestamp metav1.Time,
+) {
+	report.CreationTimestamp = creationTimestamp
+	report.ManagedFields = []metav1.ManagedFieldsEntry{
+		{
+			Manager:    "sbomscanner",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Time:       &creationTimestamp,
+		},
+		{
+			Manager:    "sbomscanner",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Time:       &lastScanTimestamp,
+		},
+	}
+
+	err := vulnerabilityReportStore.Create(
+		context.Background(),
+		vulnerabilityReportKeyPrefix+"/"+report.Namespace+"/"+report.Name,
+		report,
+		&v1alpha1.VulnerabilityReport{},
+		0,
+	)
+	suite.Require().NoError(err)
+}
+
+func newTestVulnerabilitySummary(report *v1alpha1.VulnerabilityReport, fixable int) *v1alpha1.VulnerabilitySummary {
+	objectMeta := *report.ObjectMeta.DeepCopy()
+	objectMeta.ManagedFields = nil
+
+	return &v1alpha1.VulnerabilitySummary{
+		ObjectMeta:     objectMeta,
+		ImageMetadata:  report.ImageMetadata,
+		Summary:        report.Report.Summary,
+		Fixable:        fixable,
+		FirstScannedAt: report.CreationTimestamp,
+		LastScannedAt:  *report.ManagedFields[len(report.ManagedFields)-1].Time,
+	}
+}
+
+func (suite *storeTestSuite) TestVulnerabilitySummaryStore() {
+	suite.requirePostgres()
+
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+	vulnerabilitySummaryStore := suite.newVulnerabilitySummaryStore()
+	defer vulnerabilitySummaryStore.destroy()
+
+	report1 := newTestVulnerabilityReport("test1", testVulnerability1, testVulnerability2)
+	suite.createTestScannedVulnerabilityReport(
+		vulnerabilityReportStore,
+		report1,
+		metav1.Unix(1700000000, 0),
+		metav1.Unix(1700003600, 0),
+	)
+	report2 := newTestVulnerabilityReport("test2", testVulnerability2)
+	suite.createTestScannedVulnerabilityReport(
+		vulnerabilityReportStore,
+		report2,
+		metav1.Unix(1700000000, 0),
+		metav1.Unix(1700007200, 0),
+	)
+
+	summary := &v1alpha1.VulnerabilitySummary{}
+	err := vulnerabilitySummaryStore.Get(
+		context.Background(),
+		vulnerabilitySummaryKeyPrefix+"/default/test1",
+		storage.GetOptions{},
+		summary,
+	)
+	suite.Require().NoError(err)
+	suite.Equal(newTestVulnerabilitySummary(report1, 1), summary)
+
+	list := &v1alpha1.VulnerabilitySummaryList{}
+	err = vulnerabilitySummaryStore.GetList(
+		context.Background(),
+		vulnerabilitySummaryKeyPrefix+"/default",
+		storage.ListOptions{
+			Predicate: matcher(labels.Everything(), fields.OneTermEqualSelector("imageMetadata.tag", "test2")),
+		},
+		list,
+	)
+	suite.Require().NoError(err)
+	suite.Equal([]v1alpha1.VulnerabilitySummary{*newTestVulnerabilitySummary(report2, 0)}, list.Items)
+}
+
+func (suite *storeTestSuite) TestVulnerabilitySummaryStoreWatch() {
+	suite.requirePostgres()
+
+	vulnerabilityReportStore := suite.newVulnerabilityReportStore()
+	defer vulnerabilityReportStore.destroy()
+	vulnerabilitySummaryStore := suite.newVulnerabilitySummaryStore()
+	defer vulnerabilitySummaryStore.destroy()
+
+	report1 := newTestVulnerabilityReport("test1", testVulnerability1, testVulnerability2)
+	suite.createTestScannedVulnerabilityReport(
+		vulnerabilityReportStore,
+		report1,
+		metav1.Unix(1700000000, 0),
+		metav1.Unix(1700003600, 0),
+	)
+
+	watcher, err := vulnerabilitySummaryStore.Watch(
+		context.Background(),
+		vulnerabilitySummaryKeyPrefix+"/default",
+		storage.ListOptions{ResourceVersion: "0", Predicate: matcher(labels.Everything(), fields.Everything())},
+	)
+	suite.Require().NoError(err)
+
+	report2 := newTestVulnerabilityReport("test2", testVulnerability1)
+	suite.createTestScannedVulnerabilityReport(
+		vulnerabilityReportStore,
+		report2,
+		metav1.Unix(1700000000, 0),
+		metav1.Unix(1700007200, 0),
+	)
+
+	events := collectEvents(watcher, 2)
+	suite.Require().Len(events, 2)
+	suite.Equal(watch.Added, events[0].Type)
+	suite.Equal(newTestVulnerabilitySummary(report1, 1), events[0].Object)
+	suite.Equal(watch.Added, events[1].Type)
+	suite.Equal(newTestVulnerabilitySummary(report2, 1), events[1].Object)
+}
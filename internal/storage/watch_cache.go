@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/storage"
+	cacherstorage "k8s.io/apiserver/pkg/storage/cacher"
+	etcdfeature "k8s.io/apiserver/pkg/storage/feature"
+)
+
+var registerProgressRequestSupportOnce sync.Once
+
+// progressRequestSupportChecker reports the RequestWatchProgress feature as supported,
+// as the store answers the progress requests with bookmarks, and delegates the other features to the default checker.
+//
+// The cacher and the cache delegator of k8s.io/apiserver read the global etcdfeature.DefaultFeatureSupportChecker
+// instead of a field of cacher.Config. The default checker only reports the feature once the etcd3 storage factory
+// has called CheckClient with an etcd client, which never happens as the storage does not use etcd.
+// Without the feature, the watch cache rejects the streaming lists and serves the consistent reads from the store.
+// The checker is only installed once a watch cache is enabled, as no etcd storage runs in this process.
+type progressRequestSupportChecker struct {
+	etcdfeature.FeatureSupportChecker
+}
+
+func (c progressRequestSupportChecker) Supports(feature storage.Feature) bool {
+	return feature == storage.RequestWatchProgress || c.FeatureSupportChecker.Supports(feature)
+}
+
+// registerProgressRequestSupport overrides the global feature support checker of the API server, once.
+func registerProgressRequestSupport() {
+	registerProgressRequestSupportOnce.Do(func() {
+		etcdfeature.DefaultFeatureSupportChecker = progressRequestSupportChecker{
+			FeatureSupportChecker: etcdfeature.DefaultFeatureSupportChecker,
+		}
+	})
+}
+
+// WatchCacheConfig configures the watch cache serving the reads of a resource.
+type WatchCacheConfig struct {
+	// Codec is the codec of the objects of the resource.
+	Codec runtime.Codec
+	// EventsHistoryWindow is the amount of time the watch events are kept before being compacted.
+	// It must be at least cacher.DefaultEventFreshDuration.
+	EventsHistoryWindow time.Duration
+}
+
+// enableWatchCache serves the reads of the registry store from the watch cache of the API server.
+// The cache lists the objects from the store and keeps them up to date by watching it,
+// while the writes are still served by the store.
+// The registry store must be completed. A nil config leaves the store uncached.
+func enableWatchCache(registryStore *registry.Store, config *WatchCacheConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	registerProgressRequestSupport()
+
+	objectStore := registryStore.Storage.Storage
+
+	cacher, err := cacherstorage.NewCacherFromConfig(cacherstorage.Config{
+		Storage:             objectStore,
+		Versioner:           objectStore.Versioner(),
+		GroupResource:       registryStore.DefaultQualifiedResource,
+		EventsHistoryWindow: config.EventsHistoryWindow,
+		ResourcePrefix:      registryStore.KeyRootFunc(genericapirequest.NewContext()),
+		KeyFunc: func(obj runtime.Object) (string, error) {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return "", err
+			}
+			ctx := genericapirequest.WithNamespace(genericapirequest.NewContext(), accessor.GetNamespace())
+
+			return registryStore.KeyFunc(ctx, accessor.GetName())
+		},
+		GetAttrsFunc: getAttrs,
+		NewFunc:      registryStore.NewFunc,
+		NewListFunc:  registryStore.NewListFunc,
+		Codec:        config.Codec,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create the watch cache of %s: %w", registryStore.DefaultQualifiedResource, err)
+	}
+	delegator := cacherstorage.NewCacheDelegator(cacher, objectStore)
+
+	destroyStore := registryStore.DestroyFunc
+	var once sync.Once
+	registryStore.DestroyFunc = func() {
+		once.Do(func() {
+			delegator.Stop()
+			cacher.Stop()
+			destroyStore()
+		})
+	}
+	registryStore.Storage.Storage = delegator
+	registryStore.ReadinessCheckFunc = delegator.ReadinessCheck
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/cacher/delegator"
+	etcdfeature "k8s.io/apiserver/pkg/storage/feature"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubewarden/sbomscanner/api/storage/v1alpha1"
+)
+
+const imageKeyPrefix = "/storage.sbomscanner.kubewarden.io/images"
+
+// newCachedImageStore returns an Image registry store served from the watch cache, once the cache is ready.
+func (suite *storeTestSuite) newCachedImageStore() *RegistryStore {
+	scheme := runtime.NewScheme()
+	suite.Require().NoError(v1alpha1.AddToScheme(scheme))
+
+	imageStore, err := NewImageStore(
+		scheme,
+		generic.RESTOptions{ResourcePrefix: imageKeyPrefix},
+		suite.db,
+		&WatchCacheConfig{
+			Codec:               serializer.NewCodecFactory(scheme).LegacyCodec(v1alpha1.SchemeGroupVersion),
+			EventsHistoryWindow: DefaultWatchEventsRetention,
+		},
+		slog.Default(),
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().Eventually(func() bool {
+		return imageStore.Storage.Storage.ReadinessCheck() == nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	return imageStore
+}
+
+func newTestImage(name string) *v1alpha1.Image {
+	return &v1alpha1.Image{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		ImageMetadata: v1alpha1.ImageMetadata{
+			Registry:   "registry",
+			Repository: "repository",
+			Tag:        name,
+		},
+	}
+}
+
+func (suite *storeTestSuite) TestWatchCache() {
+	imageStore := suite.newCachedImageStore()
+	defer imageStore.DestroyFunc()
+
+	image1 := newTestImage("test1")
+	err := imageStore.Storage.Storage.Create(
+		context.Background(),
+		imageKeyPrefix+"/default/test1",
+		image1,
+		&v1alpha1.Image{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	// A consistent list waits for the cache to observe the current resourceVersion.
+	list := &v1alpha1.ImageList{}
+	err = imageStore.Storage.Storage.GetList(context.Background(), imageKeyPrefix, storage.ListOptions{
+		Recursive: true,
+		Predicate: storage.Everything,
+	}, list)
+	suite.Require().NoError(err)
+	suite.Equal([]v1alpha1.Image{*image1}, list.Items)
+
+	// Remove the objects behind the cache, the lists from resourceVersion 0 are still served from memory.
+	_, err = suite.db.Exec(context.Background(), "DELETE FROM images")
+	suite.Require().NoError(err)
+
+	list = &v1alpha1.ImageList{}
+	err = imageStore.Storage.Storage.GetList(context.Background(), imageKeyPrefix, storage.ListOptions{
+		ResourceVersion: "0",
+		Recursive:       true,
+		Predicate:       storage.Everything,
+	}, list)
+	suite.Require().NoError(err)
+	suite.Equal([]v1alpha1.Image{*image1}, list.Items)
+}
+
+func (suite *storeTestSuite) TestWatchCacheSendInitialEvents() {
+	imageStore := suite.newCachedImageStore()
+	defer imageStore.DestroyFunc()
+
+	image1 := newTestImage("test1")
+	err := imageStore.Storage.Storage.Create(
+		context.Background(),
+		imageKeyPrefix+"/default/test1",
+		image1,
+		&v1alpha1.Image{},
+		0,
+	)
+	suite.Require().NoError(err)
+
+	predicate := storage.Everything
+	predicate.AllowWatchBookmarks = true
+	watcher, err := imageStore.Storage.Storage.Watch(context.Background(), imageKeyPrefix, storage.ListOptions{
+		ResourceVersion:      "",
+		ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		SendInitialEvents:    ptr.To(true),
+		Recursive:            true,
+		Predicate:            predicate,
+	})
+	suite.Require().NoError(err)
+
+	events := collectEvents(watcher, 2)
+	suite.Require().Len(events, 2)
+	suite.Equal(watch.Added, events[0].Type)
+	suite.Equal(image1, events[0].Object)
+	suite.Equal(watch.Bookmark, events[1].Type)
+	bookmark, ok := events[1].Object.(*v1alpha1.Image)
+	suite.Require().True(ok)
+	suite.Equal(image1.ResourceVersion, bookmark.ResourceVersion)
+	suite.Equal("true", bookmark.Annotations[metav1.InitialEventsAnnotationKey])
+}
+
+func TestRegisterProgressRequestSupport(t *testing.T) {
+	upstream := etcdfeature.DefaultFeatureSupportChecker
+	if checker, ok := upstream.(progressRequestSupportChecker); ok {
+		upstream = checker.FeatureSupportChecker
+	}
+	// The default checker only reports the feature after checking an etcd client.
+	// Once it no longer does, the override can be dropped.
+	assert.False(t, upstream.Supports(storage.RequestWatchProgress))
+
+	registerProgressRequestSupport()
+	registerProgressRequestSupport()
+
+	checker, ok := etcdfeature.DefaultFeatureSupportChecker.(progressRequestSupportChecker)
+	require.True(t, ok)
+	assert.Equal(t, upstream, checker.FeatureSupportChecker)
+	assert.True(t, etcdfeature.DefaultFeatureSupportChecker.Supports(storage.RequestWatchProgress))
+	assert.True(t, delegator.ConsistentReadSupported())
+}
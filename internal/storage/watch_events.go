@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/im"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// eventSchema is the schema of an event in the watch_events table.
+type eventSchema struct {
+	ResourceVersion uint64 `db:"resource_version"`
+	Type            string `db:"type"`
+	Name            string `db:"name"`
+	Namespace       string `db:"namespace"`
+	Object          []byte `db:"object"`
+}
+
+// currentResourceVersion returns the resourceVersion of the latest committed event.
+// The latest event is never compacted, so the value is stable across compactions.
+// An empty log is at resourceVersion 1, like an empty etcd, as the resourceVersion of a list cannot be 0.
+func currentResourceVersion(ctx context.Context, q querier) (uint64, error) {
+	query, args, err := psql.Select(
+		sm.Columns(psql.Raw("COALESCE(MAX(resource_version), 1)")),
+		sm.From("watch_events"),
+	).Build(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var resourceVersion int64
+	if err = q.QueryRow(ctx, query, args...).Scan(&resourceVersion); err != nil {
+		return 0, fmt.Errorf("failed to get current resource version: %w", err)
+	}
+
+	return uint64(resourceVersion), nil //nolint:gosec // resource versions are always positive
+}
+
+// compactedResourceVersion returns the highest resourceVersion whose events are no longer
+// available in the log.
+// Watches starting at or after this resourceVersion can be resumed.
+func compactedResourceVersion(ctx context.Context, q querier) (uint64, error) {
+	query, args, err := psql.Select(
+		sm.Columns(psql.Raw("COALESCE(MIN(resource_version) - 1, 0)")),
+		sm.From("watch_events"),
+	).Build(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var resourceVersion int64
+	if err = q.QueryRow(ctx, query, args...).Scan(&resourceVersion); err != nil {
+		return 0, fmt.Errorf("failed to get compacted resource version: %w", err)
+	}
+
+	return uint64(resourceVersion), nil //nolint:gosec // resource versions are always positive
+}
+
+// recordEvent appends an event to the watch_events table.
+func recordEvent(
+	ctx context.Context,
+	tx transaction,
+	resource string,
+	eventType watch.EventType,
+	resourceVersion uint64,
+	name, namespace string,
+	object []byte,
+) error {
+	return recordEvents(ctx, tx, resource, []eventSchema{{
+		ResourceVersion: resourceVersion,
+		Type:            string(eventType),
+		Name:            name,
+		Namespace:       namespace,
+		Object:          object,
+	}})
+}
+
+// recordEvents appends the events of the resource to the watch_events table,
+// notifying the listeners once for all of them.
+func recordEvents(ctx context.Context, tx transaction, resource string, events []eventSchema) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if len(events) == 1 {
+		event := events[0]
+		query, args, err := psql.Insert(
+			im.Into("watch_events", "resource_version", "resource", "type", "name", "namespace", "object"),
+			im.Values(
+				psql.Arg(event.ResourceVersion),
+				psql.Arg(resource),
+				psql.Arg(event.Type),
+				psql.Arg(event.Name),
+				psql.Arg(event.Namespace),
+				psql.Arg(event.Object),
+			),
+		).Build(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to record %s event: %w", event.Type, err)
+		}
+	} else {
+		rows := make([][]any, len(events))
+		for i, event := range events {
+			rows[i] = []any{
+				int64(event.ResourceVersion), //nolint:gosec // resource versions are stored as BIGINT
+				resource,
+				event.Type,
+				event.Name,
+				event.Namespace,
+				event.Object,
+			}
+		}
+
+		if err := tx.copyFrom(
+			ctx,
+			"watch_events",
+			[]string{"resource_version", "resource", "type", "name", "namespace", "object"},
+			rows,
+		); err != nil {
+			return fmt.Errorf("failed to record %d events: %w", len(events), err)
+		}
+	}
+
+	if err := tx.notify(ctx, resource); err != nil {
+		return fmt.Errorf("failed to notify %s events: %w", resource, err)
+	}
+
+	return nil
+}
+
+// fetchEvents returns the events of the given resource with a resourceVersion in the (from, to] range,
+// ordered by resourceVersion.
+// If to is 0 the range is unbounded.
+// The objects of the events are selected with the given column or SQL expression.
+func fetchEvents(
+	ctx context.Context,
+	q querier,
+	resource string,
+	object any,
+	from, to uint64,
+	limit int,
+) ([]eventSchema, error) {
+	queryBuilder := psql.Select(
+		sm.Columns("resource_version", "type", "name", "namespace", object),
+		sm.From("watch_events"),
+		sm.Where(psql.Quote("resource").EQ(psql.Arg(resource))),
+		sm.Where(psql.Quote("resource_version").GT(psql.Arg(from))),
+		sm.OrderBy("resource_version"),
+		sm.Limit(limit),
+	)
+	if to != 0 {
+		queryBuilder.Apply(sm.Where(psql.Quote("resource_version").LTE(psql.Arg(to))))
+	}
+
+	query, args, err := queryBuilder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []eventSchema
+	for rows.Next() {
+		var event eventSchema
+		if err = rows.Scan(
+			&event.ResourceVersion,
+			&event.Type,
+			&event.Name,
+			&event.Namespace,
+			&event.Object,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+
+	return events, nil
+}
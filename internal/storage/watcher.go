@@ -0,0 +1,795 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+const (
+	// eventsBatchSize is the maximum number of events fetched from the log in a single query.
+	eventsBatchSize = 500
+	// watcherBufferSize is the size of the buffer of events waiting to be processed by a watcher.
+	watcherBufferSize = 100
+	// dispatchTimeout is the maximum time the dispatch of an event waits for the watchers with a full buffer.
+	// The watchers still blocked after it are terminated, so that they cannot stall the other watchers,
+	// unless they are replaying the log, in which case they replay the events they missed too.
+	dispatchTimeout = 100 * time.Millisecond
+	// retryInterval is the time to wait before retrying to tail the events log after a failure.
+	retryInterval = time.Second
+	// bookmarkInterval is the interval between the bookmarks sent to the watchers allowing them,
+	// the same the watch cache of the Kubernetes API server uses.
+	bookmarkInterval = time.Minute
+)
+
+// watchEvent is an event read from the watch_events table, decoded once and shared by all the watchers.
+type watchEvent struct {
+	resourceVersion uint64
+	eventType       watch.EventType
+	name            string
+	namespace       string
+	object          runtime.Object
+}
+
+// eventBroadcaster tails the watch_events table of a resource and fans out the events to the watchers.
+// Writers only wake the broadcaster up after committing, so they are never blocked by the watchers.
+// Writes served by other storage replicas wake the broadcaster up through the database notifications,
+// so that every replica delivers every event to its watchers.
+type eventBroadcaster struct {
+	db       Database
+	resource string
+	// object is the column, or the SQL expression, selecting the objects of the events.
+	object  any
+	newFunc func() runtime.Object
+	// events is nil if the objects of the events are stored as they are served.
+	events eventHooks
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	watchers map[int64]*watcher
+	nextID   int64
+	// resourceVersion is the resourceVersion of the last event dispatched to the watchers.
+	resourceVersion uint64
+	// initialized reports whether resourceVersion is tracking the log.
+	// It is reset when the last watcher goes away, to avoid tailing the log when nobody is listening.
+	initialized bool
+	// generation is incremented every time resourceVersion is reinitialized,
+	// to discard the batches fetched before.
+	generation int64
+
+	wakeup chan struct{}
+	// progress is signaled when a bookmark has to be sent to the watchers as soon as possible.
+	progress  chan struct{}
+	startOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func newEventBroadcaster(
+	db Database,
+	resource string,
+	object any,
+	newFunc func() runtime.Object,
+	events eventHooks,
+	logger *slog.Logger,
+) *eventBroadcaster {
+	registerMetrics()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &eventBroadcaster{
+		db:       db,
+		resource: resource,
+		object:   object,
+		newFunc:  newFunc,
+		events:   events,
+		logger:   logger,
+		watchers: map[int64]*watcher{},
+		wakeup:   make(chan struct{}, 1),
+		progress: make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// notify wakes up the broadcaster, signaling that new events might be available in the log.
+func (b *eventBroadcaster) notify() {
+	select {
+	case b.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// requestProgress asks the broadcaster to send a bookmark with the current resourceVersion
+// to the watchers allowing them, after dispatching the pending events.
+func (b *eventBroadcaster) requestProgress() {
+	select {
+	case b.progress <- struct{}{}:
+	default:
+	}
+}
+
+// shutdown stops the broadcaster and all the watchers.
+func (b *eventBroadcaster) shutdown() {
+	b.cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, w := range b.watchers {
+		w.stop()
+		b.remove(w)
+	}
+}
+
+// watch registers a new watcher that receives the initial events first,
+// followed by the events with a resourceVersion greater than startResourceVersion.
+// If bookmarks is true, the watcher also receives the periodic and the requested bookmarks.
+func (b *eventBroadcaster) watch(
+	ctx context.Context,
+	startResourceVersion uint64,
+	initialEvents []watch.Event,
+	namespace, name string,
+	predicate storage.SelectionPredicate,
+	bookmarks bool,
+) (watch.Interface, error) {
+	b.startOnce.Do(func() {
+		go b.run()
+		go b.listen()
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ctx.Err() != nil {
+		return nil, storage.NewInternalError(errors.New("watch events broadcaster is shut down"))
+	}
+
+	if !b.initialized {
+		resourceVersion, err := currentResourceVersion(ctx, b.db)
+		if err != nil {
+			return nil, storage.NewInternalError(err)
+		}
+		b.resourceVersion = resourceVersion
+		b.initialized = true
+		b.generation++
+	}
+
+	w := &watcher{
+		id:                   b.nextID,
+		broadcaster:          b,
+		namespace:            namespace,
+		name:                 name,
+		predicate:            predicate,
+		bookmarks:            bookmarks,
+		startResourceVersion: startResourceVersion,
+		resourceVersion:      startResourceVersion,
+		replaying:            true,
+		incoming:             make(chan *watchEvent, watcherBufferSize),
+		result:               make(chan watch.Event),
+		done:                 make(chan struct{}),
+		stopped:              make(chan struct{}),
+	}
+	b.nextID++
+	b.watchers[w.id] = w
+	watchersGauge.WithLabelValues(b.resource).Inc()
+
+	// The events up to the broadcaster resourceVersion have already been dispatched,
+	// the watcher has to replay them from the log.
+	var replayUntil uint64
+	if startResourceVersion < b.resourceVersion {
+		replayUntil = b.resourceVersion
+	}
+
+	go w.run(ctx, initialEvents, replayUntil)
+
+	// Make sure the events committed after startResourceVersion are picked up.
+	b.notify()
+
+	return w, nil
+}
+
+// unregister removes the watcher from the broadcaster.
+func (b *eventBroadcaster) unregister(w *watcher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(w)
+}
+
+// remove removes the watcher from the watchers map.
+// It must be called with the lock held.
+func (b *eventBroadcaster) remove(w *watcher) {
+	if _, ok := b.watchers[w.id]; !ok {
+		return
+	}
+
+	delete(b.watchers, w.id)
+	watchersGauge.WithLabelValues(b.resource).Dec()
+
+	if len(b.watchers) == 0 {
+		b.initialized = false
+	}
+}
+
+// run tails the events log every time the broadcaster is woken up.
+// It also sends the bookmarks, periodically and when the progress is requested.
+func (b *eventBroadcaster) run() {
+	ticker := time.NewTicker(bookmarkInterval)
+	defer ticker.Stop()
+
+	for {
+		bookmark := false
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-b.wakeup:
+		case <-ticker.C:
+			bookmark = true
+		case <-b.progress:
+			bookmark = true
+		}
+
+		if err := b.dispatchPending(b.ctx); err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			b.logger.ErrorContext(b.ctx, "failed to dispatch watch events", "error", err)
+
+			// Retry later, the events are still in the log.
+			time.AfterFunc(retryInterval, b.notify)
+		}
+
+		// The bookmark is valid even if the dispatch failed, as it carries the resourceVersion
+		// of the last dispatched event.
+		if bookmark {
+			b.sendBookmarks()
+		}
+	}
+}
+
+// listen wakes up the broadcaster every time another replica records an event of the resource.
+// The connection is re-established on failure, waking up the broadcaster to pick up the events
+// recorded while it was down.
+func (b *eventBroadcaster) listen() {
+	for {
+		err := b.waitForNotifications(b.ctx)
+		if b.ctx.Err() != nil {
+			return
+		}
+		b.logger.ErrorContext(b.ctx, "failed to listen for watch events notifications", "error", err)
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (b *eventBroadcaster) waitForNotifications(ctx context.Context) error {
+	return b.db.listen(
+		ctx,
+		// Events might have been recorded while the broadcaster was not listening.
+		b.notify,
+		func(resource string) {
+			if resource == b.resource {
+				b.notify()
+			}
+		},
+	)
+}
+
+// dispatchPending reads the events committed after the last dispatched one and sends them to the watchers.
+func (b *eventBroadcaster) dispatchPending(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		initialized, resourceVersion, generation := b.initialized, b.resourceVersion, b.generation
+		b.mu.Unlock()
+
+		if !initialized {
+			return nil
+		}
+
+		events, compacted, current, err := b.fetch(ctx, resourceVersion)
+		if err != nil {
+			return err
+		}
+
+		b.mu.Lock()
+		if b.generation != generation {
+			// The broadcaster was reinitialized while fetching, discard the batch.
+			b.mu.Unlock()
+			continue
+		}
+
+		if compacted > b.resourceVersion {
+			// The events were compacted before being dispatched, the watchers cannot be kept consistent.
+			b.logger.WarnContext(ctx, "Watch events compacted before dispatching, terminating watchers",
+				"resourceVersion", b.resourceVersion, "compactedResourceVersion", compacted)
+			for _, w := range b.watchers {
+				w.terminate(apierrors.NewResourceExpired(
+					fmt.Sprintf("too old resource version: %d (%d)", b.resourceVersion, compacted)))
+			}
+			b.resourceVersion = compacted
+		}
+
+		for _, event := range events {
+			b.dispatch(ctx, event)
+			b.resourceVersion = event.resourceVersion
+		}
+
+		caughtUp := len(events) < eventsBatchSize
+		if caughtUp {
+			// The resourceVersions become visible in order, so every event of the resource up to
+			// the current resourceVersion of the snapshot has been dispatched.
+			// Tracking it lets the bookmarks progress while only other resources are written.
+			b.resourceVersion = max(b.resourceVersion, current)
+		}
+		b.mu.Unlock()
+
+		if caughtUp {
+			return nil
+		}
+	}
+}
+
+// dispatch sends the event to the watchers.
+// The watchers with a full buffer share a dispatchTimeout budget, the ones still blocked after it are dropped.
+// The replaying watchers with a full buffer are not waited for: they miss the event, and replay it from the log.
+// It must be called with the lock held.
+func (b *eventBroadcaster) dispatch(ctx context.Context, event *watchEvent) {
+	var blocked []*watcher
+	for _, w := range b.watchers {
+		// The events following a missed one are replayed from the log too, so that the watcher keeps their order.
+		if w.missed {
+			continue
+		}
+		if !w.add(event) {
+			if w.replaying {
+				w.missed = true
+				continue
+			}
+			blocked = append(blocked, w)
+		}
+	}
+	if len(blocked) == 0 {
+		return
+	}
+
+	timer := time.NewTimer(dispatchTimeout)
+	defer timer.Stop()
+
+	timedOut := false
+	for _, w := range blocked {
+		if !timedOut && w.addWithTimeout(event, timer.C) {
+			continue
+		}
+		timedOut = true
+
+		if !w.add(event) {
+			b.drop(ctx, w, event.resourceVersion)
+		}
+	}
+}
+
+// sendBookmarks sends a bookmark with the resourceVersion of the last dispatched event
+// to the watchers allowing them.
+// Bookmarks are best effort, the watchers with a full buffer skip them.
+func (b *eventBroadcaster) sendBookmarks() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.initialized {
+		return
+	}
+
+	event := &watchEvent{resourceVersion: b.resourceVersion, eventType: watch.Bookmark}
+	for _, w := range b.watchers {
+		// A bookmark must not be delivered before the events it follows that the watcher missed.
+		if w.bookmarks && !w.missed {
+			w.add(event)
+		}
+	}
+}
+
+// catchUp ends the replay of the watcher, if it did not miss any event while replaying.
+// Otherwise it returns the resourceVersion up to which the watcher has to replay the log again, and false.
+func (b *eventBroadcaster) catchUp(w *watcher) (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !w.missed {
+		w.replaying = false
+		return 0, true
+	}
+
+	// The missed events have all been dispatched, so they are up to the broadcaster resourceVersion.
+	w.missed = false
+	return b.resourceVersion, false
+}
+
+// drop terminates a watcher that is too slow to keep up with the events.
+// It must be called with the lock held.
+func (b *eventBroadcaster) drop(ctx context.Context, w *watcher, resourceVersion uint64) {
+	b.logger.WarnContext(ctx, "Terminating watcher too slow to keep up with the events",
+		"resourceVersion", resourceVersion, "bufferSize", watcherBufferSize)
+
+	w.terminate(apierrors.NewResourceExpired(
+		fmt.Sprintf("too old resource version: the watcher fell behind at resource version %d", resourceVersion)))
+	b.remove(w)
+	droppedWatchersCounter.WithLabelValues(b.resource).Inc()
+}
+
+// fetch reads a batch of events committed after resourceVersion,
+// together with the compacted and the current resourceVersions observed in the same snapshot.
+func (b *eventBroadcaster) fetch(ctx context.Context, resourceVersion uint64) ([]*watchEvent, uint64, uint64, error) {
+	tx, err := b.db.beginSnapshot(ctx)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			b.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	compacted, err := compactedResourceVersion(ctx, tx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	current, err := currentResourceVersion(ctx, tx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	records, err := fetchEvents(ctx, tx, b.resource, b.object, max(resourceVersion, compacted), 0, eventsBatchSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	events, err := b.decode(ctx, tx, records)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return events, compacted, current, nil
+}
+
+// replay reads the events in the (from, to] range.
+// It returns a ResourceExpired error if part of the range has been compacted.
+func (b *eventBroadcaster) replay(ctx context.Context, from, to uint64, fn func(*watchEvent) bool) error {
+	tx, err := b.db.beginSnapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(ctx); err != nil {
+			b.logger.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	compacted, err := compactedResourceVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if from < compacted {
+		return apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d (%d)", from, compacted))
+	}
+
+	for from < to {
+		records, err := fetchEvents(ctx, tx, b.resource, b.object, from, to, eventsBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		events, err := b.decode(ctx, tx, records)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if !fn(event) {
+				return nil
+			}
+		}
+
+		from = events[len(events)-1].resourceVersion
+	}
+
+	return nil
+}
+
+// decode unmarshals the objects of the events, and restores the parts shared with the other events.
+func (b *eventBroadcaster) decode(ctx context.Context, q querier, records []eventSchema) ([]*watchEvent, error) {
+	events := make([]*watchEvent, 0, len(records))
+	objs := make([]runtime.Object, 0, len(records))
+	for _, record := range records {
+		obj := b.newFunc()
+		if err := json.Unmarshal(record.Object, obj); err != nil {
+			return nil, fmt.Errorf("failed to decode event %d: %w", record.ResourceVersion, err)
+		}
+		objs = append(objs, obj)
+
+		events = append(events, &watchEvent{
+			resourceVersion: record.ResourceVersion,
+			eventType:       watch.EventType(record.Type),
+			name:            record.Name,
+			namespace:       record.Namespace,
+			object:          obj,
+		})
+	}
+
+	if b.events != nil {
+		if err := b.events.hydrateEvents(ctx, q, objs); err != nil {
+			return nil, fmt.Errorf("failed to decode events: %w", err)
+		}
+	}
+
+	return events, nil
+}
+
+var _ watch.Interface = &watcher{}
+
+// watcher is a single watch.Interface served by the eventBroadcaster.
+type watcher struct {
+	id          int64
+	broadcaster *eventBroadcaster
+
+	namespace            string
+	name                 string
+	predicate            storage.SelectionPredicate
+	bookmarks            bool
+	startResourceVersion uint64
+	// resourceVersion is the resourceVersion of the last event processed by the watcher.
+	// It is only accessed by the goroutine of the watcher.
+	resourceVersion uint64
+
+	// replaying reports whether the watcher is sending the initial events or replaying the log,
+	// and missed whether the broadcaster stopped queuing its events meanwhile, as its buffer was full.
+	// They are guarded by the lock of the broadcaster.
+	replaying bool
+	missed    bool
+
+	incoming chan *watchEvent
+	result   chan watch.Event
+	// done is closed when the watcher stops delivering events.
+	done     chan struct{}
+	stopOnce sync.Once
+	// stopped is closed when the consumer stops the watcher.
+	stopped     chan struct{}
+	stoppedOnce sync.Once
+
+	errMu sync.Mutex
+	err   error
+}
+
+// Stop stops the watcher and releases its resources.
+func (w *watcher) Stop() {
+	w.stoppedOnce.Do(func() {
+		close(w.stopped)
+	})
+	w.stop()
+	w.broadcaster.unregister(w)
+}
+
+// ResultChan returns the channel where the events are delivered.
+func (w *watcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *watcher) stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+}
+
+// terminate stops the watcher, delivering the given error as the last event.
+func (w *watcher) terminate(err error) {
+	w.errMu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.errMu.Unlock()
+
+	w.stop()
+}
+
+// error returns the error the watcher was terminated with, if any.
+func (w *watcher) error() error {
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+
+	return w.err
+}
+
+// add queues an event to be processed by the watcher without blocking.
+// It returns false if the buffer of the watcher is full.
+func (w *watcher) add(event *watchEvent) bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+	}
+
+	select {
+	case w.incoming <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// addWithTimeout queues an event to be processed by the watcher, waiting until the timeout if the buffer is full.
+// It returns false if the timeout expires.
+func (w *watcher) addWithTimeout(event *watchEvent, timeout <-chan time.Time) bool {
+	select {
+	case w.incoming <- event:
+		return true
+	case <-w.done:
+		return true
+	case <-timeout:
+		return false
+	}
+}
+
+func (w *watcher) run(ctx context.Context, initialEvents []watch.Event, replayUntil uint64) {
+	defer func() {
+		w.stop()
+		w.broadcaster.unregister(w)
+		close(w.result)
+	}()
+
+	if err := w.serve(ctx, initialEvents, replayUntil); err != nil {
+		w.sendError(ctx, err)
+	}
+}
+
+// serve delivers the events until the watcher is stopped.
+// It returns the error the watcher was terminated with, if any.
+func (w *watcher) serve(ctx context.Context, initialEvents []watch.Event, replayUntil uint64) error {
+	for _, event := range initialEvents {
+		if !w.send(ctx, event) {
+			return w.error()
+		}
+	}
+
+	// The buffer is not drained while the watcher replays the log, so the events it missed are replayed too,
+	// until the buffer holds all the events following the replayed ones.
+	for {
+		if replayUntil > w.resourceVersion {
+			err := w.broadcaster.replay(ctx, w.resourceVersion, replayUntil, func(event *watchEvent) bool {
+				return w.process(ctx, event)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		var caughtUp bool
+		if replayUntil, caughtUp = w.broadcaster.catchUp(w); caughtUp {
+			break
+		}
+	}
+
+	for {
+		select {
+		case event := <-w.incoming:
+			if !w.process(ctx, event) {
+				return w.error()
+			}
+		case <-w.done:
+			return w.error()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// process filters the event and sends it to the result channel.
+// It returns false when the watcher is stopped.
+func (w *watcher) process(ctx context.Context, event *watchEvent) bool {
+	if event.resourceVersion <= w.startResourceVersion {
+		return true
+	}
+	// The events queued before being replayed from the log have already been processed,
+	// and the bookmarks queued before them would move the watcher back.
+	if event.eventType == watch.Bookmark {
+		if event.resourceVersion < w.resourceVersion {
+			return true
+		}
+		return w.sendBookmark(ctx, event.resourceVersion)
+	}
+	if event.resourceVersion <= w.resourceVersion {
+		return true
+	}
+	w.resourceVersion = event.resourceVersion
+
+	if w.namespace != "" && event.namespace != w.namespace {
+		return true
+	}
+	if w.name != "" && event.name != w.name {
+		return true
+	}
+
+	matches, err := matches(w.predicate, event.object)
+	if err != nil {
+		w.broadcaster.logger.ErrorContext(ctx, "failed to match watch event", "error", err)
+		return true
+	}
+	if !matches {
+		return true
+	}
+
+	return w.send(ctx, watch.Event{Type: event.eventType, Object: event.object})
+}
+
+func (w *watcher) send(ctx context.Context, event watch.Event) bool {
+	select {
+	case w.result <- event:
+		return true
+	case <-w.done:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sendBookmark sends a bookmark event, carrying only the resourceVersion in an empty object.
+// It returns false when the watcher is stopped.
+func (w *watcher) sendBookmark(ctx context.Context, resourceVersion uint64) bool {
+	obj := w.broadcaster.newFunc()
+	if err := (storage.APIObjectVersioner{}).UpdateObject(obj, resourceVersion); err != nil {
+		w.broadcaster.logger.ErrorContext(ctx, "failed to create bookmark", "error", err)
+		return true
+	}
+
+	return w.send(ctx, watch.Event{Type: watch.Bookmark, Object: obj})
+}
+
+func (w *watcher) sendError(ctx context.Context, err error) {
+	var statusErr *apierrors.StatusError
+	if !errors.As(err, &statusErr) {
+		statusErr = apierrors.NewInternalError(err)
+	}
+
+	// The error is delivered even if the watcher is terminated, until the consumer stops it.
+	select {
+	case w.result <- watch.Event{Type: watch.Error, Object: &statusErr.ErrStatus}:
+	case <-w.stopped:
+	case <-ctx.Done():
+	}
+}
+
+// matches reports whether the object matches the predicate.
+// An unset predicate matches everything.
+func matches(predicate storage.SelectionPredicate, obj runtime.Object) (bool, error) {
+	if predicate.Label == nil && predicate.Field == nil {
+		return true, nil
+	}
+	if predicate.Label == nil {
+		predicate.Label = labels.Everything()
+	}
+	if predicate.Field == nil {
+		predicate.Field = fields.Everything()
+	}
+	if predicate.GetAttrs == nil {
+		predicate.GetAttrs = getAttrs
+	}
+
+	return predicate.Matches(obj)
+}
@@ -2,6 +2,10 @@
 
 package v1alpha1
 
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // VulnerabilityApplyConfiguration represents a declarative configuration of the Vulnerability type for use
 // with apply.
 type VulnerabilityApplyConfiguration struct {
@@ -19,6 +23,7 @@ type VulnerabilityApplyConfiguration struct {
 	CVSS             map[string]CVSSApplyConfiguration `json:"cvss,omitempty"`
 	Suppressed       *bool                             `json:"suppressed,omitempty"`
 	VEXStatus        *VEXStatusApplyConfiguration      `json:"vexStatus,omitempty"`
+	FirstSeen        *v1.Time                          `json:"firstSeen,omitempty"`
 }
 
 // VulnerabilityApplyConfiguration constructs a declarative configuration of the Vulnerability type for use with
@@ -148,3 +153,11 @@ func (b *VulnerabilityApplyConfiguration) WithVEXStatus(value *VEXStatusApplyCon
 	b.VEXStatus = value
 	return b
 }
+
+// WithFirstSeen sets the FirstSeen field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FirstSeen field is set to the value of the last call.
+func (b *VulnerabilityApplyConfiguration) WithFirstSeen(value v1.Time) *VulnerabilityApplyConfiguration {
+	b.FirstSeen = &value
+	return b
+}
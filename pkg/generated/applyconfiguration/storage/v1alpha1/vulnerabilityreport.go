@@ -15,6 +15,7 @@ type VulnerabilityReportApplyConfiguration struct {
 	*v1.ObjectMetaApplyConfiguration `json:"metadata,omitempty"`
 	ImageMetadata                    *ImageMetadataApplyConfiguration `json:"imageMetadata,omitempty"`
 	Report                           *ReportApplyConfiguration        `json:"report,omitempty"`
+	LastChange                       *ReportChangeApplyConfiguration  `json:"lastChange,omitempty"`
 }
 
 // VulnerabilityReport constructs a declarative configuration of the VulnerabilityReport type for use with
@@ -203,6 +204,14 @@ func (b *VulnerabilityReportApplyConfiguration) WithReport(value *ReportApplyCon
 	return b
 }
 
+// WithLastChange sets the LastChange field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastChange field is set to the value of the last call.
+func (b *VulnerabilityReportApplyConfiguration) WithLastChange(value *ReportChangeApplyConfiguration) *VulnerabilityReportApplyConfiguration {
+	b.LastChange = value
+	return b
+}
+
 // GetKind retrieves the value of the Kind field in the declarative configuration.
 func (b *VulnerabilityReportApplyConfiguration) GetKind() *string {
 	return b.TypeMetaApplyConfiguration.Kind
@@ -26,6 +26,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &storagev1alpha1.ImageMetadataApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Report"):
 		return &storagev1alpha1.ReportApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ReportChange"):
+		return &storagev1alpha1.ReportChangeApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Result"):
 		return &storagev1alpha1.ResultApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("SBOM"):
@@ -36,6 +38,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &storagev1alpha1.VEXStatusApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Vulnerability"):
 		return &storagev1alpha1.VulnerabilityApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("VulnerabilityChange"):
+		return &storagev1alpha1.VulnerabilityChangeApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("VulnerabilityReport"):
 		return &storagev1alpha1.VulnerabilityReportApplyConfiguration{}
 
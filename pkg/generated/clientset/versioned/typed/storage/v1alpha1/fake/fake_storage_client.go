@@ -12,18 +12,50 @@ type FakeStorageV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeStorageV1alpha1) CVEs() v1alpha1.CVEInterface {
+	return newFakeCVEs(c)
+}
+
+func (c *FakeStorageV1alpha1) ClusterVulnerabilityRollups() v1alpha1.ClusterVulnerabilityRollupInterface {
+	return newFakeClusterVulnerabilityRollups(c)
+}
+
 func (c *FakeStorageV1alpha1) Images(namespace string) v1alpha1.ImageInterface {
 	return newFakeImages(c, namespace)
 }
 
+func (c *FakeStorageV1alpha1) ImageBatches(namespace string) v1alpha1.ImageBatchInterface {
+	return newFakeImageBatches(c, namespace)
+}
+
+func (c *FakeStorageV1alpha1) PackageSearches() v1alpha1.PackageSearchInterface {
+	return newFakePackageSearches(c)
+}
+
 func (c *FakeStorageV1alpha1) SBOMs(namespace string) v1alpha1.SBOMInterface {
 	return newFakeSBOMs(c, namespace)
 }
 
+func (c *FakeStorageV1alpha1) SBOMComparisons(namespace string) v1alpha1.SBOMComparisonInterface {
+	return newFakeSBOMComparisons(c, namespace)
+}
+
+func (c *FakeStorageV1alpha1) VulnerabilityHistories(namespace string) v1alpha1.VulnerabilityHistoryInterface {
+	return newFakeVulnerabilityHistories(c, namespace)
+}
+
 func (c *FakeStorageV1alpha1) VulnerabilityReports(namespace string) v1alpha1.VulnerabilityReportInterface {
 	return newFakeVulnerabilityReports(c, namespace)
 }
 
+func (c *FakeStorageV1alpha1) VulnerabilityRollups(namespace string) v1alpha1.VulnerabilityRollupInterface {
+	return newFakeVulnerabilityRollups(c, namespace)
+}
+
+func (c *FakeStorageV1alpha1) VulnerabilitySummaries(namespace string) v1alpha1.VulnerabilitySummaryInterface {
+	return newFakeVulnerabilitySummaries(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeStorageV1alpha1) RESTClient() rest.Interface {
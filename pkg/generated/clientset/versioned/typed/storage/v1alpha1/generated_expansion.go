@@ -2,8 +2,24 @@
 
 package v1alpha1
 
+type CVEExpansion interface{}
+
+type ClusterVulnerabilityRollupExpansion interface{}
+
 type ImageExpansion interface{}
 
+type ImageBatchExpansion interface{}
+
+type PackageSearchExpansion interface{}
+
 type SBOMExpansion interface{}
 
+type SBOMComparisonExpansion interface{}
+
+type VulnerabilityHistoryExpansion interface{}
+
 type VulnerabilityReportExpansion interface{}
+
+type VulnerabilityRollupExpansion interface{}
+
+type VulnerabilitySummaryExpansion interface{}
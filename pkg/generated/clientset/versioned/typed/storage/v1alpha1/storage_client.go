@@ -12,9 +12,17 @@ import (
 
 type StorageV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	CVEsGetter
+	ClusterVulnerabilityRollupsGetter
 	ImagesGetter
+	ImageBatchesGetter
+	PackageSearchesGetter
 	SBOMsGetter
+	SBOMComparisonsGetter
+	VulnerabilityHistoriesGetter
 	VulnerabilityReportsGetter
+	VulnerabilityRollupsGetter
+	VulnerabilitySummariesGetter
 }
 
 // StorageV1alpha1Client is used to interact with features provided by the storage.sbomscanner.kubewarden.io group.
@@ -22,18 +30,50 @@ type StorageV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *StorageV1alpha1Client) CVEs() CVEInterface {
+	return newCVEs(c)
+}
+
+func (c *StorageV1alpha1Client) ClusterVulnerabilityRollups() ClusterVulnerabilityRollupInterface {
+	return newClusterVulnerabilityRollups(c)
+}
+
 func (c *StorageV1alpha1Client) Images(namespace string) ImageInterface {
 	return newImages(c, namespace)
 }
 
+func (c *StorageV1alpha1Client) ImageBatches(namespace string) ImageBatchInterface {
+	return newImageBatches(c, namespace)
+}
+
+func (c *StorageV1alpha1Client) PackageSearches() PackageSearchInterface {
+	return newPackageSearches(c)
+}
+
 func (c *StorageV1alpha1Client) SBOMs(namespace string) SBOMInterface {
 	return newSBOMs(c, namespace)
 }
 
+func (c *StorageV1alpha1Client) SBOMComparisons(namespace string) SBOMComparisonInterface {
+	return newSBOMComparisons(c, namespace)
+}
+
+func (c *StorageV1alpha1Client) VulnerabilityHistories(namespace string) VulnerabilityHistoryInterface {
+	return newVulnerabilityHistories(c, namespace)
+}
+
 func (c *StorageV1alpha1Client) VulnerabilityReports(namespace string) VulnerabilityReportInterface {
 	return newVulnerabilityReports(c, namespace)
 }
 
+func (c *StorageV1alpha1Client) VulnerabilityRollups(namespace string) VulnerabilityRollupInterface {
+	return newVulnerabilityRollups(c, namespace)
+}
+
+func (c *StorageV1alpha1Client) VulnerabilitySummaries(namespace string) VulnerabilitySummaryInterface {
+	return newVulnerabilitySummaries(c, namespace)
+}
+
 // NewForConfig creates a new StorageV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -41,8 +41,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Storage().V1alpha1().Images().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("sboms"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Storage().V1alpha1().SBOMs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("vulnerabilityhistories"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Storage().V1alpha1().VulnerabilityHistories().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("vulnerabilityreports"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Storage().V1alpha1().VulnerabilityReports().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("vulnerabilitysummaries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Storage().V1alpha1().VulnerabilitySummaries().Informer()}, nil
 
 	}
 
@@ -12,8 +12,12 @@ type Interface interface {
 	Images() ImageInformer
 	// SBOMs returns a SBOMInformer.
 	SBOMs() SBOMInformer
+	// VulnerabilityHistories returns a VulnerabilityHistoryInformer.
+	VulnerabilityHistories() VulnerabilityHistoryInformer
 	// VulnerabilityReports returns a VulnerabilityReportInformer.
 	VulnerabilityReports() VulnerabilityReportInformer
+	// VulnerabilitySummaries returns a VulnerabilitySummaryInformer.
+	VulnerabilitySummaries() VulnerabilitySummaryInformer
 }
 
 type version struct {
@@ -37,7 +41,17 @@ func (v *version) SBOMs() SBOMInformer {
 	return &sBOMInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// VulnerabilityHistories returns a VulnerabilityHistoryInformer.
+func (v *version) VulnerabilityHistories() VulnerabilityHistoryInformer {
+	return &vulnerabilityHistoryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // VulnerabilityReports returns a VulnerabilityReportInformer.
 func (v *version) VulnerabilityReports() VulnerabilityReportInformer {
 	return &vulnerabilityReportInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// VulnerabilitySummaries returns a VulnerabilitySummaryInformer.
+func (v *version) VulnerabilitySummaries() VulnerabilitySummaryInformer {
+	return &vulnerabilitySummaryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
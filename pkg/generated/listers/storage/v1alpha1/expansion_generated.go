@@ -2,6 +2,14 @@
 
 package v1alpha1
 
+// CVEListerExpansion allows custom methods to be added to
+// CVELister.
+type CVEListerExpansion interface{}
+
+// ClusterVulnerabilityRollupListerExpansion allows custom methods to be added to
+// ClusterVulnerabilityRollupLister.
+type ClusterVulnerabilityRollupListerExpansion interface{}
+
 // ImageListerExpansion allows custom methods to be added to
 // ImageLister.
 type ImageListerExpansion interface{}
@@ -18,6 +26,14 @@ type SBOMListerExpansion interface{}
 // SBOMNamespaceLister.
 type SBOMNamespaceListerExpansion interface{}
 
+// VulnerabilityHistoryListerExpansion allows custom methods to be added to
+// VulnerabilityHistoryLister.
+type VulnerabilityHistoryListerExpansion interface{}
+
+// VulnerabilityHistoryNamespaceListerExpansion allows custom methods to be added to
+// VulnerabilityHistoryNamespaceLister.
+type VulnerabilityHistoryNamespaceListerExpansion interface{}
+
 // VulnerabilityReportListerExpansion allows custom methods to be added to
 // VulnerabilityReportLister.
 type VulnerabilityReportListerExpansion interface{}
@@ -25,3 +41,19 @@ type VulnerabilityReportListerExpansion interface{}
 // VulnerabilityReportNamespaceListerExpansion allows custom methods to be added to
 // VulnerabilityReportNamespaceLister.
 type VulnerabilityReportNamespaceListerExpansion interface{}
+
+// VulnerabilityRollupListerExpansion allows custom methods to be added to
+// VulnerabilityRollupLister.
+type VulnerabilityRollupListerExpansion interface{}
+
+// VulnerabilityRollupNamespaceListerExpansion allows custom methods to be added to
+// VulnerabilityRollupNamespaceLister.
+type VulnerabilityRollupNamespaceListerExpansion interface{}
+
+// VulnerabilitySummaryListerExpansion allows custom methods to be added to
+// VulnerabilitySummaryLister.
+type VulnerabilitySummaryListerExpansion interface{}
+
+// VulnerabilitySummaryNamespaceListerExpansion allows custom methods to be added to
+// VulnerabilitySummaryNamespaceLister.
+type VulnerabilitySummaryNamespaceListerExpansion interface{}
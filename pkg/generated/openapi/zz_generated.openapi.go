@@ -13,111 +13,209 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS":                    schema_sbomscanner_api_storage_v1alpha1_CVSS(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image":                   schema_sbomscanner_api_storage_v1alpha1_Image(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageLayer":              schema_sbomscanner_api_storage_v1alpha1_ImageLayer(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageList":               schema_sbomscanner_api_storage_v1alpha1_ImageList(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata":           schema_sbomscanner_api_storage_v1alpha1_ImageMetadata(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Report":                  schema_sbomscanner_api_storage_v1alpha1_Report(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Result":                  schema_sbomscanner_api_storage_v1alpha1_Result(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOM":                    schema_sbomscanner_api_storage_v1alpha1_SBOM(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMList":                schema_sbomscanner_api_storage_v1alpha1_SBOMList(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary":                 schema_sbomscanner_api_storage_v1alpha1_Summary(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus":               schema_sbomscanner_api_storage_v1alpha1_VEXStatus(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Vulnerability":           schema_sbomscanner_api_storage_v1alpha1_Vulnerability(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReport":     schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReport(ref),
-		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReportList": schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReportList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                  schema_pkg_apis_meta_v1_APIGroup(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                              schema_pkg_apis_meta_v1_APIGroupList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                               schema_pkg_apis_meta_v1_APIResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                           schema_pkg_apis_meta_v1_APIResourceList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                               schema_pkg_apis_meta_v1_APIVersions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ApplyOptions":                              schema_pkg_apis_meta_v1_ApplyOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Condition":                                 schema_pkg_apis_meta_v1_Condition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                             schema_pkg_apis_meta_v1_CreateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                             schema_pkg_apis_meta_v1_DeleteOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                  schema_pkg_apis_meta_v1_Duration(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldSelectorRequirement":                  schema_pkg_apis_meta_v1_FieldSelectorRequirement(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                  schema_pkg_apis_meta_v1_FieldsV1(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                schema_pkg_apis_meta_v1_GetOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                 schema_pkg_apis_meta_v1_GroupKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                             schema_pkg_apis_meta_v1_GroupResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                              schema_pkg_apis_meta_v1_GroupVersion(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                  schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                          schema_pkg_apis_meta_v1_GroupVersionKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                      schema_pkg_apis_meta_v1_GroupVersionResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                             schema_pkg_apis_meta_v1_InternalEvent(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                             schema_pkg_apis_meta_v1_LabelSelector(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                  schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                      schema_pkg_apis_meta_v1_List(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                  schema_pkg_apis_meta_v1_ListMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                               schema_pkg_apis_meta_v1_ListOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                        schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                 schema_pkg_apis_meta_v1_MicroTime(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                schema_pkg_apis_meta_v1_ObjectMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                            schema_pkg_apis_meta_v1_OwnerReference(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                     schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                 schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                     schema_pkg_apis_meta_v1_Patch(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                              schema_pkg_apis_meta_v1_PatchOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                             schema_pkg_apis_meta_v1_Preconditions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                 schema_pkg_apis_meta_v1_RootPaths(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                 schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                    schema_pkg_apis_meta_v1_Status(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                               schema_pkg_apis_meta_v1_StatusCause(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                             schema_pkg_apis_meta_v1_StatusDetails(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                     schema_pkg_apis_meta_v1_Table(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                     schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                              schema_pkg_apis_meta_v1_TableOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                  schema_pkg_apis_meta_v1_TableRow(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                         schema_pkg_apis_meta_v1_TableRowCondition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                      schema_pkg_apis_meta_v1_Time(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                 schema_pkg_apis_meta_v1_Timestamp(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                  schema_pkg_apis_meta_v1_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                             schema_pkg_apis_meta_v1_UpdateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                schema_pkg_apis_meta_v1_WatchEvent(ref),
-		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                   schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
-		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                       schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/runtime.Unknown":                                        schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
-		"k8s.io/apimachinery/pkg/version.Info":                                           schema_k8sio_apimachinery_pkg_version_Info(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.AffectedImage":                  schema_sbomscanner_api_storage_v1alpha1_AffectedImage(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVE":                            schema_sbomscanner_api_storage_v1alpha1_CVE(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVECount":                       schema_sbomscanner_api_storage_v1alpha1_CVECount(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVEList":                        schema_sbomscanner_api_storage_v1alpha1_CVEList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS":                           schema_sbomscanner_api_storage_v1alpha1_CVSS(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ClusterVulnerabilityRollup":     schema_sbomscanner_api_storage_v1alpha1_ClusterVulnerabilityRollup(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ClusterVulnerabilityRollupList": schema_sbomscanner_api_storage_v1alpha1_ClusterVulnerabilityRollupList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image":                          schema_sbomscanner_api_storage_v1alpha1_Image(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageBatch":                     schema_sbomscanner_api_storage_v1alpha1_ImageBatch(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageBatchSpec":                 schema_sbomscanner_api_storage_v1alpha1_ImageBatchSpec(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageBatchStatus":               schema_sbomscanner_api_storage_v1alpha1_ImageBatchStatus(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageLayer":                     schema_sbomscanner_api_storage_v1alpha1_ImageLayer(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageList":                      schema_sbomscanner_api_storage_v1alpha1_ImageList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata":                  schema_sbomscanner_api_storage_v1alpha1_ImageMetadata(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageChange":                  schema_sbomscanner_api_storage_v1alpha1_PackageChange(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageMatch":                   schema_sbomscanner_api_storage_v1alpha1_PackageMatch(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageSearch":                  schema_sbomscanner_api_storage_v1alpha1_PackageSearch(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageSearchSpec":              schema_sbomscanner_api_storage_v1alpha1_PackageSearchSpec(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageSearchStatus":            schema_sbomscanner_api_storage_v1alpha1_PackageSearchStatus(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Report":                         schema_sbomscanner_api_storage_v1alpha1_Report(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ReportChange":                   schema_sbomscanner_api_storage_v1alpha1_ReportChange(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Result":                         schema_sbomscanner_api_storage_v1alpha1_Result(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Rollup":                         schema_sbomscanner_api_storage_v1alpha1_Rollup(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOM":                           schema_sbomscanner_api_storage_v1alpha1_SBOM(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMComparison":                 schema_sbomscanner_api_storage_v1alpha1_SBOMComparison(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMComparisonSpec":             schema_sbomscanner_api_storage_v1alpha1_SBOMComparisonSpec(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMComparisonStatus":           schema_sbomscanner_api_storage_v1alpha1_SBOMComparisonStatus(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMList":                       schema_sbomscanner_api_storage_v1alpha1_SBOMList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary":                        schema_sbomscanner_api_storage_v1alpha1_Summary(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus":                      schema_sbomscanner_api_storage_v1alpha1_VEXStatus(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Vulnerability":                  schema_sbomscanner_api_storage_v1alpha1_Vulnerability(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityChange":            schema_sbomscanner_api_storage_v1alpha1_VulnerabilityChange(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityHistory":           schema_sbomscanner_api_storage_v1alpha1_VulnerabilityHistory(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityHistoryList":       schema_sbomscanner_api_storage_v1alpha1_VulnerabilityHistoryList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReport":            schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReport(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReportList":        schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReportList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityRollup":            schema_sbomscanner_api_storage_v1alpha1_VulnerabilityRollup(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityRollupList":        schema_sbomscanner_api_storage_v1alpha1_VulnerabilityRollupList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilitySummary":           schema_sbomscanner_api_storage_v1alpha1_VulnerabilitySummary(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilitySummaryList":       schema_sbomscanner_api_storage_v1alpha1_VulnerabilitySummaryList(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityTimelineEntry":     schema_sbomscanner_api_storage_v1alpha1_VulnerabilityTimelineEntry(ref),
+		"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerableImage":                schema_sbomscanner_api_storage_v1alpha1_VulnerableImage(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                         schema_pkg_apis_meta_v1_APIGroup(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                                     schema_pkg_apis_meta_v1_APIGroupList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                                      schema_pkg_apis_meta_v1_APIResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                                  schema_pkg_apis_meta_v1_APIResourceList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                                      schema_pkg_apis_meta_v1_APIVersions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ApplyOptions":                                     schema_pkg_apis_meta_v1_ApplyOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Condition":                                        schema_pkg_apis_meta_v1_Condition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                                    schema_pkg_apis_meta_v1_CreateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                                    schema_pkg_apis_meta_v1_DeleteOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                         schema_pkg_apis_meta_v1_Duration(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldSelectorRequirement":                         schema_pkg_apis_meta_v1_FieldSelectorRequirement(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                         schema_pkg_apis_meta_v1_FieldsV1(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                       schema_pkg_apis_meta_v1_GetOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                        schema_pkg_apis_meta_v1_GroupKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                                    schema_pkg_apis_meta_v1_GroupResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                                     schema_pkg_apis_meta_v1_GroupVersion(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                         schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                                 schema_pkg_apis_meta_v1_GroupVersionKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                             schema_pkg_apis_meta_v1_GroupVersionResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                                    schema_pkg_apis_meta_v1_InternalEvent(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                                    schema_pkg_apis_meta_v1_LabelSelector(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                         schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                             schema_pkg_apis_meta_v1_List(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                         schema_pkg_apis_meta_v1_ListMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                                      schema_pkg_apis_meta_v1_ListOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                               schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                        schema_pkg_apis_meta_v1_MicroTime(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                       schema_pkg_apis_meta_v1_ObjectMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                                   schema_pkg_apis_meta_v1_OwnerReference(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                            schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                        schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                            schema_pkg_apis_meta_v1_Patch(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                                     schema_pkg_apis_meta_v1_PatchOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                                    schema_pkg_apis_meta_v1_Preconditions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                        schema_pkg_apis_meta_v1_RootPaths(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                        schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                           schema_pkg_apis_meta_v1_Status(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                                      schema_pkg_apis_meta_v1_StatusCause(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                                    schema_pkg_apis_meta_v1_StatusDetails(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                            schema_pkg_apis_meta_v1_Table(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                            schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                                     schema_pkg_apis_meta_v1_TableOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                         schema_pkg_apis_meta_v1_TableRow(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                                schema_pkg_apis_meta_v1_TableRowCondition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                             schema_pkg_apis_meta_v1_Time(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                        schema_pkg_apis_meta_v1_Timestamp(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                         schema_pkg_apis_meta_v1_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                    schema_pkg_apis_meta_v1_UpdateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                       schema_pkg_apis_meta_v1_WatchEvent(ref),
+		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                          schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
+		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                              schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/runtime.Unknown":                                               schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
+		"k8s.io/apimachinery/pkg/version.Info":                                                  schema_k8sio_apimachinery_pkg_version_Info(ref),
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_CVSS(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_AffectedImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CVSS holds Common Vulnerability Scoring System data for a vulnerability.",
+				Description: "AffectedImage is a vulnerable package found in an image",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"v3vector": {
+					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "V3Vector string (e.g., \"AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H\")",
+							Description: "Namespace of the VulnerabilityReport",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"v3score": {
+					"vulnerabilityReport": {
 						SchemaProps: spec.SchemaProps{
-							Description: "V3Score numerical score",
+							Description: "VulnerabilityReport is the name of the VulnerabilityReport of the image",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageMetadata contains info about the image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"packageName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackageName is the name of the vulnerable package (empty when Class is \"binary\")",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"packagePath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackagePath is the path where the package was found",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"purl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PURL (Package URL) identify the package uniquely",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"installedVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InstalledVersion of the package that was found",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fixedVersions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FixedVersions is the list of versions where the vulnerability is fixed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity rating (e.g., \"HIGH\", \"MEDIUM\")",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"suppressed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suppressed identify when vulnerability has been suppressed by VEX documents",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
-				Required: []string{"v3vector", "v3score"},
+				Required: []string{"namespace", "vulnerabilityReport", "imageMetadata", "purl", "installedVersion", "severity", "suppressed"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_Image(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_CVE(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Image is the Schema for the images API",
+				Description: "CVE lists the images affected by a vulnerability. It is a read-only resource computed from the VulnerabilityReports, named after the CVE identifier.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -140,79 +238,115 @@ func schema_sbomscanner_api_storage_v1alpha1_Image(ref common.ReferenceCallback)
 							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"imageMetadata": {
+					"title": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Metadata of the image",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+							Description: "Title is the title of the vulnerability",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"layers": {
+					"description": {
 						SchemaProps: spec.SchemaProps{
-							Description: "List of the layers that make the image",
+							Description: "Description of the vulnerability",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"references": {
+						SchemaProps: spec.SchemaProps{
+							Description: "References contains URLs for more information",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"cvss": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CVSS scoring details",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageLayer"),
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS"),
+									},
+								},
+							},
+						},
+					},
+					"affectedImages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AffectedImages lists the vulnerable packages found in the images",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.AffectedImage"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"imageMetadata"},
+				Required: []string{"affectedImages"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageLayer", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.AffectedImage", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_ImageLayer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_CVECount(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageLayer define a layer part of an OCI Image",
+				Description: "CVECount is the number of images affected by a CVE",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"command": {
+					"cve": {
 						SchemaProps: spec.SchemaProps{
-							Description: "command is the command that led to the creation of the layer. The contents are base64 encoded",
+							Description: "CVE identifier",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"digest": {
+					"title": {
 						SchemaProps: spec.SchemaProps{
-							Description: "digest is the Hash of the compressed layer",
-							Default:     "",
+							Description: "Title is the title of the vulnerability",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"diffID": {
+					"images": {
 						SchemaProps: spec.SchemaProps{
-							Description: "diffID is the Hash of the uncompressed layer",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Images is the number of affected images",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
-				Required: []string{"command", "digest", "diffID"},
+				Required: []string{"cve", "images"},
 			},
 		},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_ImageList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_CVEList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageList contains a list of Image",
+				Description: "CVEList contains a list of CVE",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -242,7 +376,7 @@ func schema_sbomscanner_api_storage_v1alpha1_ImageList(ref common.ReferenceCallb
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image"),
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVE"),
 									},
 								},
 							},
@@ -253,168 +387,194 @@ func schema_sbomscanner_api_storage_v1alpha1_ImageList(ref common.ReferenceCallb
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVE", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_ImageMetadata(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_CVSS(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageMetadata contains the metadata details of an image.",
+				Description: "CVSS holds Common Vulnerability Scoring System data for a vulnerability.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"registry": {
+					"v3vector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Registry specifies the name of the Registry object in the same namespace where the image is stored.",
+							Description: "V3Vector string (e.g., \"AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H\")",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"registryURI": {
+					"v3score": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RegistryURI specifies the URI of the registry where the image is stored. Example: \"registry-1.docker.io:5000\".`",
+							Description: "V3Score numerical score",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"repository": {
+				},
+				Required: []string{"v3vector", "v3score"},
+			},
+		},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_ClusterVulnerabilityRollup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterVulnerabilityRollup aggregates the VulnerabilityReports of the images of a namespace. It is a read-only resource computed from the VulnerabilityReports, named after the namespace.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Repository specifies the repository path of the image. Example: \"kubewarden/sbomscanner\".",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"tag": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Tag specifies the tag of the image. Example: \"latest\".",
-							Default:     "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"platform": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Platform specifies the platform of the image. Example \"linux/amd64\".",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"digest": {
+					"rollup": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Digest specifies the sha256 digest of the image.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Rollup holds the aggregated vulnerabilities",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Rollup"),
 						},
 					},
 				},
-				Required: []string{"registry", "registryURI", "repository", "tag", "platform", "digest"},
+				Required: []string{"rollup"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Rollup", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_Report(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_ClusterVulnerabilityRollupList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Report contains metadata about the scanned image and a list of vulnerability results.",
+				Description: "ClusterVulnerabilityRollupList contains a list of ClusterVulnerabilityRollup",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"summary": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Summary of vulnerabilities found",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"results": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Results per target (e.g., layer, package type)",
-							Type:        []string{"array"},
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Result"),
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ClusterVulnerabilityRollup"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"summary", "results"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Result", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ClusterVulnerabilityRollup", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_Result(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_Image(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Result represents scan findings for a specific target and class of packages",
+				Description: "Image is the Schema for the images API",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"target": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Target is the specific target scanned",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"class": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Class is the classification of the target",
-							Default:     "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"type": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Type is the language type",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"vulnerabilities": {
+					"imageMetadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Vulnerabilities found in this target",
+							Description: "Metadata of the image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"layers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "List of the layers that make the image",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Vulnerability"),
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageLayer"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"target", "class", "type", "vulnerabilities"},
+				Required: []string{"imageMetadata"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Vulnerability"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageLayer", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_SBOM(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_ImageBatch(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SBOM represents a Software Bill of Materials of an OCI artifact",
+				Description: "ImageBatch creates or updates a batch of Images of its namespace in a single transaction. It is a create-only resource: the created object is not persisted and the number of written images is returned in its status.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -437,327 +597,1620 @@ func schema_sbomscanner_api_storage_v1alpha1_SBOM(ref common.ReferenceCallback)
 							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"imageMetadata": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+							Description: "Spec holds the images to write",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageBatchSpec"),
 						},
 					},
-					"spdx": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SPDX contains the SPDX document of the SBOM in JSON format",
-							Ref:         ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+							Description: "Status holds the result of the write",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageBatchStatus"),
 						},
 					},
 				},
-				Required: []string{"imageMetadata", "spdx"},
+				Required: []string{"spec"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "k8s.io/apimachinery/pkg/runtime.RawExtension"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageBatchSpec", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageBatchStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_SBOMList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_ImageBatchSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SBOMList contains a list of Software Bill of Materials",
+				Description: "ImageBatchSpec defines the images to write.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"images": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
-						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
-						},
-					},
-					"items": {
-						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "Images are the images to create, or to update if they exist. They must be in the namespace of the batch and have a name. At most MaxImageBatchSize images can be written by a batch.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOM"),
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"items"},
+				Required: []string{"images"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOM", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_Summary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_ImageBatchStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Summary provides a high-level overview of the vulnerabilities found.",
+				Description: "ImageBatchStatus holds the result of the write.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"critical": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Critical vulnerabilities count",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"high": {
-						SchemaProps: spec.SchemaProps{
-							Description: "High vulnerabilities count",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"medium": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Medium vulnerabilities count",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"low": {
+					"created": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Low vulnerabilities count",
+							Description: "Created is the number of images created",
 							Default:     0,
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
-					"unknown": {
+					"updated": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Unknown vulnerabilities count",
+							Description: "Updated is the number of existing images updated",
 							Default:     0,
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
-					"suppressed": {
+					"unchanged": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Suppressed vulnerabilities count",
+							Description: "Unchanged is the number of existing images left as they were",
 							Default:     0,
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
 				},
-				Required: []string{"critical", "high", "medium", "low", "unknown", "suppressed"},
+				Required: []string{"created", "updated", "unchanged"},
 			},
 		},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_VEXStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_ImageLayer(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VEXStatus represents the status of a vulnerability as declared in a VEX document",
+				Description: "ImageLayer define a layer part of an OCI Image",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"repository": {
+					"command": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Repository providing the VEX document",
+							Description: "command is the command that led to the creation of the layer. The contents are base64 encoded",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"status": {
+					"digest": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VEX status (e.g., \"not_affected\", \"fixed\", \"under_investigation\")",
+							Description: "digest is the Hash of the compressed layer",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"statement": {
+					"diffID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Statement optionally explain statement from the VEX document",
+							Description: "diffID is the Hash of the uncompressed layer",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"repository", "status", "statement"},
+				Required: []string{"command", "digest", "diffID"},
 			},
 		},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_Vulnerability(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_ImageList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Vulnerability contains detailed information about a single vulnerability found in a package",
+				Description: "ImageList contains a list of Image",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"cve": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CVE identifier",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"title": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Title is the title of the vulnerability",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"packageName": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "PackageName is the name of the vulnerable package (empty when Class is \"binary\")",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"packagePath": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "PackagePath is the path where the package was found (equal to Target when Class is \"binary\"). trivy removes the \"/\" at the beginning of the path so we have to restore it.",
-							Type:        []string{"string"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image"),
+									},
+								},
+							},
 						},
 					},
-					"purl": {
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Image", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_ImageMetadata(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageMetadata contains the metadata details of an image.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"registry": {
 						SchemaProps: spec.SchemaProps{
-							Description: "PURL (Package URL) identify the package uniquely",
+							Description: "Registry specifies the name of the Registry object in the same namespace where the image is stored.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"installedVersion": {
+					"registryURI": {
 						SchemaProps: spec.SchemaProps{
-							Description: "InstalledVersion of the package that was found",
+							Description: "RegistryURI specifies the URI of the registry where the image is stored. Example: \"registry-1.docker.io:5000\".`",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"fixedVersions": {
+					"repository": {
 						SchemaProps: spec.SchemaProps{
-							Description: "FixedVersions is the list of versions where the vulnerability is fixed",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Repository specifies the repository path of the image. Example: \"kubewarden/sbomscanner\".",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"diffID": {
+					"tag": {
 						SchemaProps: spec.SchemaProps{
-							Description: "DiffID of the image layer where the vulnerability was introduced",
+							Description: "Tag specifies the tag of the image. Example: \"latest\".",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"description": {
+					"platform": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Description of the vulnerability",
+							Description: "Platform specifies the platform of the image. Example \"linux/amd64\".",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"severity": {
+					"digest": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Severity rating (e.g., \"HIGH\", \"MEDIUM\")",
+							Description: "Digest specifies the sha256 digest of the image.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"references": {
+				},
+				Required: []string{"registry", "registryURI", "repository", "tag", "platform", "digest"},
+			},
+		},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_PackageChange(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageChange is a package that changed from the base SBOM to the target one.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "References contains URLs for more information",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Name is the name of the package",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"cvss": {
+					"purl": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CVSS scoring details",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS"),
-									},
-								},
-							},
+							Description: "PURL (Package URL) identify the package uniquely, as listed by the target SBOM, or by the base one for the removed packages",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"suppressed": {
+					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Suppressed identify when vulnerability has been suppressed by VEX documents",
-							Default:     false,
-							Type:        []string{"boolean"},
+							Description: "Version of the package in the target SBOM, unset for the removed packages",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"vexStatus": {
+					"previousVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VEXStatus information",
-							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus"),
+							Description: "PreviousVersion is the version of the package in the base SBOM, unset for the added packages",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"cve", "purl", "installedVersion", "diffID", "severity", "suppressed"},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_PackageMatch(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VulnerabilityReport is the Schema for the scanresults API",
+				Description: "PackageMatch is a package found in an image",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the image",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the name of the SBOM listing the package, which is the name of the Image and of its VulnerabilityReport too",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageMetadata contains info about the image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"packageName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackageName is the name of the package",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version of the package",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"purl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PURL (Package URL) identify the package uniquely",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespace", "image", "imageMetadata", "packageName", "version"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_PackageSearch(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageSearch searches the packages listed in the SBOMs. It is a create-only resource: the created object is not persisted and the matching packages are returned in its status.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spec holds the search criteria",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageSearchSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status holds the result of the search",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageSearchStatus"),
+						},
+					},
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageSearchSpec", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageSearchStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_PackageSearchSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageSearchSpec defines the search criteria. At least one of Name and PURLPrefix must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace restricts the search to the images of the namespace. All the namespaces are searched if empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the exact name of the package",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"purlPrefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PURLPrefix is a prefix of the PURL (Package URL) of the package. Example: \"pkg:maven/org.apache.logging.log4j/log4j-core\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"versionConstraint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VersionConstraint is a constraint on the version of the package. Example: \"< 2.17\", \">= 5.6.0, < 5.6.2\". Packages whose version is not a semantic version do not match any constraint.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"limit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limit is the maximum number of matches to return. All the matches are returned if zero.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"continue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Continue is the continue token of the status of a previous search with the same criteria, to return the matches following the ones it returned.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_PackageSearchStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageSearchStatus holds the result of the search.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"matches": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Matches lists the packages matching the criteria",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageMatch"),
+									},
+								},
+							},
+						},
+					},
+					"continue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Continue is set if there are more matches than the limit. The search is continued by setting it in the spec of a new search.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"matches"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageMatch"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_Report(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Report contains metadata about the scanned image and a list of vulnerability results.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary of vulnerabilities found",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"),
+						},
+					},
+					"results": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Results per target (e.g., layer, package type)",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Result"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"summary", "results"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Result", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_ReportChange(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ReportChange lists the vulnerabilities that changed between two scans of an image. A vulnerability is identified by its CVE, package name and PURL.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"added": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Added lists the vulnerabilities found by the scan and not by the previous one",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityChange"),
+									},
+								},
+							},
+						},
+					},
+					"resolved": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resolved lists the vulnerabilities found by the previous scan and no longer by the scan",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityChange"),
+									},
+								},
+							},
+						},
+					},
+					"changed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Changed lists the vulnerabilities found by both scans, whose severity or VEX status changed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityChange"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityChange"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_Result(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Result represents scan findings for a specific target and class of packages",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target is the specific target scanned",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"class": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Class is the classification of the target",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the language type",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"vulnerabilities": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Vulnerabilities found in this target",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Vulnerability"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"target", "class", "type", "vulnerabilities"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Vulnerability"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_Rollup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Rollup holds the vulnerabilities aggregated over a set of images.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Images is the number of scanned images",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary is the sum of the summaries of the VulnerabilityReports of the images",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"),
+						},
+					},
+					"fixable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Fixable is the number of vulnerabilities, not suppressed, with a fixed version available",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"fixablePercentage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FixablePercentage is the percentage, rounded down, of the vulnerabilities not suppressed with a fixed version available",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"topCVEs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TopCVEs lists the 10 CVEs, not suppressed, affecting the most images",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVECount"),
+									},
+								},
+							},
+						},
+					},
+					"mostVulnerableImages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MostVulnerableImages lists the 10 images with the most critical, then high, vulnerabilities",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerableImage"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"images", "summary", "fixable", "fixablePercentage", "topCVEs", "mostVulnerableImages"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVECount", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerableImage"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_SBOM(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SBOM represents a Software Bill of Materials of an OCI artifact",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"imageMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"spdx": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SPDX contains the SPDX document of the SBOM in JSON format",
+							Ref:         ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+						},
+					},
+				},
+				Required: []string{"imageMetadata", "spdx"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_SBOMComparison(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SBOMComparison compares the packages of two SBOMs of its namespace, such as the SBOMs of two digests of a tag or of two tags of a repository. It is a create-only resource: the created object is not persisted and the package changes are returned in its status.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spec holds the SBOMs to compare",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMComparisonSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status holds the result of the comparison",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMComparisonStatus"),
+						},
+					},
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMComparisonSpec", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOMComparisonStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_SBOMComparisonSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SBOMComparisonSpec defines the SBOMs to compare.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"base": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base is the name of the SBOM the packages are compared from, usually the one of the older image",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target is the name of the SBOM the packages are compared to",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"base", "target"},
+			},
+		},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_SBOMComparisonStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SBOMComparisonStatus holds the packages that changed from the base SBOM to the target one. A package is identified by its name and its PURL without the version and the qualifiers. The packages are sorted by name and PURL.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"base": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base contains info about the image of the base SBOM",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target contains info about the image of the target SBOM",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"added": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Added lists the packages of the target SBOM missing from the base one",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageChange"),
+									},
+								},
+							},
+						},
+					},
+					"removed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Removed lists the packages of the base SBOM missing from the target one",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageChange"),
+									},
+								},
+							},
+						},
+					},
+					"upgraded": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Upgraded lists the packages whose version is higher in the target SBOM",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageChange"),
+									},
+								},
+							},
+						},
+					},
+					"downgraded": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Downgraded lists the packages whose version is lower in the target SBOM",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageChange"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"base", "target"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.PackageChange"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_SBOMList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SBOMList contains a list of Software Bill of Materials",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOM"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.SBOM", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_Summary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Summary provides a high-level overview of the vulnerabilities found.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"critical": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Critical vulnerabilities count",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"high": {
+						SchemaProps: spec.SchemaProps{
+							Description: "High vulnerabilities count",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"medium": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Medium vulnerabilities count",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"low": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Low vulnerabilities count",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"unknown": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Unknown vulnerabilities count",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"suppressed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suppressed vulnerabilities count",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"critical", "high", "medium", "low", "unknown", "suppressed"},
+			},
+		},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VEXStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VEXStatus represents the status of a vulnerability as declared in a VEX document",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"repository": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Repository providing the VEX document",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VEX status (e.g., \"not_affected\", \"fixed\", \"under_investigation\")",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"statement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Statement optionally explain statement from the VEX document",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"repository", "status", "statement"},
+			},
+		},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_Vulnerability(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Vulnerability contains detailed information about a single vulnerability found in a package",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cve": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CVE identifier",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"title": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Title is the title of the vulnerability",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"packageName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackageName is the name of the vulnerable package (empty when Class is \"binary\")",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"packagePath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackagePath is the path where the package was found (equal to Target when Class is \"binary\"). trivy removes the \"/\" at the beginning of the path so we have to restore it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"purl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PURL (Package URL) identify the package uniquely",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"installedVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InstalledVersion of the package that was found",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fixedVersions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FixedVersions is the list of versions where the vulnerability is fixed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"diffID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DiffID of the image layer where the vulnerability was introduced",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description of the vulnerability",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity rating (e.g., \"HIGH\", \"MEDIUM\")",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"references": {
+						SchemaProps: spec.SchemaProps{
+							Description: "References contains URLs for more information",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"cvss": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CVSS scoring details",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS"),
+									},
+								},
+							},
+						},
+					},
+					"suppressed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suppressed identify when vulnerability has been suppressed by VEX documents",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"vexStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VEXStatus information",
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus"),
+						},
+					},
+					"firstSeen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FirstSeen is the time the vulnerability was first found in the image digest. It is set by the storage and kept across the rescans of the image.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"cve", "purl", "installedVersion", "diffID", "severity", "suppressed"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.CVSS", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityChange(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityChange describes a vulnerability that changed between two scans of an image.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cve": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CVE identifier",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"packageName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackageName is the name of the vulnerable package",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"purl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PURL (Package URL) identify the package uniquely",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity rating of the vulnerability, as found by the previous scan for the resolved vulnerabilities",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"suppressed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suppressed identify when vulnerability has been suppressed by VEX documents",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"vexStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VEXStatus information",
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus"),
+						},
+					},
+					"previousSeverity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreviousSeverity is the severity rating found by the previous scan, set for the changed vulnerabilities",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"previousSuppressed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreviousSuppressed is the suppression found by the previous scan, set for the changed vulnerabilities",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"previousVEXStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreviousVEXStatus is the VEX status found by the previous scan, set for the changed vulnerabilities",
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus"),
+						},
+					},
+				},
+				Required: []string{"cve", "purl", "severity", "suppressed"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VEXStatus"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityHistory(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityHistory is the read-only timeline of the vulnerabilities found in the image digest of a VulnerabilityReport. It has the same name and namespace as the report, and is kept across the rescans overwriting the report.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"imageMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageMetadata contains info about the scanned image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"timeline": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeline lists the vulnerabilities ever found in the image digest, ordered by the time they were first seen",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityTimelineEntry"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"imageMetadata", "timeline"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityTimelineEntry", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityHistoryList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityHistoryList contains a list of VulnerabilityHistory",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityHistory"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityHistory", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityReport is the Schema for the scanresults API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"imageMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageMetadata contains info about the scanned image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"report": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Report is the actual vulnerability scan report",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Report"),
+						},
+					},
+					"lastChange": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastChange is the difference between the report and the report of the previous scan of the image. It is unset until the image is scanned again.",
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ReportChange"),
+						},
+					},
+				},
+				Required: []string{"imageMetadata", "report"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Report", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ReportChange", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReportList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityReportList contains a list of ScanResult",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReport"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReport", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityRollup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityRollup aggregates the VulnerabilityReports of the images of a registry. It is a read-only resource computed from the VulnerabilityReports, named after the Registry and living in its namespace.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"rollup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rollup holds the aggregated vulnerabilities",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Rollup"),
+						},
+					},
+				},
+				Required: []string{"rollup"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Rollup", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityRollupList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityRollupList contains a list of VulnerabilityRollup",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityRollup"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityRollup", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilitySummary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilitySummary is a read-only summary of a VulnerabilityReport. It has the same name and namespace as the report, without the vulnerabilities.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
@@ -784,27 +2237,47 @@ func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReport(ref common.Refe
 							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
 						},
 					},
-					"report": {
+					"summary": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Report is the actual vulnerability scan report",
+							Description: "Summary of vulnerabilities found",
 							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Report"),
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"),
+						},
+					},
+					"fixable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Fixable is the number of vulnerabilities, not suppressed, with a fixed version available",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"firstScannedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FirstScannedAt is the time the image was scanned for the first time",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastScannedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScannedAt is the time the image was scanned for the last time",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
 				},
-				Required: []string{"imageMetadata", "report"},
+				Required: []string{"imageMetadata", "summary", "fixable", "firstScannedAt", "lastScannedAt"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Report", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReportList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilitySummaryList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VulnerabilityReportList contains a list of ScanResult",
+				Description: "VulnerabilitySummaryList contains a list of VulnerabilitySummary",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -834,7 +2307,7 @@ func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReportList(ref common.
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReport"),
+										Ref:     ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilitySummary"),
 									},
 								},
 							},
@@ -845,7 +2318,118 @@ func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityReportList(ref common.
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilityReport", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.VulnerabilitySummary", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerabilityTimelineEntry(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerabilityTimelineEntry records when a vulnerability of a package was found in an image digest.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cve": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CVE identifier",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"packageName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackageName is the name of the vulnerable package",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"purl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PURL (Package URL) identify the package uniquely",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity rating reported by the latest scan finding the vulnerability",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"firstSeen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FirstSeen is the time of the first scan finding the vulnerability",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastSeen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastSeen is the time of the latest scan finding the vulnerability",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"resolvedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedAt is the time of the first scan of the same image digest no longer finding the vulnerability, unset while the vulnerability is found. A vulnerability fixed by a new digest of the image is not resolved in the timeline of the previous digest.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"cve", "purl", "severity", "firstSeen", "lastSeen"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_sbomscanner_api_storage_v1alpha1_VulnerableImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VulnerableImage is the summary of the vulnerabilities of an image",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the VulnerabilityReport",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"vulnerabilityReport": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VulnerabilityReport is the name of the VulnerabilityReport of the image",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageMetadata contains info about the image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata"),
+						},
+					},
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary of vulnerabilities found",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"),
+						},
+					},
+				},
+				Required: []string{"namespace", "vulnerabilityReport", "imageMetadata", "summary"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewarden/sbomscanner/api/storage/v1alpha1.ImageMetadata", "github.com/kubewarden/sbomscanner/api/storage/v1alpha1.Summary"},
 	}
 }
 